@@ -4,7 +4,6 @@ go 1.20
 
 require (
 	github.com/CycloneDX/cyclonedx-go v0.7.1
-	github.com/google/go-cmp v0.5.9
 	github.com/google/uuid v1.3.0
 	github.com/sirupsen/logrus v1.9.3
 	github.com/stretchr/testify v1.8.4
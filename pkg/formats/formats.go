@@ -21,6 +21,19 @@ const (
 	CDX15JSON  = Format("application/vnd.cyclonedx+json;version=1.5")
 	CDXFORMAT  = "cyclonedx"
 	SPDXFORMAT = "spdx"
+
+	// PROTOBOMJSON is the protobom document rendered as protobuf JSON
+	PROTOBOMJSON = Format("application/x-protobom+json;version=1.0")
+
+	// PROTOBOMNDJSON is the protobom document rendered as newline delimited
+	// JSON: one node per line followed by a record with the document graph
+	PROTOBOMNDJSON = Format("application/x-protobom+ndjson;version=1.0")
+
+	// PROTOBOMINVENTORYNDJSON is a node inventory rendered as newline
+	// delimited JSON: each line is a standalone node record with its edges
+	// summarized. It is intended for log and search systems and, unlike
+	// PROTOBOMNDJSON, cannot be read back.
+	PROTOBOMINVENTORYNDJSON = Format("application/x-protobom-inventory+ndjson;version=1.0")
 )
 
 type Document interface{}
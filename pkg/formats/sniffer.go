@@ -2,14 +2,41 @@ package formats
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 )
 
+// gzipMagic is the header that identifies gzip compressed streams
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// maxSniffLineSize is the longest line the sniffer will read
+const maxSniffLineSize = 256 * 1024 * 1024
+
+// specVersionRegex captures the version of CycloneDX JSON documents, both
+// indented and compact
+var specVersionRegex = regexp.MustCompile(`"specVersion"\s*:\s*"([^"]+)"`)
+
 type Sniffer struct{}
 
+// IsGzip returns true if the stream starts with the gzip magic header. The
+// stream is rewound to its beginning before returning.
+func IsGzip(r io.ReadSeeker) (bool, error) {
+	header := make([]byte, len(gzipMagic))
+	n, err := io.ReadFull(r, header)
+	if _, serr := r.Seek(0, io.SeekStart); serr != nil {
+		return false, fmt.Errorf("seeking to beginning of stream: %w", serr)
+	}
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, fmt.Errorf("reading stream header: %w", err)
+	}
+	return n == len(gzipMagic) && bytes.Equal(header, gzipMagic), nil
+}
+
 // SniffFile takes a path an return the format
 func (fs *Sniffer) SniffFile(path string) (Format, error) {
 	f, err := os.Open(path)
@@ -27,28 +54,52 @@ func (fs *Sniffer) SniffReader(f io.ReadSeeker) (Format, error) {
 			fmt.Printf("WARNING: could not seek to beginning of file: %v", err)
 		}
 	}()
-	fileScanner := bufio.NewScanner(f)
+
+	// Compressed streams are sniffed transparently
+	var src io.Reader = f
+	isGzip, err := IsGzip(f)
+	if err != nil {
+		return "", fmt.Errorf("checking stream compression: %w", err)
+	}
+	if isGzip {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return "", fmt.Errorf("opening gzip stream: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	fileScanner := bufio.NewScanner(src)
 	fileScanner.Split(bufio.ScanLines)
+	// Compact JSON documents are written in a single, possibly huge, line
+	fileScanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxSniffLineSize)
 
 	formatType := ""
 	formatEncoding := ""
 	formatVersion := ""
 
+	firstLine := true
 	for fileScanner.Scan() {
+		// Protobom NDJSON streams start with a node or document record
+		// compacted in a single line
+		if firstLine {
+			firstLine = false
+			line := strings.TrimSpace(fileScanner.Text())
+			if strings.HasSuffix(line, "}") && (strings.HasPrefix(line, `{"id":`) ||
+				strings.HasPrefix(line, `{"metadata":`) || strings.HasPrefix(line, `{"nodeList":`)) {
+				return PROTOBOMNDJSON, nil
+			}
+		}
+
 		if strings.Contains(fileScanner.Text(), `"bomFormat"`) && strings.Contains(fileScanner.Text(), `"CycloneDX"`) {
 			formatType = "application/vnd.cyclonedx"
 			formatEncoding = JSON
 		}
 
-		if strings.Contains(fileScanner.Text(), `"specVersion"`) {
-			parts := strings.Split(fileScanner.Text(), ":")
-			if len(parts) == 2 {
-				ver := strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(parts[1]), ","), "\""), "\"")
-				if ver != "" {
-					formatVersion = ver
-					formatEncoding = JSON
-				}
-			}
+		if m := specVersionRegex.FindStringSubmatch(fileScanner.Text()); m != nil {
+			formatVersion = m[1]
+			formatEncoding = JSON
 		}
 
 		if strings.Contains(fileScanner.Text(), "SPDXVersion:") {
@@ -1,7 +1,9 @@
 package formats
 
 import (
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -37,6 +39,13 @@ func TestSniffReader(t *testing.T) {
 			formatType: "spdx",
 			encoding:   "json",
 		},
+		{
+			filename:   "testdata/nginx.spdx.json.gz",
+			mustError:  false,
+			version:    "2.3",
+			formatType: "spdx",
+			encoding:   "json",
+		},
 		{
 			filename:   "testdata/pause.spdx",
 			mustError:  false,
@@ -96,3 +105,30 @@ func TestSniffFile(t *testing.T) {
 		}
 	}
 }
+
+func TestIsGzip(t *testing.T) {
+	for _, tc := range []struct {
+		filename string
+		expected bool
+	}{
+		{"testdata/nginx.spdx.json.gz", true},
+		{"testdata/nginx.spdx.json", false},
+	} {
+		f, err := os.Open(tc.filename)
+		require.NoError(t, err)
+		defer f.Close()
+
+		res, err := IsGzip(f)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, res, tc.filename)
+
+		// The stream must be rewound
+		pos, err := f.Seek(0, io.SeekCurrent)
+		require.NoError(t, err)
+		require.Zero(t, pos)
+	}
+
+	res, err := IsGzip(strings.NewReader(""))
+	require.NoError(t, err)
+	require.False(t, res)
+}
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright 2023 The BOM Squad Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package spdx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// License expression operators
+const (
+	OperatorAND  = "AND"
+	OperatorOR   = "OR"
+	OperatorWITH = "WITH"
+)
+
+// LicenseExpression is a node in a parsed SPDX license expression. Leaf nodes
+// have no operator and carry a license identifier (or LicenseRef) in License.
+// AND and OR nodes carry two or more operands and WITH nodes carry a single
+// operand and the name of the exception.
+type LicenseExpression struct {
+	Operator  string
+	License   string
+	Exception string
+	Operands  []*LicenseExpression
+}
+
+// ParseLicenseExpression parses an SPDX license expression into a tree
+// following the precedence rules of the SPDX spec (WITH > AND > OR).
+// Operators are accepted in all uppercase or all lowercase form.
+//
+// See https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+func ParseLicenseExpression(s string) (*LicenseExpression, error) {
+	p := &licenseParser{tokens: tokenizeLicenseExpression(s)}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("license expression is empty")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in license expression", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// LicenseIDs returns the license identifiers in the expression, without
+// duplicates and in the order in which they appear. Exceptions are not
+// included in the list.
+func (e *LicenseExpression) LicenseIDs() []string {
+	ret := []string{}
+	seen := map[string]struct{}{}
+	var walk func(*LicenseExpression)
+	walk = func(e *LicenseExpression) {
+		if e.Operator == "" {
+			if _, ok := seen[e.License]; !ok {
+				seen[e.License] = struct{}{}
+				ret = append(ret, e.License)
+			}
+			return
+		}
+		for _, o := range e.Operands {
+			walk(o)
+		}
+	}
+	walk(e)
+	return ret
+}
+
+// String renders the expression back into its string form
+func (e *LicenseExpression) String() string {
+	switch e.Operator {
+	case "":
+		return e.License
+	case OperatorWITH:
+		return fmt.Sprintf("%s WITH %s", e.Operands[0].String(), e.Exception)
+	default:
+		parts := []string{}
+		for _, o := range e.Operands {
+			// Nested compound expressions are enclosed in parenthesis
+			if o.Operator == OperatorAND || o.Operator == OperatorOR {
+				parts = append(parts, "("+o.String()+")")
+			} else {
+				parts = append(parts, o.String())
+			}
+		}
+		return strings.Join(parts, " "+e.Operator+" ")
+	}
+}
+
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+// tokenizeLicenseExpression splits the expression into words and parenthesis
+func tokenizeLicenseExpression(s string) []string {
+	s = strings.ReplaceAll(s, "(", " ( ")
+	s = strings.ReplaceAll(s, ")", " ) ")
+	return strings.Fields(s)
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// isOperator returns true if the token is the operator op
+func isOperator(token, op string) bool {
+	return token == op || token == strings.ToLower(op)
+}
+
+func (p *licenseParser) parseOr() (*LicenseExpression, error) {
+	return p.parseCompound(OperatorOR, p.parseAnd)
+}
+
+func (p *licenseParser) parseAnd() (*LicenseExpression, error) {
+	return p.parseCompound(OperatorAND, p.parseWith)
+}
+
+// parseCompound parses a list of operands joined by op
+func (p *licenseParser) parseCompound(op string, next func() (*LicenseExpression, error)) (*LicenseExpression, error) {
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*LicenseExpression{first}
+	for isOperator(p.peek(), op) {
+		p.pos++
+		operand, err := next()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &LicenseExpression{Operator: op, Operands: operands}, nil
+}
+
+func (p *licenseParser) parseWith() (*LicenseExpression, error) {
+	expr, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOperator(p.peek(), OperatorWITH) {
+		return expr, nil
+	}
+	p.pos++
+
+	exception := p.peek()
+	if exception == "" || exception == "(" || exception == ")" || isKeyword(exception) {
+		return nil, errors.New("license exception expected after WITH")
+	}
+	p.pos++
+	return &LicenseExpression{
+		Operator:  OperatorWITH,
+		Exception: exception,
+		Operands:  []*LicenseExpression{expr},
+	}, nil
+}
+
+func (p *licenseParser) parseTerm() (*LicenseExpression, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, errors.New("unexpected end of license expression")
+	case token == "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("unbalanced parenthesis in license expression")
+		}
+		p.pos++
+		return expr, nil
+	case token == ")" || isKeyword(token):
+		return nil, fmt.Errorf("unexpected token %q in license expression", token)
+	default:
+		p.pos++
+		return &LicenseExpression{License: token}, nil
+	}
+}
+
+// isKeyword returns true if the token is one of the expression operators
+func isKeyword(token string) bool {
+	for _, op := range []string{OperatorAND, OperatorOR, OperatorWITH} {
+		if isOperator(token, op) {
+			return true
+		}
+	}
+	return false
+}
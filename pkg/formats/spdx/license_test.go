@@ -0,0 +1,44 @@
+package spdx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLicenseExpression(t *testing.T) {
+	for expr, tc := range map[string]struct {
+		mustErr  bool
+		ids      []string
+		rendered string
+	}{
+		"MIT":                 {ids: []string{"MIT"}, rendered: "MIT"},
+		"GPL-2.0-only OR MIT": {ids: []string{"GPL-2.0-only", "MIT"}, rendered: "GPL-2.0-only OR MIT"},
+		"Apache-2.0 AND MIT OR BSD-3-Clause": {
+			ids: []string{"Apache-2.0", "MIT", "BSD-3-Clause"}, rendered: "(Apache-2.0 AND MIT) OR BSD-3-Clause",
+		},
+		"GPL-2.0-or-later WITH Classpath-exception-2.0 AND MIT": {
+			ids: []string{"GPL-2.0-or-later", "MIT"}, rendered: "GPL-2.0-or-later WITH Classpath-exception-2.0 AND MIT",
+		},
+		"(MIT OR Apache-2.0) AND (MIT OR ISC)": {
+			ids: []string{"MIT", "Apache-2.0", "ISC"}, rendered: "(MIT OR Apache-2.0) AND (MIT OR ISC)",
+		},
+		"mit or LicenseRef-custom":    {ids: []string{"mit", "LicenseRef-custom"}, rendered: "mit OR LicenseRef-custom"},
+		"":                            {mustErr: true},
+		"MIT OR":                      {mustErr: true},
+		"(MIT AND Apache-2.0":         {mustErr: true},
+		"MIT Apache-2.0":              {mustErr: true},
+		"GPL-2.0-only WITH":           {mustErr: true},
+		"AND MIT":                     {mustErr: true},
+		"MIT WITH Classpath OR (ISC)": {ids: []string{"MIT", "ISC"}, rendered: "MIT WITH Classpath OR ISC"},
+	} {
+		res, err := ParseLicenseExpression(expr)
+		if tc.mustErr {
+			require.Error(t, err, expr)
+			continue
+		}
+		require.NoError(t, err, expr)
+		require.Equal(t, tc.ids, res.LicenseIDs(), expr)
+		require.Equal(t, tc.rendered, res.String(), expr)
+	}
+}
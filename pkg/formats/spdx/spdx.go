@@ -3,7 +3,11 @@
 
 package spdx
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 const (
 	DOCUMENT     = "DOCUMENT"
@@ -23,6 +27,38 @@ const (
 	ExtRefTypeCPE22  = "cpe22Type"
 	ExtRefTypeCPE23  = "cpe23Type"
 	ExtRefTypeGitoid = "gitoid"
+
+	// Non-identifier external reference types
+	ExtRefTypeAdvisory     = "advisory"
+	ExtRefTypeFix          = "fix"
+	ExtRefTypeURL          = "url"
+	ExtRefTypeSwid         = "swid"
+	ExtRefTypeMavenCentral = "maven-central"
+	ExtRefTypeNpm          = "npm"
+	ExtRefTypeNuget        = "nuget"
+	ExtRefTypeBower        = "bower"
+	ExtRefTypeSwh          = "swh"
+
+	// Primary package purposes
+	PurposeApplication     = "APPLICATION"
+	PurposeFramework       = "FRAMEWORK"
+	PurposeLibrary         = "LIBRARY"
+	PurposeContainer       = "CONTAINER"
+	PurposeOperatingSystem = "OPERATING-SYSTEM"
+	PurposeDevice          = "DEVICE"
+	PurposeFirmware        = "FIRMWARE"
+	PurposeSource          = "SOURCE"
+	PurposeArchive         = "ARCHIVE"
+	PurposeFile            = "FILE"
+	PurposeInstall         = "INSTALL"
+	PurposeOther           = "OTHER"
+
+	// AnnotationTypeOther is the annotation type used to record node
+	// properties. Their comments hold the property as "key=value"
+	AnnotationTypeOther = "OTHER"
+
+	// PropertyAnnotator is the tool recorded as annotator of node properties
+	PropertyAnnotator = "protobom"
 )
 
 // ParseActorString parses an SPDX "actor string", it is a specially formatted
@@ -52,3 +88,46 @@ func ParseActorString(s string) (actorType, actorName, actorEmail string) {
 
 	return actorType, actorName, actorEmail
 }
+
+// ParseToolString splits an SPDX tool creator string in the
+// "toolidentifier-version" form into the tool name and version. The version
+// is the part after the last dash when it starts with a digit (optionally
+// prefixed with a "v"), otherwise the whole string is returned as the name.
+func ParseToolString(s string) (name, version string) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndex(s, "-")
+	if i <= 0 || i == len(s)-1 {
+		return s, ""
+	}
+	v := strings.TrimPrefix(s[i+1:], "v")
+	if v == "" || v[0] < '0' || v[0] > '9' {
+		return s, ""
+	}
+	return s[:i], s[i+1:]
+}
+
+// dateLayouts are the time formats accepted by ParseDate, in order of preference
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// ParseDate parses a date from an SPDX document. The spec requires dates in
+// the UTC RFC3339 "YYYY-MM-DDThh:mm:ssZ" form but in the wild, documents with
+// timestamps without a timezone or only with the date are common. Dates
+// without a timezone are assumed to be in UTC.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse date %q", s)
+}
+
+// FormatDate returns the time formatted as required by the SPDX spec
+func FormatDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
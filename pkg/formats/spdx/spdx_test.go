@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: Copyright 2023 The BOM Squad Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package spdx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	for _, tc := range []struct {
+		date      string
+		expected  time.Time
+		shouldErr bool
+	}{
+		{"2023-05-10T14:30:00Z", time.Date(2023, 5, 10, 14, 30, 0, 0, time.UTC), false},
+		{"2023-05-10T14:30:00.5Z", time.Date(2023, 5, 10, 14, 30, 0, 500000000, time.UTC), false},
+		{"2023-05-10T16:30:00+02:00", time.Date(2023, 5, 10, 14, 30, 0, 0, time.UTC), false},
+		{"2023-05-10T14:30:00", time.Date(2023, 5, 10, 14, 30, 0, 0, time.UTC), false},
+		{"2023-05-10", time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC), false},
+		{" 2023-05-10 ", time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC), false},
+		{"10/05/2023", time.Time{}, true},
+		{"", time.Time{}, true},
+	} {
+		res, err := ParseDate(tc.date)
+		if tc.shouldErr {
+			require.Error(t, err, tc.date)
+			continue
+		}
+		require.NoError(t, err, tc.date)
+		require.True(t, tc.expected.Equal(res), tc.date)
+		require.Equal(t, tc.expected.UTC().Format(time.RFC3339), FormatDate(res))
+	}
+}
+
+func TestParseToolString(t *testing.T) {
+	for _, tc := range []struct {
+		tool, name, version string
+	}{
+		{"protobom-v0.2.0", "protobom", "v0.2.0"},
+		{"syft-0.84.1", "syft", "0.84.1"},
+		{"spdx-sbom-generator-0.0.15", "spdx-sbom-generator", "0.0.15"},
+		{"bom-devel", "bom-devel", ""},
+		{"trivy", "trivy", ""},
+		{"tool-", "tool-", ""},
+		{" syft-1.0 ", "syft", "1.0"},
+	} {
+		name, version := ParseToolString(tc.tool)
+		require.Equal(t, tc.name, name, tc.tool)
+		require.Equal(t, tc.version, version, tc.tool)
+	}
+}
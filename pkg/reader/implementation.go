@@ -39,6 +39,8 @@ func (dpi *defaultParserImplementation) GetUnserializer(_ *options.Options, form
 		return &UnserializerSPDX23{}, nil
 	case "application/vnd.cyclonedx+json;version=1.4":
 		return &UnserializerCDX14{}, nil
+	case string(formats.PROTOBOMNDJSON):
+		return &UnserializerNDJSON{}, nil
 	default:
 		return nil, fmt.Errorf("no format parser registered for %s", format)
 	}
@@ -0,0 +1,180 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bom-squad/protobom/pkg/reader/options"
+)
+
+// Default limits of the reader. They are meant to be large enough for any
+// real world SBOM while stopping documents crafted to exhaust memory.
+const (
+	DefaultMaxNodes      = 1_000_000
+	DefaultMaxEdges      = 5_000_000
+	DefaultMaxDepth      = 256
+	DefaultMaxInputBytes = 1 << 30
+)
+
+// ErrLimitExceeded is returned when a document exceeds one of the limits
+// configured in the reader options
+var ErrLimitExceeded = errors.New("document exceeds reader limits")
+
+// hasLimits returns true if any of the size limits is set
+func hasLimits(opts *options.Options) bool {
+	return opts != nil && (opts.MaxNodes > 0 || opts.MaxEdges > 0 || opts.MaxDepth > 0 || opts.MaxInputBytes > 0)
+}
+
+// readAllLimited reads r to memory failing if it is larger than limit bytes.
+// A limit of zero reads the whole stream.
+func readAllLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("input is larger than %d bytes: %w", limit, ErrLimitExceeded)
+	}
+	return data, nil
+}
+
+// limitChecker scans JSON documents counting the elements of the arrays
+// that hold nodes and edges so that limits can be enforced before the
+// document is unmarshaled.
+type limitChecker struct {
+	opts     *options.Options
+	nodeKeys map[string]struct{}
+	edgeKeys map[string]struct{}
+	nodes    int
+	edges    int
+}
+
+// newLimitChecker returns a checker that counts the elements of arrays in
+// any of nodeKeys as nodes and those in any of edgeKeys as edges
+func newLimitChecker(opts *options.Options, nodeKeys, edgeKeys []string) *limitChecker {
+	lc := &limitChecker{
+		opts:     opts,
+		nodeKeys: map[string]struct{}{},
+		edgeKeys: map[string]struct{}{},
+	}
+	for _, k := range nodeKeys {
+		lc.nodeKeys[k] = struct{}{}
+	}
+	for _, k := range edgeKeys {
+		lc.edgeKeys[k] = struct{}{}
+	}
+	return lc
+}
+
+// addNodes adds to the node count, failing when it goes over the limit
+func (lc *limitChecker) addNodes(n int) error {
+	lc.nodes += n
+	if lc.opts.MaxNodes > 0 && lc.nodes > lc.opts.MaxNodes {
+		return fmt.Errorf("document has more than %d nodes: %w", lc.opts.MaxNodes, ErrLimitExceeded)
+	}
+	return nil
+}
+
+// addEdges adds to the edge count, failing when it goes over the limit
+func (lc *limitChecker) addEdges(n int) error {
+	lc.edges += n
+	if lc.opts.MaxEdges > 0 && lc.edges > lc.opts.MaxEdges {
+		return fmt.Errorf("document has more than %d edges: %w", lc.opts.MaxEdges, ErrLimitExceeded)
+	}
+	return nil
+}
+
+// limitFrame is an open object or array while scanning a JSON document
+type limitFrame struct {
+	isArray bool
+	// expectKey is true when the next token in an object is a key
+	expectKey bool
+	// add counts the elements of the array against a limit
+	add func(int) error
+}
+
+// checkJSON tokenizes a JSON document and returns an error as soon as it
+// goes over one of the limits. Syntax errors are left to the decoder.
+func (lc *limitChecker) checkJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	stack := []*limitFrame{}
+	key := ""
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// io.EOF is the end of the document, anything else is left
+			// for the unserializer to report
+			return nil
+		}
+
+		var top *limitFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		if top != nil && !top.isArray && top.expectKey {
+			key, _ = tok.(string) //nolint:errcheck
+			top.expectKey = false
+			continue
+		}
+
+		// tok is the start of a value
+		if top != nil {
+			if top.isArray && top.add != nil {
+				if err := top.add(1); err != nil {
+					return err
+				}
+			}
+			if !top.isArray {
+				top.expectKey = true
+			}
+		}
+
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			continue
+		}
+
+		frame := &limitFrame{isArray: delim == '[', expectKey: delim == '{'}
+		if frame.isArray && top != nil && !top.isArray {
+			if _, ok := lc.nodeKeys[key]; ok {
+				frame.add = lc.addNodes
+			} else if _, ok := lc.edgeKeys[key]; ok {
+				frame.add = lc.addEdges
+			}
+		}
+		stack = append(stack, frame)
+
+		if lc.opts.MaxDepth > 0 && len(stack) > lc.opts.MaxDepth {
+			return fmt.Errorf("document is nested deeper than %d levels: %w", lc.opts.MaxDepth, ErrLimitExceeded)
+		}
+	}
+}
+
+// checkJSONLimits reads a JSON document from r enforcing the limits in
+// opts and returns a reader with its contents
+func checkJSONLimits(opts *options.Options, r io.Reader, nodeKeys, edgeKeys []string) (io.Reader, error) {
+	data, err := readAllLimited(r, opts.MaxInputBytes)
+	if err != nil {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+
+	if err := newLimitChecker(opts, nodeKeys, edgeKeys).checkJSON(data); err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
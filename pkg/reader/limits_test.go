@@ -0,0 +1,115 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/reader/options"
+)
+
+func TestUnserializerLimits(t *testing.T) {
+	// The document is truncated after the third component: it can only be
+	// rejected for its size if the limit is checked before decoding it
+	truncatedCDX := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"bom-ref": "a", "type": "library", "name": "a"},
+    {"bom-ref": "b", "type": "library", "name": "b"},
+    {"bom-ref": "c", "type": "library", "name": "c"},`
+
+	nestedCDX := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"bom-ref": "a", "type": "library", "name": "a", "components": [
+      {"bom-ref": "b", "type": "library", "name": "b"}
+    ]}
+  ]
+}`
+
+	// A single dependency entry holding all the edges of the document
+	dependsOnCDX := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"bom-ref": "a", "type": "library", "name": "a"},
+    {"bom-ref": "b", "type": "library", "name": "b"},
+    {"bom-ref": "c", "type": "library", "name": "c"}
+  ],
+  "dependencies": [
+    {"ref": "a", "dependsOn": ["b", "c", "d", "e"]}
+  ]
+}`
+
+	spdxDoc := `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "test",
+  "documentNamespace": "https://example.com/test",
+  "creationInfo": {"created": "2023-01-01T00:00:00Z", "creators": ["Tool: test"]},
+  "packages": [
+    {"SPDXID": "SPDXRef-a", "name": "a", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-b", "name": "b", "downloadLocation": "NOASSERTION"}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-a", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-b"},
+    {"spdxElementId": "SPDXRef-b", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-a"}
+  ]
+}`
+
+	ndjson := `{"id": "a", "name": "a"}
+{"id": "b", "name": "b"}
+{"metadata": {"id": "doc"}, "nodeList": {"edges": [{"from": "a", "to": ["b"]}]}}
+`
+
+	for _, tc := range []struct {
+		name    string
+		u       Unserializer
+		doc     string
+		opts    options.Options
+		message string
+	}{
+		{"cdx max nodes", &UnserializerCDX14{}, truncatedCDX, options.Options{MaxNodes: 2}, "more than 2 nodes"},
+		{"cdx nested components count", &UnserializerCDX14{}, nestedCDX, options.Options{MaxNodes: 1}, "more than 1 nodes"},
+		{"cdx max depth", &UnserializerCDX14{}, nestedCDX, options.Options{MaxDepth: 4}, "deeper than 4 levels"},
+		{"cdx max input bytes", &UnserializerCDX14{}, nestedCDX, options.Options{MaxInputBytes: 10}, "larger than 10 bytes"},
+		{"cdx under the limits", &UnserializerCDX14{}, nestedCDX, options.Options{MaxNodes: 2, MaxDepth: 5}, ""},
+		{"cdx max edges", &UnserializerCDX14{}, dependsOnCDX, options.Options{MaxEdges: 3}, "more than 3 edges"},
+		{"cdx edges under the limit", &UnserializerCDX14{}, dependsOnCDX, options.Options{MaxEdges: 4}, ""},
+		{"spdx max nodes", &UnserializerSPDX23{}, spdxDoc, options.Options{MaxNodes: 1}, "more than 1 nodes"},
+		{"spdx max edges", &UnserializerSPDX23{}, spdxDoc, options.Options{MaxEdges: 1}, "more than 1 edges"},
+		{"spdx under the limits", &UnserializerSPDX23{}, spdxDoc, options.Options{MaxNodes: 2, MaxEdges: 2}, ""},
+		{"ndjson max nodes", &UnserializerNDJSON{}, ndjson, options.Options{MaxNodes: 1}, "more than 1 nodes"},
+		{"ndjson max depth", &UnserializerNDJSON{}, ndjson, options.Options{MaxDepth: 2}, "deeper than 2 levels"},
+		{"ndjson under the limits", &UnserializerNDJSON{}, ndjson, options.Options{MaxNodes: 2, MaxEdges: 1}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			_, err := tc.u.ParseStream(&opts, strings.NewReader(tc.doc))
+			if tc.message == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLimitExceeded)
+			require.Contains(t, err.Error(), tc.message)
+		})
+	}
+}
+
+func TestReaderDefaultLimits(t *testing.T) {
+	r := New()
+	require.Equal(t, DefaultMaxNodes, r.Options.MaxNodes)
+	require.Equal(t, DefaultMaxInputBytes, int(r.Options.MaxInputBytes))
+
+	r = New(WithMaxNodes(10), WithMaxEdges(0))
+	require.Equal(t, 10, r.Options.MaxNodes)
+	require.Zero(t, r.Options.MaxEdges)
+}
@@ -3,4 +3,74 @@
 
 package options
 
-type Options struct{}
+type Options struct {
+	// Strict makes the unserializers fail when the document has data that
+	// protobom does not capture instead of silently discarding it
+	Strict bool `yaml:"strict,omitempty" json:"strict,omitempty"`
+
+	// MaxNodes is the maximum number of elements (components, packages,
+	// files) a document may declare. Zero means no limit.
+	MaxNodes int `yaml:"maxNodes,omitempty" json:"maxNodes,omitempty"`
+
+	// MaxEdges is the maximum number of relationships a document may
+	// declare. Zero means no limit.
+	MaxEdges int `yaml:"maxEdges,omitempty" json:"maxEdges,omitempty"`
+
+	// MaxDepth is the maximum nesting depth of the document structures.
+	// Zero means no limit.
+	MaxDepth int `yaml:"maxDepth,omitempty" json:"maxDepth,omitempty"`
+
+	// MaxInputBytes is the maximum size of the (decompressed) document.
+	// Zero means no limit.
+	MaxInputBytes int64 `yaml:"maxInputBytes,omitempty" json:"maxInputBytes,omitempty"`
+
+	// ComponentFilter keeps only the components with this name and their
+	// dependencies in the parsed document
+	ComponentFilter string `yaml:"componentFilter,omitempty" json:"componentFilter,omitempty"`
+
+	// RepairEdges makes the reader drop the edges pointing to nodes not in
+	// the document and consolidate duplicate edges after parsing
+	RepairEdges bool `yaml:"repairEdges,omitempty" json:"repairEdges,omitempty"`
+
+	// DuplicateIDs sets how the reader handles documents where several
+	// elements share the same ID
+	DuplicateIDs DuplicateIDPolicy `yaml:"duplicateIDs,omitempty" json:"duplicateIDs,omitempty"`
+
+	// Warn is called by the unserializers with each piece of data they
+	// could not import faithfully. Nil discards the warnings.
+	Warn func(Warning) `yaml:"-" json:"-"`
+}
+
+// DuplicateIDPolicy is the handling of nodes with duplicate IDs
+type DuplicateIDPolicy string
+
+const (
+	// DuplicateIDsKeep leaves the nodes with duplicate IDs as parsed
+	DuplicateIDsKeep DuplicateIDPolicy = ""
+
+	// DuplicateIDsError makes parsing fail if two nodes share an ID
+	DuplicateIDsError DuplicateIDPolicy = "error"
+
+	// DuplicateIDsRename gives a unique ID to every node after the first
+	// one with the same ID, see sbom.NodeList.DisambiguateIDs
+	DuplicateIDsRename DuplicateIDPolicy = "rename"
+)
+
+// Warning describes a relationship of the source document that was dropped
+// or could not be mapped to a protobom edge type when importing it
+type Warning struct {
+	// Relationship is the name of the relationship in the source document
+	Relationship string
+
+	// From and To are the IDs of the related elements
+	From string
+	To   string
+
+	// Message explains what happened to the relationship
+	Message string
+}
+
+// String returns a human readable description of the warning
+func (w Warning) String() string {
+	return w.From + " " + w.Relationship + " " + w.To + ": " + w.Message
+}
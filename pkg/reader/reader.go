@@ -4,55 +4,299 @@
 package reader
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/bom-squad/protobom/pkg/formats"
 	"github.com/bom-squad/protobom/pkg/reader/options"
 	"github.com/bom-squad/protobom/pkg/sbom"
 )
 
-var defaultOptions = options.Options{}
+var defaultOptions = options.Options{
+	MaxNodes:      DefaultMaxNodes,
+	MaxEdges:      DefaultMaxEdges,
+	MaxDepth:      DefaultMaxDepth,
+	MaxInputBytes: DefaultMaxInputBytes,
+}
+
+// ErrDuplicateIDs is returned when parsing a document with nodes sharing
+// an ID and the reader is set to fail on duplicate IDs
+var ErrDuplicateIDs = errors.New("document has duplicate node IDs")
 
 type Reader struct {
 	impl    parserImplementation
 	Options options.Options
+
+	// edgeRepairFn receives the report of the edge repairs
+	edgeRepairFn func(*sbom.EdgeRepairReport)
+
+	// warnings are the warnings of the last document parsed
+	warnings []options.Warning
 }
 
+type Option func(*Reader)
+
 // New returns a new Reader with the default options
-func New() *Reader {
-	return &Reader{
+func New(opts ...Option) *Reader {
+	r := &Reader{
 		Options: defaultOptions,
 		impl:    &defaultParserImplementation{},
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithStrict makes the reader return an error when a document has data
+// that cannot be represented in protobom. Only the CycloneDX unserializer
+// supports strict mode at the moment.
+func WithStrict() Option {
+	return func(r *Reader) {
+		r.Options.Strict = true
+	}
+}
+
+// WithMaxNodes sets the maximum number of nodes a document may declare.
+// Zero disables the limit.
+func WithMaxNodes(n int) Option {
+	return func(r *Reader) {
+		r.Options.MaxNodes = n
+	}
+}
+
+// WithMaxEdges sets the maximum number of relationships a document may
+// declare. Zero disables the limit.
+func WithMaxEdges(n int) Option {
+	return func(r *Reader) {
+		r.Options.MaxEdges = n
+	}
+}
+
+// WithMaxDepth sets the maximum nesting depth of the document. Zero
+// disables the limit.
+func WithMaxDepth(n int) Option {
+	return func(r *Reader) {
+		r.Options.MaxDepth = n
+	}
+}
+
+// WithMaxInputBytes sets the maximum size of the document after
+// decompression. Zero disables the limit.
+func WithMaxInputBytes(n int64) Option {
+	return func(r *Reader) {
+		r.Options.MaxInputBytes = n
+	}
+}
+
+// WithComponentFilter makes the reader keep only the components named
+// name and the nodes reachable from them. CycloneDX JSON documents are
+// filtered as they are parsed so the full nodelist is never held in
+// memory, other formats are pruned once parsed. Parsing fails if no
+// component has the name.
+func WithComponentFilter(name string) Option {
+	return func(r *Reader) {
+		r.Options.ComponentFilter = name
+	}
+}
+
+// WithEdgeRepair makes the reader repair the document graph after parsing:
+// edges from or to nodes not in the document are dropped and duplicates
+// merged. If fn is not nil, it is called with the report of the repairs
+// of every parsed document.
+func WithEdgeRepair(fn func(*sbom.EdgeRepairReport)) Option {
+	return func(r *Reader) {
+		r.Options.RepairEdges = true
+		r.edgeRepairFn = fn
+	}
+}
+
+// WithDuplicateIDs sets how the reader handles nodes sharing the same ID.
+// By default they are kept as parsed, which breaks lookups by ID.
+func WithDuplicateIDs(policy options.DuplicateIDPolicy) Option {
+	return func(r *Reader) {
+		r.Options.DuplicateIDs = policy
+	}
+}
+
+// Warnings returns the warnings recorded by the unserializer while parsing
+// the last document, such as relationships that were dropped or could not
+// be mapped to an edge type. Use Options.Warn to receive them as they
+// happen.
+func (r *Reader) Warnings() []options.Warning {
+	return append([]options.Warning{}, r.warnings...)
 }
 
 // ParseFile reads a file and returns an sbom.Document
 func (r *Reader) ParseFile(path string) (*sbom.Document, error) {
+	return r.ParseFileContext(context.Background(), path)
+}
+
+// ParseFileContext works as ParseFile but aborts the parsing and returns
+// the context error when ctx is canceled.
+func (r *Reader) ParseFileContext(ctx context.Context, path string) (*sbom.Document, error) {
 	f, err := r.impl.OpenDocumentFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("opening SBOM file: %w", err)
 	}
 	defer f.Close()
 
-	return r.ParseStream(f)
+	return r.ParseStreamContext(ctx, f)
 }
 
-// ParseStream returns a document from a io reader
+// ParseStream returns a document from a io reader. Gzip compressed streams
+// are decompressed transparently.
 func (r *Reader) ParseStream(f io.ReadSeeker) (*sbom.Document, error) {
+	return r.ParseStreamContext(context.Background(), f)
+}
+
+// ParseStreamContext works as ParseStream but aborts the parsing and
+// returns the context error when ctx is canceled. Cancellation is noticed
+// while the stream is read and between the parsing stages.
+func (r *Reader) ParseStreamContext(ctx context.Context, f io.ReadSeeker) (*sbom.Document, error) {
+	return r.parseStream(ctx, f, "")
+}
+
+// DocumentFromReader parses a document of format f from an io.Reader, for
+// example the body of an HTTP response. The stream is read to memory and
+// its format is detected, if it does not match the declared format an
+// error is returned.
+func DocumentFromReader(rd io.Reader, f formats.Format, opts ...Option) (*sbom.Document, error) {
+	if f == "" {
+		return nil, errors.New("no format declared")
+	}
+
+	r := New(opts...)
+	data, err := readAllLimited(rd, r.Options.MaxInputBytes)
+	if err != nil {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+	return r.parseStream(context.Background(), bytes.NewReader(data), f)
+}
+
+// parseStream detects the format of the stream and parses it. When
+// declared is not empty, the detected format must match it.
+func (r *Reader) parseStream(ctx context.Context, f io.ReadSeeker, declared formats.Format) (*sbom.Document, error) {
+	// Reads from the stream fail once the context is canceled
+	f = &ctxReadSeeker{ctx: ctx, r: f}
+
+	isGzip, err := formats.IsGzip(f)
+	if err != nil {
+		return nil, fmt.Errorf("checking stream compression: %w", err)
+	}
+
+	if isGzip {
+		// The stream needs to be seekable to detect the format so the
+		// document is decompressed to memory
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip stream: %w", err)
+		}
+		data, err := readAllLimited(gz, r.Options.MaxInputBytes)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing stream: %w", err)
+		}
+		f = &ctxReadSeeker{ctx: ctx, r: bytes.NewReader(data)}
+	}
+
 	format, err := r.impl.DetectFormat(&r.Options, f)
 	if err != nil {
 		return nil, fmt.Errorf("detecting SBOM format: %w", err)
 	}
 
+	if declared != "" && format != declared {
+		return nil, fmt.Errorf("document format %s does not match the declared format %s", format, declared)
+	}
+
 	formatParser, err := r.impl.GetUnserializer(&r.Options, format)
 	if err != nil {
 		return nil, fmt.Errorf("getting format parser: %w", err)
 	}
 
-	doc, err := formatParser.ParseStream(&r.Options, f)
+	r.warnings = []options.Warning{}
+	parseOpts := r.Options
+	parseOpts.Warn = func(w options.Warning) {
+		r.warnings = append(r.warnings, w)
+		if r.Options.Warn != nil {
+			r.Options.Warn(w)
+		}
+	}
+
+	// The component filter is applied while parsing when the format
+	// supports it so the nodes left out are never held in memory
+	var doc *sbom.Document
+	fu, filtering := formatParser.(filteringUnserializer)
+	filtering = filtering && r.Options.ComponentFilter != "" && !r.Options.Strict
+	if filtering {
+		doc, err = fu.parseStreamFiltered(&parseOpts, f, r.Options.ComponentFilter)
+	} else {
+		doc, err = formatParser.ParseStream(&parseOpts, f)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("parsing %s document: %w", format, err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch r.Options.DuplicateIDs {
+	case options.DuplicateIDsKeep:
+	case options.DuplicateIDsError:
+		if ids := doc.NodeList.DuplicateIDs(); len(ids) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateIDs, strings.Join(ids, ", "))
+		}
+	case options.DuplicateIDsRename:
+		doc.NodeList.DisambiguateIDs()
+	default:
+		return nil, fmt.Errorf("unknown duplicate ID policy %q", r.Options.DuplicateIDs)
+	}
+
+	if r.Options.RepairEdges {
+		report := doc.NodeList.RepairEdges()
+		if r.edgeRepairFn != nil {
+			r.edgeRepairFn(report)
+		}
+	}
+
+	if r.Options.ComponentFilter != "" {
+		if !filtering {
+			doc.NodeList = doc.NodeList.SubgraphByName(r.Options.ComponentFilter)
+		}
+		if len(doc.NodeList.RootElements) == 0 {
+			return nil, fmt.Errorf("no component named %q in document", r.Options.ComponentFilter)
+		}
+	}
+
 	return doc, err
 }
+
+// filteringUnserializer is implemented by the unserializers that can apply
+// the component filter while they read the document
+type filteringUnserializer interface {
+	parseStreamFiltered(opts *options.Options, r io.ReadSeeker, name string) (*sbom.Document, error)
+}
+
+// ctxReadSeeker is a ReadSeeker that fails once its context is canceled
+type ctxReadSeeker struct {
+	ctx context.Context
+	r   io.ReadSeeker
+}
+
+func (cr *ctxReadSeeker) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+func (cr *ctxReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return cr.r.Seek(offset, whence)
+}
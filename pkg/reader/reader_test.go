@@ -0,0 +1,279 @@
+package reader
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader/options"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+func TestDocumentFromReader(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [{"bom-ref": "lib", "type": "library", "name": "lib"}]
+}`
+
+	bom, err := DocumentFromReader(strings.NewReader(doc), formats.CDX14JSON)
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Nodes, 1)
+	require.Equal(t, "lib", bom.NodeList.Nodes[0].Id)
+
+	_, err = DocumentFromReader(strings.NewReader(doc), formats.SPDX23JSON)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not match the declared format")
+
+	_, err = DocumentFromReader(strings.NewReader(doc), "")
+	require.Error(t, err)
+}
+
+func TestWithComponentFilter(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+  "components": [
+    {"bom-ref": "lib", "type": "library", "name": "lib", "components": [
+      {"bom-ref": "sub", "type": "library", "name": "sub"}
+    ]},
+    {"bom-ref": "other", "type": "library", "name": "other"},
+    {"bom-ref": "dep", "type": "library", "name": "dep"}
+  ],
+  "dependencies": [
+    {"ref": "lib", "dependsOn": ["dep"]},
+    {"ref": "other", "dependsOn": ["dep"]}
+  ],
+  "vulnerabilities": [
+    {"id": "CVE-2023-0001", "affects": [{"ref": "sub"}, {"ref": "other"}]}
+  ]
+}`
+
+	bom, err := DocumentFromReader(strings.NewReader(doc), formats.CDX14JSON, WithComponentFilter("lib"))
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Nodes, 3)
+	require.Equal(t, []string{"lib"}, bom.NodeList.RootElements)
+	require.NotNil(t, bom.NodeList.GetNodeByID("dep"))
+	require.Nil(t, bom.NodeList.GetNodeByID("other"))
+	require.Len(t, bom.NodeList.GetNodeByID("sub").Vulnerabilities, 1)
+
+	// The document is filtered while it is streamed, the result is the
+	// same as pruning the fully parsed document
+	full, err := DocumentFromReader(strings.NewReader(doc), formats.CDX14JSON)
+	require.NoError(t, err)
+	require.True(t, full.NodeList.SubgraphByName("lib").Equal(bom.NodeList))
+
+	_, err = DocumentFromReader(strings.NewReader(doc), formats.CDX14JSON, WithComponentFilter("missing"))
+	require.Error(t, err)
+
+	// Input limits are enforced when streaming
+	_, err = DocumentFromReader(strings.NewReader(doc), formats.CDX14JSON, WithComponentFilter("lib"), WithMaxInputBytes(10))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
+
+func TestWithEdgeRepair(t *testing.T) {
+	doc := `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "test",
+  "documentNamespace": "https://example.com/test",
+  "creationInfo": {"created": "2023-01-01T00:00:00Z", "creators": ["Tool: test"]},
+  "packages": [
+    {"SPDXID": "SPDXRef-a", "name": "a", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-b", "name": "b", "downloadLocation": "NOASSERTION"}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-a", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-b"},
+    {"spdxElementId": "SPDXRef-a", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-missing"}
+  ]
+}`
+
+	bom, err := DocumentFromReader(strings.NewReader(doc), formats.SPDX23JSON)
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Edges, 2)
+
+	var report *sbom.EdgeRepairReport
+	bom, err = DocumentFromReader(
+		strings.NewReader(doc), formats.SPDX23JSON,
+		WithEdgeRepair(func(r *sbom.EdgeRepairReport) { report = r }),
+	)
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Edges, 1)
+	require.Equal(t, []string{"b"}, bom.NodeList.Edges[0].To)
+
+	require.NotNil(t, report)
+	require.Equal(t, []sbom.BrokenEdge{{From: "a", Type: sbom.Edge_dependsOn, To: "missing"}}, report.DroppedTargets)
+	require.Equal(t, 1, report.MergedEdges)
+}
+
+func TestSPDXInverseRelationships(t *testing.T) {
+	for _, tc := range []struct {
+		relationship string
+		expected     sbom.Edge_Type
+	}{
+		{"CONTAINED_BY", sbom.Edge_contains},
+		{"DEPENDENCY_OF", sbom.Edge_dependsOn},
+		{"DESCRIBED_BY", sbom.Edge_describes},
+		{"GENERATED_FROM", sbom.Edge_generates},
+		{"PREREQUISITE_FOR", sbom.Edge_prerequisite},
+	} {
+		t.Run(tc.relationship, func(t *testing.T) {
+			doc := `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "test",
+  "documentNamespace": "https://example.com/test",
+  "creationInfo": {"created": "2023-01-01T00:00:00Z", "creators": ["Tool: test"]},
+  "packages": [
+    {"SPDXID": "SPDXRef-a", "name": "a", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-b", "name": "b", "downloadLocation": "NOASSERTION"}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-a", "relationshipType": "` + tc.relationship + `", "relatedSpdxElement": "SPDXRef-b"}
+  ]
+}`
+			bom, err := DocumentFromReader(strings.NewReader(doc), formats.SPDX23JSON)
+			require.NoError(t, err)
+			require.Len(t, bom.NodeList.Edges, 1)
+			require.Equal(t, tc.expected, bom.NodeList.Edges[0].Type)
+			require.Equal(t, "b", bom.NodeList.Edges[0].From)
+			require.Equal(t, []string{"a"}, bom.NodeList.Edges[0].To)
+		})
+	}
+}
+
+func TestReaderWarnings(t *testing.T) {
+	spdxDoc := `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "test",
+  "documentNamespace": "https://example.com/test",
+  "creationInfo": {"created": "2023-01-01T00:00:00Z", "creators": ["Tool: test"]},
+  "packages": [
+    {"SPDXID": "SPDXRef-a", "name": "a", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-b", "name": "b", "downloadLocation": "NOASSERTION"}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-a", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-b"},
+    {"spdxElementId": "SPDXRef-a", "relationshipType": "VENDORED_BY", "relatedSpdxElement": "SPDXRef-b"}
+  ]
+}`
+	cdxDoc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [{"bom-ref": "a", "type": "library", "name": "a"}],
+  "dependencies": [{"ref": "a", "dependsOn": ["missing"]}]
+}`
+
+	for _, tc := range []struct {
+		name     string
+		doc      string
+		expected []options.Warning
+	}{
+		{
+			name: "spdx unknown relationship",
+			doc:  spdxDoc,
+			expected: []options.Warning{{
+				Relationship: "VENDORED_BY", From: "a", To: "b",
+				Message: "unknown relationship type, imported as an UNKNOWN edge",
+			}},
+		},
+		{
+			name: "cdx dependency on unknown component",
+			doc:  cdxDoc,
+			expected: []options.Warning{{
+				Relationship: "dependsOn", From: "a", To: "missing",
+				Message: "dependency refers to an unknown component, dropped",
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			received := []options.Warning{}
+			r := New()
+			r.Options.Warn = func(w options.Warning) { received = append(received, w) }
+
+			_, err := r.ParseStream(strings.NewReader(tc.doc))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, r.Warnings())
+			require.Equal(t, tc.expected, received)
+		})
+	}
+
+	// Warnings are reset on every parse
+	r := New()
+	_, err := r.ParseStream(strings.NewReader(spdxDoc))
+	require.NoError(t, err)
+	require.Len(t, r.Warnings(), 1)
+	_, err = r.ParseStream(strings.NewReader(strings.ReplaceAll(spdxDoc, "VENDORED_BY", "CONTAINS")))
+	require.NoError(t, err)
+	require.Empty(t, r.Warnings())
+}
+
+func TestDuplicateIDs(t *testing.T) {
+	doc := `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "test",
+  "documentNamespace": "https://example.com/test",
+  "creationInfo": {"created": "2023-01-01T00:00:00Z", "creators": ["Tool: test"]},
+  "packages": [
+    {"SPDXID": "SPDXRef-lib", "name": "lib", "versionInfo": "1.0", "downloadLocation": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-lib", "name": "lib", "versionInfo": "2.0", "downloadLocation": "NOASSERTION"}
+  ]
+}`
+
+	for _, tc := range []struct {
+		policy    options.DuplicateIDPolicy
+		expected  []string
+		shouldErr bool
+	}{
+		{policy: options.DuplicateIDsKeep, expected: []string{"lib", "lib"}},
+		{policy: options.DuplicateIDsError, shouldErr: true},
+		{policy: options.DuplicateIDsRename, expected: []string{"lib", "lib-2"}},
+		{policy: "bogus", shouldErr: true},
+	} {
+		bom, err := New(WithDuplicateIDs(tc.policy)).ParseStream(strings.NewReader(doc))
+		if tc.shouldErr {
+			require.Error(t, err, tc.policy)
+			continue
+		}
+		require.NoError(t, err, tc.policy)
+		ids := []string{}
+		for _, n := range bom.NodeList.Nodes {
+			ids = append(ids, n.Id)
+		}
+		require.Equal(t, tc.expected, ids, tc.policy)
+	}
+
+	_, err := New(WithDuplicateIDs(options.DuplicateIDsError)).ParseStream(strings.NewReader(doc))
+	require.ErrorIs(t, err, ErrDuplicateIDs)
+}
+
+func TestParseStreamContext(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [{"bom-ref": "lib", "type": "library", "name": "lib"}]
+}`
+
+	bom, err := New().ParseStreamContext(context.Background(), strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Nodes, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = New().ParseStreamContext(ctx, strings.NewReader(doc))
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -13,3 +13,10 @@ import (
 type Unserializer interface {
 	ParseStream(*options.Options, io.Reader) (*sbom.Document, error)
 }
+
+// warn passes a warning to the Warn function of the options, if any
+func warn(opts *options.Options, w options.Warning) {
+	if opts != nil && opts.Warn != nil {
+		opts.Warn(w)
+	}
+}
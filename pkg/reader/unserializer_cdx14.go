@@ -1,14 +1,17 @@
 package reader
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	protospdx "github.com/bom-squad/protobom/pkg/formats/spdx"
 	"github.com/bom-squad/protobom/pkg/reader/options"
 	"github.com/bom-squad/protobom/pkg/sbom"
 )
@@ -17,7 +20,26 @@ type UnserializerCDX14 struct{}
 
 // ParseStream reads a CycloneDX 1.4 from stream r usinbg the offcial CycloneDX
 // libraries and returns a protobom document with its data.
-func (u *UnserializerCDX14) ParseStream(_ *options.Options, r io.Reader) (*sbom.Document, error) {
+func (u *UnserializerCDX14) ParseStream(opts *options.Options, r io.Reader) (*sbom.Document, error) {
+	if hasLimits(opts) {
+		var err error
+		r, err = checkJSONLimits(opts, r, []string{"components"}, []string{"dependsOn"})
+		if err != nil {
+			return nil, fmt.Errorf("checking cyclonedx limits: %w", err)
+		}
+	}
+
+	if opts != nil && opts.Strict {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading cyclonedx: %w", err)
+		}
+		if err := checkCDXUnmodeledFields(data); err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
 	bom := new(cdx.BOM)
 	decoder := cdx.NewBOMDecoder(r, cdx.BOMFileFormatJSON)
 	if err := decoder.Decode(bom); err != nil {
@@ -25,19 +47,11 @@ func (u *UnserializerCDX14) ParseStream(_ *options.Options, r io.Reader) (*sbom.
 	}
 
 	doc := &sbom.Document{
-		Metadata: &sbom.Metadata{
-			Id:      bom.SerialNumber,
-			Version: fmt.Sprintf("%d", bom.Version),
-			// Name:    ,
-			Date:    &timestamppb.Timestamp{},
-			Tools:   []*sbom.Tool{},
-			Authors: []*sbom.Person{},
-			// Comment: bom.Com,
-		},
+		Metadata: u.metadataFromCDX(bom),
 		NodeList: &sbom.NodeList{},
 	}
 
-	if bom.Metadata.Component != nil {
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
 		nl, err := u.componentToNodeList(bom.Metadata.Component)
 		if err != nil {
 			return nil, fmt.Errorf("converting main bom component to node: %w", err)
@@ -49,8 +63,12 @@ func (u *UnserializerCDX14) ParseStream(_ *options.Options, r io.Reader) (*sbom.
 	}
 
 	// Cycle all components and get their graph fragments
-	for i := range *bom.Components {
-		nl, err := u.componentToNodeList(&(*bom.Components)[i])
+	components := []cdx.Component{}
+	if bom.Components != nil {
+		components = *bom.Components
+	}
+	for i := range components {
+		nl, err := u.componentToNodeList(&components[i])
 		if err != nil {
 			return nil, fmt.Errorf("converting component to node: %w", err)
 		}
@@ -64,9 +82,119 @@ func (u *UnserializerCDX14) ParseStream(_ *options.Options, r io.Reader) (*sbom.
 		}
 	}
 
+	u.addDependencies(opts, bom, doc.NodeList)
+	u.attachVulnerabilities(bom, doc.NodeList)
+
 	return doc, nil
 }
 
+// metadataFromCDX returns the protobom metadata of the CycloneDX document
+func (u *UnserializerCDX14) metadataFromCDX(bom *cdx.BOM) *sbom.Metadata {
+	md := &sbom.Metadata{
+		Id:      bom.SerialNumber,
+		Version: fmt.Sprintf("%d", bom.Version),
+		// Name:    ,
+		Tools:   []*sbom.Tool{},
+		Authors: []*sbom.Person{},
+		// Comment: bom.Com,
+	}
+
+	if bom.Metadata != nil && bom.Metadata.Timestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, bom.Metadata.Timestamp)
+		if err != nil {
+			logrus.Warnf("invalid metadata timestamp %q", bom.Metadata.Timestamp)
+		} else {
+			md.Date = timestamppb.New(t)
+		}
+	}
+
+	if bom.Metadata != nil && bom.Metadata.Tools != nil {
+		for _, t := range *bom.Metadata.Tools {
+			md.Tools = append(md.Tools, &sbom.Tool{
+				Name:    t.Name,
+				Version: t.Version,
+				Vendor:  t.Vendor,
+			})
+		}
+	}
+
+	if bom.Metadata != nil {
+		md.Properties = propertiesFromCDX(bom.Metadata.Properties)
+	}
+
+	return md
+}
+
+// addDependencies adds the dependency graph of the CycloneDX document to the
+// NodeList as dependsOn edges. Dependencies from or to unknown components
+// are dropped with a warning.
+func (u *UnserializerCDX14) addDependencies(opts *options.Options, bom *cdx.BOM, nl *sbom.NodeList) {
+	if bom.Dependencies == nil {
+		return
+	}
+
+	known := map[string]struct{}{}
+	for _, n := range nl.Nodes {
+		known[n.Id] = struct{}{}
+	}
+
+	edges := []*sbom.Edge{}
+	for _, d := range *bom.Dependencies {
+		if d.Dependencies == nil || len(*d.Dependencies) == 0 {
+			continue
+		}
+		e := &sbom.Edge{Type: sbom.Edge_dependsOn, From: d.Ref, To: []string{}}
+		for _, to := range *d.Dependencies {
+			_, fromOK := known[d.Ref]
+			if _, toOK := known[to]; !fromOK || !toOK {
+				warn(opts, options.Warning{
+					Relationship: "dependsOn",
+					From:         d.Ref,
+					To:           to,
+					Message:      "dependency refers to an unknown component, dropped",
+				})
+				continue
+			}
+			e.To = append(e.To, to)
+		}
+		if len(e.To) > 0 {
+			edges = append(edges, e)
+		}
+	}
+	nl.AddEdges(edges...)
+}
+
+// attachVulnerabilities records the vulnerabilities in the CycloneDX document
+// in the nodes listed in their affects section
+func (u *UnserializerCDX14) attachVulnerabilities(bom *cdx.BOM, nl *sbom.NodeList) {
+	if bom.Vulnerabilities == nil {
+		return
+	}
+
+	for _, v := range *bom.Vulnerabilities {
+		if v.Affects == nil {
+			continue
+		}
+		for _, affected := range *v.Affects {
+			n := nl.GetNodeByID(affected.Ref)
+			if n == nil {
+				logrus.Warnf("vulnerability %s affects unknown component %s", v.ID, affected.Ref)
+				continue
+			}
+
+			// TODO(degradation): Ratings, advisories, affected versions and
+			// other vulnerability data is not captured
+			vuln := &sbom.Vulnerability{Id: v.ID}
+			if v.Analysis != nil {
+				vuln.Status = sbom.VulnerabilityStatusFromCycloneDX(v.Analysis.State)
+				vuln.Justification = string(v.Analysis.Justification)
+				vuln.Detail = v.Analysis.Detail
+			}
+			n.Vulnerabilities = append(n.Vulnerabilities, vuln)
+		}
+	}
+}
+
 // componentToNodes takes a CycloneDX component and computes its graph fragment,
 // returning a nodelist
 func (u *UnserializerCDX14) componentToNodeList(component *cdx.Component) (*sbom.NodeList, error) {
@@ -98,26 +226,37 @@ func (u *UnserializerCDX14) componentToNodeList(component *cdx.Component) (*sbom
 
 func (u *UnserializerCDX14) componentToNode(c *cdx.Component) (*sbom.Node, error) { //nolint:unparam
 	node := &sbom.Node{
-		Id:      c.BOMRef,
-		Type:    sbom.Node_PACKAGE,
-		Name:    c.Name,
-		Version: c.Version,
-		// UrlHome:     "", // Perhaps it would make sense to use the supplier URL here
+		Id:                 c.BOMRef,
+		Type:               sbom.Node_PACKAGE,
+		Name:               c.Name,
+		Version:            c.Version,
+		UrlHome:            "",
 		UrlDownload:        "",
 		Licenses:           u.licenseChoicesToLicenseList(c.Licenses),
 		LicenseConcluded:   u.licenseChoicesToLicenseString(c.Licenses),
 		Copyright:          c.Copyright,
 		Hashes:             map[string]string{},
-		PrimaryPurpose:     string(c.Type),
+		PrimaryPurpose:     sbom.PurposeFromCDXComponentType(c.Type),
 		Description:        c.Description,
 		Attribution:        []string{},
-		Suppliers:          []*sbom.Person{}, // TODO
-		Originators:        []*sbom.Person{}, // TODO
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
 		ExternalReferences: []*sbom.ExternalReference{},
 		Identifiers:        map[int32]string{},
 		FileTypes:          []string{},
 	}
 
+	if c.Supplier != nil {
+		node.Suppliers = append(node.Suppliers, organizationalEntityToPerson(c.Supplier))
+	}
+
+	// CycloneDX authors are a free-form string, we try to extract an
+	// email from them in the "Name (email)" form used by SPDX
+	if c.Author != "" {
+		_, name, email := protospdx.ParseActorString(c.Author)
+		node.Originators = append(node.Originators, &sbom.Person{Name: name, Email: email})
+	}
+
 	// type should be one of
 	// application | framework | library | container | operating-system | device | firmware | file
 	if c.Type == cdx.ComponentTypeFile {
@@ -127,6 +266,21 @@ func (u *UnserializerCDX14) componentToNode(c *cdx.Component) (*sbom.Node, error
 	// External references
 	// "vcs" "issue-tracker" "website"  "advisories" "bom" "mailing-list"  "social"  "chat" "documentation"
 	// "support" "distribution" "license" "build-meta" "build-system" "release-notes" "other"
+	if c.ExternalReferences != nil {
+		for _, er := range *c.ExternalReferences {
+			// The first website and distribution references are mapped to
+			// the node's home and download URLs
+			switch {
+			case er.Type == cdx.ERTypeWebsite && node.UrlHome == "":
+				node.UrlHome = er.URL
+				continue
+			case er.Type == cdx.ERTypeDistribution && node.UrlDownload == "":
+				node.UrlDownload = er.URL
+				continue
+			}
+			node.ExternalReferences = append(node.ExternalReferences, u.externalReferenceToProto(&er))
+		}
+	}
 
 	// Named external references:
 	if c.CPE != "" {
@@ -141,6 +295,8 @@ func (u *UnserializerCDX14) componentToNode(c *cdx.Component) (*sbom.Node, error
 		node.Identifiers[int32(sbom.SoftwareIdentifierType_PURL)] = c.PackageURL
 	}
 
+	node.Properties = propertiesFromCDX(c.Properties)
+
 	if c.Hashes != nil {
 		for _, h := range *c.Hashes {
 			algo := sbom.HashAlgorithmFromCDX(h.Algorithm)
@@ -165,6 +321,46 @@ func (u *UnserializerCDX14) componentToNode(c *cdx.Component) (*sbom.Node, error
 	return node, nil
 }
 
+// propertiesFromCDX converts a list of CycloneDX properties to a map.
+// Returns nil if there are no properties.
+func propertiesFromCDX(props *[]cdx.Property) map[string]string {
+	if props == nil || len(*props) == 0 {
+		return nil
+	}
+	ret := map[string]string{}
+	for _, p := range *props {
+		if _, ok := ret[p.Name]; ok {
+			// TODO(degradation): CycloneDX allows repeated property
+			// names, only the first value is kept
+			continue
+		}
+		ret[p.Name] = p.Value
+	}
+	return ret
+}
+
+// externalReferenceToProto converts a CycloneDX external reference to
+// a protobom external reference
+func (u *UnserializerCDX14) externalReferenceToProto(er *cdx.ExternalReference) *sbom.ExternalReference {
+	ret := &sbom.ExternalReference{
+		Url:           er.URL,
+		ReferenceType: sbom.ExternalReferenceTypeFromCycloneDX(er.Type),
+		Comment:       er.Comment,
+		Hashes:        map[string]string{},
+	}
+
+	if er.Hashes != nil {
+		for _, h := range *er.Hashes {
+			algo := sbom.HashAlgorithmFromCDX(h.Algorithm)
+			if algo == sbom.HashAlgorithm_UNKNOWN {
+				continue
+			}
+			ret.Hashes[algo.String()] = h.Value
+		}
+	}
+	return ret
+}
+
 // licenseChoicesToLicenseList returns a flat list of license strings combining
 // expressions and IDs in one. This function should be part of a license package.
 func (u *UnserializerCDX14) licenseChoicesToLicenseList(lcs *cdx.Licenses) []string {
@@ -176,7 +372,7 @@ func (u *UnserializerCDX14) licenseChoicesToLicenseList(lcs *cdx.Licenses) []str
 		// TODO(license): This should handle licenses without an ID and
 		// create custom licenses or another solution that captures the
 		// full cuistom license text.
-		if lc.Expression == "" && lc.License.ID == "" {
+		if lc.Expression == "" && (lc.License == nil || lc.License.ID == "") {
 			continue
 		}
 
@@ -185,7 +381,6 @@ func (u *UnserializerCDX14) licenseChoicesToLicenseList(lcs *cdx.Licenses) []str
 		} else {
 			list = append(list, lc.License.ID)
 		}
-		return list
 	}
 
 	return list
@@ -200,29 +395,44 @@ func (u *UnserializerCDX14) licenseChoicesToLicenseString(lcs *cdx.Licenses) str
 	if lcs == nil {
 		return ""
 	}
+	list := u.licenseChoicesToLicenseList(lcs)
+	if len(list) == 1 {
+		return list[0]
+	}
+
 	s := ""
-	for _, lc := range *lcs {
-		// TODO(license): This should handle licenses without an ID and
-		// create custom licenses or another solution that captures the
-		// full cuistom license text.
-		if lc.Expression == "" && lc.License.ID == "" {
-			continue
-		}
+	for _, l := range list {
 		if s != "" {
-			s += fmt.Sprintf("(%s) OR ", s)
+			s += " OR "
 		}
+		s += fmt.Sprintf("(%s)", l)
+	}
+	return s
+}
 
-		newLicense := ""
-		if lc.Expression != "" {
-			newLicense = lc.Expression
-		} else {
-			newLicense = lc.License.ID
-		}
-		if s == "" {
-			s = newLicense
-		} else {
-			s += fmt.Sprintf(" (%s)", newLicense)
+// organizationalEntityToPerson converts a CycloneDX organizational entity
+// to a protobom person. The entity is always an organization and its
+// contacts are recorded as the person's contacts.
+func organizationalEntityToPerson(oe *cdx.OrganizationalEntity) *sbom.Person {
+	p := &sbom.Person{
+		Name:     oe.Name,
+		IsOrg:    true,
+		Contacts: []*sbom.Person{},
+	}
+
+	// TODO(degradation): Only the first URL of the entity is kept
+	if oe.URL != nil && len(*oe.URL) > 0 {
+		p.Url = (*oe.URL)[0]
+	}
+
+	if oe.Contact != nil {
+		for _, c := range *oe.Contact {
+			p.Contacts = append(p.Contacts, &sbom.Person{
+				Name:  c.Name,
+				Email: c.Email,
+				Phone: c.Phone,
+			})
 		}
 	}
-	return s
+	return p
 }
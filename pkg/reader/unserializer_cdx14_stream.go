@@ -0,0 +1,360 @@
+package reader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/bom-squad/protobom/pkg/reader/options"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+// StreamCallbacks are the functions the streaming unserializers call as
+// they decode the elements of a document. Returning an error from any of
+// them aborts the parsing. Nil callbacks are skipped.
+type StreamCallbacks struct {
+	// Node is called with every node decoded from the document
+	Node func(*sbom.Node) error
+
+	// Edge is called with every relationship decoded from the document.
+	// Edges are not merged, the same origin and type may be reported more
+	// than once.
+	Edge func(*sbom.Edge) error
+}
+
+// ParseStreamCallback decodes a CycloneDX 1.4 JSON document incrementally,
+// calling the callbacks with each component and dependency as they are
+// read so that large documents can be processed without holding them in
+// memory. The returned document only has the metadata and the root
+// elements, its nodes and edges are only passed to the callbacks.
+//
+// Top level components are related to the metadata component only if it
+// appears before them in the document. Of the reader options only the
+// node and edge limits are enforced, vulnerabilities are not read.
+func (u *UnserializerCDX14) ParseStreamCallback(opts *options.Options, r io.Reader, cb StreamCallbacks) (*sbom.Document, error) {
+	sp := newCDXStreamParser(u, opts, r, cb)
+	if err := sp.parse(); err != nil {
+		return nil, fmt.Errorf("decoding cyclonedx stream: %w", err)
+	}
+
+	sp.doc.Metadata = u.metadataFromCDX(sp.bom)
+	return sp.doc, nil
+}
+
+// parseStreamFiltered parses the document keeping only the components
+// named name and the nodes reachable from them. The stream is read twice:
+// the first pass collects the graph with just the IDs and names of the
+// nodes and the second one keeps the data of the nodes in the subgraph, so
+// the full nodelist is never held in memory.
+func (u *UnserializerCDX14) parseStreamFiltered(opts *options.Options, r io.ReadSeeker, name string) (*sbom.Document, error) {
+	if opts == nil {
+		opts = &options.Options{}
+	}
+
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("getting stream position: %w", err)
+	}
+	if opts.MaxInputBytes > 0 {
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("getting stream size: %w", err)
+		}
+		if end-start > opts.MaxInputBytes {
+			return nil, fmt.Errorf("input is larger than %d bytes: %w", opts.MaxInputBytes, ErrLimitExceeded)
+		}
+		if _, err := r.Seek(start, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("rewinding stream: %w", err)
+		}
+	}
+
+	// Components without a bom-ref get a new random ID each time they are
+	// parsed, so the IDs of the first pass are recorded in emit order and
+	// assigned again to the nodes of the second one.
+	graph := &sbom.NodeList{}
+	ids := []string{}
+	if _, err := u.ParseStreamCallback(opts, r, StreamCallbacks{
+		Node: func(n *sbom.Node) error {
+			ids = append(ids, n.Id)
+			graph.Nodes = append(graph.Nodes, &sbom.Node{Id: n.Id, Name: n.Name})
+			return nil
+		},
+		Edge: func(e *sbom.Edge) error {
+			graph.Edges = append(graph.Edges, e)
+			return nil
+		},
+	}); err != nil {
+		return nil, err
+	}
+	subgraph := graph.SubgraphByName(name)
+	keep := map[string]*sbom.Node{}
+	for _, n := range subgraph.Nodes {
+		keep[n.Id] = nil
+	}
+
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("rewinding stream: %w", err)
+	}
+
+	nl := &sbom.NodeList{RootElements: subgraph.RootElements}
+	seen := 0
+	sp := newCDXStreamParser(u, opts, r, StreamCallbacks{
+		Node: func(n *sbom.Node) error {
+			if seen >= len(ids) {
+				return errors.New("stream changed between passes")
+			}
+			n.Id = ids[seen]
+			seen++
+			existing, ok := keep[n.Id]
+			switch {
+			case !ok:
+			case existing != nil:
+				existing.Augment(n)
+			default:
+				keep[n.Id] = n
+				nl.Nodes = append(nl.Nodes, n)
+			}
+			return nil
+		},
+	})
+	sp.vulnerabilities = true
+	if err := sp.parse(); err != nil {
+		return nil, fmt.Errorf("decoding cyclonedx stream: %w", err)
+	}
+	for id, n := range keep {
+		if n == nil {
+			return nil, fmt.Errorf("node %q of the subgraph was not found in the second pass", id)
+		}
+	}
+	nl.AddEdges(subgraph.Edges...)
+
+	// Vulnerabilities of the components left out are dropped with them
+	if sp.bom.Vulnerabilities != nil {
+		for i := range *sp.bom.Vulnerabilities {
+			v := &(*sp.bom.Vulnerabilities)[i]
+			if v.Affects == nil {
+				continue
+			}
+			affects := []cdx.Affects{}
+			for _, a := range *v.Affects {
+				if _, ok := keep[a.Ref]; ok {
+					affects = append(affects, a)
+				}
+			}
+			v.Affects = &affects
+		}
+	}
+
+	doc := &sbom.Document{
+		Metadata: u.metadataFromCDX(sp.bom),
+		NodeList: nl,
+	}
+	u.attachVulnerabilities(sp.bom, doc.NodeList)
+	return doc, nil
+}
+
+// cdxStreamParser holds the state of a streaming parse
+type cdxStreamParser struct {
+	u      *UnserializerCDX14
+	dec    *json.Decoder
+	cb     StreamCallbacks
+	limits *limitChecker
+	bom    *cdx.BOM
+	doc    *sbom.Document
+
+	// vulnerabilities makes the parser decode the vulnerabilities of the
+	// document into bom instead of skipping them
+	vulnerabilities bool
+}
+
+// newCDXStreamParser returns a parser that reads the document from r
+func newCDXStreamParser(u *UnserializerCDX14, opts *options.Options, r io.Reader, cb StreamCallbacks) *cdxStreamParser {
+	if opts == nil {
+		opts = &options.Options{}
+	}
+	return &cdxStreamParser{
+		u:      u,
+		dec:    json.NewDecoder(r),
+		cb:     cb,
+		limits: newLimitChecker(opts, nil, nil),
+		bom:    &cdx.BOM{},
+		doc:    &sbom.Document{NodeList: &sbom.NodeList{}},
+	}
+}
+
+// parse reads the top level object of the document
+func (sp *cdxStreamParser) parse() error {
+	if err := sp.expectDelim('{'); err != nil {
+		return err
+	}
+
+	for sp.dec.More() {
+		tok, err := sp.dec.Token()
+		if err != nil {
+			return fmt.Errorf("reading key: %w", err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected token %v", tok)
+		}
+
+		switch key {
+		case "serialNumber":
+			err = sp.dec.Decode(&sp.bom.SerialNumber)
+		case "version":
+			err = sp.dec.Decode(&sp.bom.Version)
+		case "metadata":
+			err = sp.parseMetadata()
+		case "components":
+			err = sp.parseArray(sp.parseComponent)
+		case "dependencies":
+			err = sp.parseArray(sp.parseDependency)
+		case "vulnerabilities":
+			if sp.vulnerabilities {
+				err = sp.dec.Decode(&sp.bom.Vulnerabilities)
+				break
+			}
+			var skip json.RawMessage
+			err = sp.dec.Decode(&skip)
+		default:
+			// Anything else is decoded and discarded
+			var skip json.RawMessage
+			err = sp.dec.Decode(&skip)
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", key, err)
+		}
+	}
+
+	return sp.expectDelim('}')
+}
+
+// expectDelim reads the next token, failing if it is not delim
+func (sp *cdxStreamParser) expectDelim(delim json.Delim) error {
+	tok, err := sp.dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %s, found %v", delim, tok)
+	}
+	return nil
+}
+
+// parseArray calls fn to decode each of the elements of an array
+func (sp *cdxStreamParser) parseArray(fn func() error) error {
+	tok, err := sp.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return errors.New("value is not an array")
+	}
+
+	for sp.dec.More() {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return sp.expectDelim(']')
+}
+
+// parseMetadata decodes the metadata and emits the graph of the main
+// component, which becomes the root element of the document
+func (sp *cdxStreamParser) parseMetadata() error {
+	md := &cdx.Metadata{}
+	if err := sp.dec.Decode(md); err != nil {
+		return err
+	}
+	sp.bom.Metadata = md
+	if md.Component == nil {
+		return nil
+	}
+
+	nl, err := sp.u.componentToNodeList(md.Component)
+	if err != nil {
+		return fmt.Errorf("converting main bom component to node: %w", err)
+	}
+	sp.doc.NodeList.RootElements = append(sp.doc.NodeList.RootElements, nl.RootElements...)
+	return sp.emit(nl)
+}
+
+// parseComponent decodes a component and emits its graph fragment
+func (sp *cdxStreamParser) parseComponent() error {
+	c := &cdx.Component{}
+	if err := sp.dec.Decode(c); err != nil {
+		return err
+	}
+
+	nl, err := sp.u.componentToNodeList(c)
+	if err != nil {
+		return fmt.Errorf("converting component to node: %w", err)
+	}
+
+	if len(sp.doc.NodeList.RootElements) > 0 {
+		nl.Edges = append(nl.Edges, &sbom.Edge{
+			Type: sbom.Edge_contains,
+			From: sp.doc.NodeList.RootElements[0],
+			To:   []string{nl.RootElements[0]},
+		})
+	}
+	return sp.emit(nl)
+}
+
+// parseDependency decodes a dependency and emits it as an edge
+func (sp *cdxStreamParser) parseDependency() error {
+	d := &cdx.Dependency{}
+	if err := sp.dec.Decode(d); err != nil {
+		return err
+	}
+	if d.Dependencies == nil || len(*d.Dependencies) == 0 {
+		return nil
+	}
+
+	return sp.emit(&sbom.NodeList{
+		Edges: []*sbom.Edge{{
+			Type: sbom.Edge_dependsOn,
+			From: d.Ref,
+			To:   append([]string{}, *d.Dependencies...),
+		}},
+	})
+}
+
+// emit passes the nodes and edges of a graph fragment to the callbacks
+func (sp *cdxStreamParser) emit(nl *sbom.NodeList) error {
+	if err := sp.limits.addNodes(len(nl.Nodes)); err != nil {
+		return err
+	}
+	// Edges are counted by their destinations as a single edge can hold
+	// all the dependencies of a component
+	to := 0
+	for _, e := range nl.Edges {
+		to += len(e.To)
+	}
+	if err := sp.limits.addEdges(to); err != nil {
+		return err
+	}
+
+	if sp.cb.Node != nil {
+		for _, n := range nl.Nodes {
+			if err := sp.cb.Node(n); err != nil {
+				return err
+			}
+		}
+	}
+
+	if sp.cb.Edge != nil {
+		for _, e := range nl.Edges {
+			if err := sp.cb.Edge(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
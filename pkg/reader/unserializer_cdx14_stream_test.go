@@ -0,0 +1,224 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/reader/options"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+func TestParseStreamCallback(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "serialNumber": "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+  "version": 2,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+  "components": [
+    {"bom-ref": "lib", "type": "library", "name": "lib", "components": [
+      {"bom-ref": "sub", "type": "library", "name": "sub"}
+    ]},
+    {"bom-ref": "other", "type": "library", "name": "other"}
+  ],
+  "dependencies": [
+    {"ref": "lib", "dependsOn": ["other"]},
+    {"ref": "other"}
+  ]
+}`
+
+	nodes := []string{}
+	edges := []string{}
+	cb := StreamCallbacks{
+		Node: func(n *sbom.Node) error {
+			nodes = append(nodes, n.Id)
+			return nil
+		},
+		Edge: func(e *sbom.Edge) error {
+			edges = append(edges, fmt.Sprintf("%s %s %v", e.From, e.Type, e.To))
+			return nil
+		},
+	}
+
+	u := &UnserializerCDX14{}
+	bom, err := u.ParseStreamCallback(&options.Options{}, strings.NewReader(doc), cb)
+	require.NoError(t, err)
+	require.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", bom.Metadata.Id)
+	require.Equal(t, "2", bom.Metadata.Version)
+	require.Equal(t, []string{"app"}, bom.NodeList.RootElements)
+	require.Empty(t, bom.NodeList.Nodes)
+
+	require.Equal(t, []string{"app", "lib", "sub", "other"}, nodes)
+	require.Equal(t, []string{
+		"lib contains [sub]",
+		"app contains [lib]",
+		"app contains [other]",
+		"lib dependsOn [other]",
+	}, edges)
+
+	// Errors in the callback abort the parsing
+	_, err = u.ParseStreamCallback(nil, strings.NewReader(doc), StreamCallbacks{
+		Node: func(n *sbom.Node) error { return fmt.Errorf("stop at %s", n.Id) },
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stop at app")
+
+	// Limits are enforced as the document is read
+	_, err = u.ParseStreamCallback(&options.Options{MaxNodes: 2}, strings.NewReader(doc), StreamCallbacks{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	// Edges are counted by their destinations, not by dependency entries
+	bigDep := strings.Replace(doc, `"dependsOn": ["other"]`, `"dependsOn": ["other", "sub", "app"]`, 1)
+	_, err = u.ParseStreamCallback(&options.Options{MaxEdges: 5}, strings.NewReader(bigDep), StreamCallbacks{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	_, err = u.ParseStreamCallback(&options.Options{MaxEdges: 6}, strings.NewReader(bigDep), StreamCallbacks{})
+	require.NoError(t, err)
+}
+
+func TestParseStreamCallbackNoBOMRef(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+  "components": [{"type": "library", "name": "noref"}]
+}`
+
+	var node *sbom.Node
+	edges := []*sbom.Edge{}
+	_, err := (&UnserializerCDX14{}).ParseStreamCallback(nil, strings.NewReader(doc), StreamCallbacks{
+		Node: func(n *sbom.Node) error {
+			if n.Name == "noref" {
+				node = n
+			}
+			return nil
+		},
+		Edge: func(e *sbom.Edge) error {
+			edges = append(edges, e)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, node)
+	require.NotEmpty(t, node.Id)
+
+	// The root is related to the generated ID of the component
+	require.Len(t, edges, 1)
+	require.Equal(t, []string{node.Id}, edges[0].To)
+}
+
+func TestParseStreamFiltered(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+  "components": [
+    {"bom-ref": "lib", "type": "library", "name": "lib"},
+    {"bom-ref": "other", "type": "library", "name": "other"}
+  ],
+  "dependencies": [
+    {"ref": "app", "dependsOn": ["lib", "other"]},
+    {"ref": "lib", "dependsOn": ["other"]}
+  ]
+}`
+
+	u := &UnserializerCDX14{}
+	bom, err := u.parseStreamFiltered(nil, strings.NewReader(doc), "lib")
+	require.NoError(t, err)
+	require.Equal(t, []string{"lib"}, bom.NodeList.RootElements)
+	require.Len(t, bom.NodeList.Nodes, 2)
+	require.Nil(t, bom.NodeList.GetNodeByID("app"))
+	require.Len(t, bom.NodeList.Edges, 1)
+	require.Equal(t, []string{"other"}, bom.NodeList.Edges[0].To)
+
+	bom, err = u.parseStreamFiltered(nil, strings.NewReader(doc), "missing")
+	require.NoError(t, err)
+	require.Empty(t, bom.NodeList.Nodes)
+}
+
+func TestParseStreamFilteredNoBOMRefs(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"type": "application", "name": "app"}},
+  "components": [
+    {"type": "library", "name": "lib", "components": [{"type": "library", "name": "sub"}]},
+    {"type": "library", "name": "other"}
+  ]
+}`
+
+	bom, err := (&UnserializerCDX14{}).parseStreamFiltered(nil, strings.NewReader(doc), "lib")
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.RootElements, 1)
+	require.Len(t, bom.NodeList.Nodes, 2)
+
+	lib := bom.NodeList.GetNodeByID(bom.NodeList.RootElements[0])
+	require.NotNil(t, lib)
+	require.Equal(t, "lib", lib.Name)
+	require.Len(t, bom.NodeList.Edges, 1)
+	require.Equal(t, lib.Id, bom.NodeList.Edges[0].From)
+	sub := bom.NodeList.GetNodeByID(bom.NodeList.Edges[0].To[0])
+	require.NotNil(t, sub)
+	require.Equal(t, "sub", sub.Name)
+}
+
+func TestParseStreamCallbackMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large document test in short mode")
+	}
+
+	// The document is generated as it is read, it is never in memory
+	const total = 200_000
+	pr, pw := io.Pipe()
+	go func() {
+		fmt.Fprint(pw, `{"bomFormat": "CycloneDX", "specVersion": "1.4", "version": 1, "components": [`)
+		for i := 0; i < total; i++ {
+			if i > 0 {
+				fmt.Fprint(pw, ",")
+			}
+			fmt.Fprintf(
+				pw, `{"bom-ref": "pkg:npm/component-%d@1.0.0", "type": "library", "name": "component-%d", "version": "1.0.0", "purl": "pkg:npm/component-%d@1.0.0"}`,
+				i, i, i,
+			)
+		}
+		fmt.Fprint(pw, `]}`)
+		pw.Close()
+	}()
+
+	heap := func() uint64 {
+		runtime.GC()
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		return m.HeapAlloc
+	}
+
+	var start, end uint64
+	count := 0
+	u := &UnserializerCDX14{}
+	_, err := u.ParseStreamCallback(nil, pr, StreamCallbacks{
+		Node: func(n *sbom.Node) error {
+			count++
+			switch count {
+			case 1_000:
+				start = heap()
+			case total:
+				end = heap()
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, total, count)
+
+	// The document is over 30 MiB, holding its nodes would take far more
+	// than the allowed growth
+	growth := int64(end) - int64(start)
+	require.Less(t, growth, int64(4*1024*1024), "heap grew %d bytes", growth)
+}
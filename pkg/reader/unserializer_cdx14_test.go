@@ -0,0 +1,54 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/reader/options"
+)
+
+func TestUnserializeCDXNoComponents(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}}
+}`
+
+	bom, err := (&UnserializerCDX14{}).ParseStream(&options.Options{}, strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Nodes, 1)
+	require.Equal(t, []string{"app"}, bom.NodeList.RootElements)
+}
+
+func TestUnserializeCDXStrict(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+  "components": [
+    {"bom-ref": "lib", "type": "library", "name": "lib", "purl": "pkg:generic/lib@1.0"},
+    {
+      "bom-ref": "other", "type": "library", "name": "other",
+      "publisher": "acme",
+      "properties": [{"name": "foo", "value": "bar"}],
+      "components": [{"bom-ref": "sub", "type": "library", "name": "sub", "group": "example"}]
+    }
+  ]
+}`
+
+	u := &UnserializerCDX14{}
+	bom, err := u.ParseStream(&options.Options{}, strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, bom.NodeList.Nodes, 4)
+
+	_, err = u.ParseStream(&options.Options{Strict: true}, strings.NewReader(doc))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "$.components[1].components[0].group")
+	require.Contains(t, err.Error(), "$.components[1].publisher")
+	require.NotContains(t, err.Error(), "properties")
+	require.NotContains(t, err.Error(), "purl")
+}
@@ -0,0 +1,115 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// cdxModel describes the CycloneDX JSON fields captured by the CycloneDX
+// unserializer. A nil model accepts any value under the field.
+type cdxModel map[string]cdxModel
+
+// cdxComponentModel lists the component fields read into nodes
+var cdxComponentModel = cdxModel{
+	"bom-ref":     nil,
+	"type":        nil,
+	"name":        nil,
+	"version":     nil,
+	"description": nil,
+	"copyright":   nil,
+	"author":      nil,
+	"purl":        nil,
+	"cpe":         nil,
+	"properties":  cdxModel{"name": nil, "value": nil},
+	"hashes":      cdxModel{"alg": nil, "content": nil},
+	"licenses": cdxModel{
+		"expression": nil,
+		"license":    cdxModel{"id": nil},
+	},
+	"supplier": cdxModel{
+		"name":    nil,
+		"url":     nil,
+		"contact": cdxModel{"name": nil, "email": nil, "phone": nil},
+	},
+	"externalReferences": cdxModel{
+		"type":    nil,
+		"url":     nil,
+		"comment": nil,
+		"hashes":  cdxModel{"alg": nil, "content": nil},
+	},
+}
+
+// cdxDocumentModel lists the top level fields read from CycloneDX documents
+var cdxDocumentModel = cdxModel{
+	"$schema":      nil,
+	"bomFormat":    nil,
+	"specVersion":  nil,
+	"serialNumber": nil,
+	"version":      nil,
+	"metadata": cdxModel{
+		"timestamp":  nil,
+		"tools":      cdxModel{"vendor": nil, "name": nil, "version": nil},
+		"properties": cdxModel{"name": nil, "value": nil},
+		"component":  cdxComponentModel,
+	},
+	"components":   cdxComponentModel,
+	"dependencies": cdxModel{"ref": nil, "dependsOn": nil},
+	"vulnerabilities": cdxModel{
+		"id":       nil,
+		"analysis": cdxModel{"state": nil, "justification": nil, "detail": nil},
+		"affects":  cdxModel{"ref": nil},
+	},
+}
+
+func init() {
+	// Components are recursive
+	cdxComponentModel["components"] = cdxComponentModel
+}
+
+// checkCDXUnmodeledFields returns an error listing the JSON paths of the
+// fields in the CycloneDX document that the unserializer does not capture
+func checkCDXUnmodeledFields(data []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decoding cyclonedx: %w", err)
+	}
+
+	paths := unmodeledFields("$", doc, cdxDocumentModel)
+	if len(paths) == 0 {
+		return nil
+	}
+	return fmt.Errorf("document has fields not supported by protobom: %s", strings.Join(paths, ", "))
+}
+
+// unmodeledFields walks the value v and returns the paths of the fields not
+// listed in model
+func unmodeledFields(path string, v interface{}, model cdxModel) []string {
+	if model == nil {
+		return nil
+	}
+
+	ret := []string{}
+	switch val := v.(type) {
+	case []interface{}:
+		for i, item := range val {
+			ret = append(ret, unmodeledFields(fmt.Sprintf("%s[%d]", path, i), item, model)...)
+		}
+	case map[string]interface{}:
+		keys := []string{}
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			submodel, ok := model[k]
+			if !ok {
+				ret = append(ret, path+"."+k)
+				continue
+			}
+			ret = append(ret, unmodeledFields(path+"."+k, val[k], submodel)...)
+		}
+	}
+	return ret
+}
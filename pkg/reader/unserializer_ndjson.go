@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bom-squad/protobom/pkg/reader/options"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+// maxNDJSONLineSize is the largest record the NDJSON reader will accept
+const maxNDJSONLineSize = 16 * 1024 * 1024
+
+type UnserializerNDJSON struct{}
+
+// ParseStream reads a protobom written as newline delimited JSON: one node
+// per line followed by a last record with the document metadata and graph.
+func (u *UnserializerNDJSON) ParseStream(opts *options.Options, r io.Reader) (*sbom.Document, error) {
+	if opts == nil {
+		opts = &options.Options{}
+	}
+	if opts.MaxInputBytes > 0 {
+		data, err := readAllLimited(r, opts.MaxInputBytes)
+		if err != nil {
+			return nil, fmt.Errorf("reading ndjson stream: %w", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	records := [][]byte{}
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		records = append(records, append([]byte{}, line...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ndjson stream: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("ndjson stream has no records")
+	}
+
+	// Node records are counted and all records checked before unmarshaling
+	limits := newLimitChecker(opts, []string{"nodes"}, []string{"edges"})
+	if err := limits.addNodes(len(records) - 1); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if err := limits.checkJSON(record); err != nil {
+			return nil, err
+		}
+	}
+
+	doc := &sbom.Document{}
+	if err := protojson.Unmarshal(records[len(records)-1], doc); err != nil {
+		return nil, fmt.Errorf("parsing document graph record: %w", err)
+	}
+	if doc.NodeList == nil {
+		doc.NodeList = &sbom.NodeList{}
+	}
+
+	for i, record := range records[:len(records)-1] {
+		n := &sbom.Node{}
+		if err := protojson.Unmarshal(record, n); err != nil {
+			return nil, fmt.Errorf("parsing node in line %d: %w", i+1, err)
+		}
+		doc.NodeList.AddNode(n)
+	}
+
+	return doc, nil
+}
@@ -19,7 +19,15 @@ import (
 type UnserializerSPDX23 struct{}
 
 // ParseStream reads an io.Reader to parse an SPDX 2.3 document from it
-func (u *UnserializerSPDX23) ParseStream(_ *options.Options, r io.Reader) (*sbom.Document, error) {
+func (u *UnserializerSPDX23) ParseStream(opts *options.Options, r io.Reader) (*sbom.Document, error) {
+	if hasLimits(opts) {
+		var err error
+		r, err = checkJSONLimits(opts, r, []string{"packages", "files"}, []string{"relationships"})
+		if err != nil {
+			return nil, fmt.Errorf("checking SPDX limits: %w", err)
+		}
+	}
+
 	spdxDoc, err := spdxjson.Read(r)
 	if err != nil {
 		return nil, fmt.Errorf("parsing SPDX json: %w", err)
@@ -38,18 +46,26 @@ func (u *UnserializerSPDX23) ParseStream(_ *options.Options, r io.Reader) (*sbom
 	if spdxDoc.CreationInfo.Creators != nil {
 		for _, c := range spdxDoc.CreationInfo.Creators {
 			// TODO: We need to create a parser library in formats/spdx
-			if c.CreatorType == "Tool" {
-				// TODO: Split the version from the Tool string here.
-				bom.Metadata.Tools = append(bom.Metadata.Tools, &sbom.Tool{Name: c.Creator})
+			if c.CreatorType == protospdx.Tool {
+				name, version := protospdx.ParseToolString(c.Creator)
+				bom.AddTool(name, version, "")
 				continue
 			}
-			a := &sbom.Person{Name: c.Creator}
+			_, name, email := protospdx.ParseActorString(c.Creator)
+			a := &sbom.Person{Name: name, Email: email}
 			a.IsOrg = (c.CreatorType == protospdx.Organization)
 			bom.Metadata.Authors = append(bom.Metadata.Authors, a)
 		}
 	}
+	bom.Metadata.LicenseListVersion = spdxDoc.CreationInfo.LicenseListVersion
 
-	// TODO(degradation): SPDX LicenseVersion
+	// The SPDX JSON library does not read the element of the top-level
+	// annotations, they are all considered to be on the document
+	docAnnotations := []spdx23.Annotation{}
+	for _, a := range spdxDoc.Annotations {
+		docAnnotations = append(docAnnotations, *a)
+	}
+	bom.Metadata.Properties = annotationsToProperties(docAnnotations)
 
 	for _, p := range spdxDoc.Packages {
 		bom.NodeList.AddNode(u.packageToNode(p))
@@ -59,12 +75,17 @@ func (u *UnserializerSPDX23) ParseStream(_ *options.Options, r io.Reader) (*sbom
 		bom.NodeList.AddNode(u.fileToNode(f))
 	}
 
+	for i := range spdxDoc.Snippets {
+		u.snippetToLocation(bom.NodeList, &spdxDoc.Snippets[i])
+	}
+
 	for _, r := range spdxDoc.Relationships {
+		e := u.relationshipToEdge(opts, r)
 		// The SPDX go library surfaces the JSON top-level elements as relationships:
-		if r.RefA.ElementRefID == "DOCUMENT" && strings.EqualFold(r.Relationship, "DESCRIBES") {
-			bom.NodeList.RootElements = append(bom.NodeList.RootElements, string(r.RefB.ElementRefID))
+		if e.From == "DOCUMENT" && e.Type == sbom.Edge_describes {
+			bom.NodeList.RootElements = append(bom.NodeList.RootElements, e.To...)
 		} else {
-			bom.NodeList.AddEdge(u.relationshipToEdge(r))
+			bom.NodeList.AddEdge(e)
 		}
 	}
 
@@ -97,6 +118,10 @@ func (u *UnserializerSPDX23) packageToNode(p *spdx23.Package) *sbom.Node {
 		n.LicenseConcluded = p.PackageLicenseConcluded
 	}
 
+	if p.PackageLicenseDeclared != protospdx.NOASSERTION && p.PackageLicenseDeclared != "" {
+		n.Licenses = []string{p.PackageLicenseDeclared}
+	}
+
 	if len(p.PackageChecksums) > 0 {
 		n.Hashes = map[string]string{}
 		for _, h := range p.PackageChecksums {
@@ -115,10 +140,12 @@ func (u *UnserializerSPDX23) packageToNode(p *spdx23.Package) *sbom.Node {
 			}
 
 			// Else, it goes into the external references
+			// TODO(degradation): Custom types in the OTHER category are
+			// not preserved verbatim
 			n.ExternalReferences = append(n.ExternalReferences, &sbom.ExternalReference{
-				Url:     r.Locator,
-				Type:    r.RefType,
-				Comment: r.ExternalRefComment,
+				Url:           r.Locator,
+				ReferenceType: sbom.ExternalReferenceTypeFromSPDX2(r.RefType),
+				Comment:       r.ExternalRefComment,
 			})
 		}
 	}
@@ -133,32 +160,57 @@ func (u *UnserializerSPDX23) packageToNode(p *spdx23.Package) *sbom.Node {
 		n.BuildDate = timestamppb.New(*t)
 	}
 
-	// Mmh there is a limitation here on the SPDX libraries. They will not
-	// return the supplier and originator emails as a separate field. Perhaps
-	// we should upstream a fix for that.
-	if p.PackageSupplier != nil && p.PackageSupplier.Supplier != protospdx.NOASSERTION {
-		n.Suppliers = []*sbom.Person{{Name: p.PackageSupplier.Supplier}}
+	// The SPDX libraries will not return the supplier and originator emails
+	// as a separate field so we split them from the actor string here.
+	if p.PackageSupplier != nil && p.PackageSupplier.Supplier != protospdx.NOASSERTION && p.PackageSupplier.Supplier != "" {
+		_, name, email := protospdx.ParseActorString(p.PackageSupplier.Supplier)
+		n.Suppliers = []*sbom.Person{{Name: name, Email: email}}
 		if p.PackageSupplier.SupplierType == protospdx.Organization {
 			n.Suppliers[0].IsOrg = true
 		}
 	}
 
 	if p.PackageOriginator != nil && p.PackageOriginator.Originator != protospdx.NOASSERTION && p.PackageOriginator.Originator != "" {
-		n.Originators = []*sbom.Person{{Name: p.PackageOriginator.Originator}}
+		_, name, email := protospdx.ParseActorString(p.PackageOriginator.Originator)
+		n.Originators = []*sbom.Person{{Name: name, Email: email}}
 		if p.PackageOriginator.OriginatorType == protospdx.Organization {
 			n.Originators[0].IsOrg = true
 		}
 	}
 
+	n.Properties = annotationsToProperties(p.Annotations)
+
 	return n
 }
 
+// annotationsToProperties reads back the node properties recorded as
+// annotations by protobom.
+// TODO(degradation): Annotations made by other tools or people are lost
+func annotationsToProperties(annotations []spdx23.Annotation) map[string]string {
+	var props map[string]string
+	for _, a := range annotations {
+		if a.Annotator.AnnotatorType != protospdx.Tool || a.Annotator.Annotator != protospdx.PropertyAnnotator ||
+			a.AnnotationType != protospdx.AnnotationTypeOther {
+			continue
+		}
+		k, v, ok := strings.Cut(a.AnnotationComment, "=")
+		if !ok || k == "" {
+			continue
+		}
+		if props == nil {
+			props = map[string]string{}
+		}
+		props[k] = v
+	}
+	return props
+}
+
 // spdxDateToTime is a utility function that turns a date into a go time.Time
 func (*UnserializerSPDX23) spdxDateToTime(date string) *time.Time {
 	if date == "" {
 		return nil
 	}
-	t, err := time.Parse(time.RFC3339Nano, date)
+	t, err := protospdx.ParseDate(date)
 	if err != nil {
 		logrus.Warnf("invalid time format in %s", date)
 		return nil
@@ -166,6 +218,33 @@ func (*UnserializerSPDX23) spdxDateToTime(date string) *time.Time {
 	return &t
 }
 
+// snippetToLocation records the ranges of a snippet as the location of the
+// file node it points to.
+// TODO(degradation): Only the first snippet of each file is preserved and
+// the snippet's own identifier, licensing and copyright data is lost.
+func (u *UnserializerSPDX23) snippetToLocation(nl *sbom.NodeList, s *spdx23.Snippet) {
+	n := nl.GetNodeByID(string(s.SnippetFromFileSPDXIdentifier))
+	if n == nil || n.Location != nil {
+		return
+	}
+
+	loc := &sbom.SourceLocation{}
+	for _, r := range s.Ranges {
+		if r.StartPointer.Offset != 0 || r.EndPointer.Offset != 0 {
+			loc.StartByte = int64(r.StartPointer.Offset)
+			loc.EndByte = int64(r.EndPointer.Offset)
+		}
+		if r.StartPointer.LineNumber != 0 || r.EndPointer.LineNumber != 0 {
+			loc.StartLine = int64(r.StartPointer.LineNumber)
+			loc.EndLine = int64(r.EndPointer.LineNumber)
+		}
+	}
+
+	if loc.HasBytes() || loc.HasLines() {
+		n.Location = loc
+	}
+}
+
 // fileToNode converts a file from SPDX into a protobom node
 func (u *UnserializerSPDX23) fileToNode(f *spdx23.File) *sbom.Node {
 	n := &sbom.Node{
@@ -181,6 +260,7 @@ func (u *UnserializerSPDX23) fileToNode(f *spdx23.File) *sbom.Node {
 		Suppliers:        []*sbom.Person{},
 		Originators:      []*sbom.Person{},
 		FileTypes:        f.FileTypes,
+		Properties:       annotationsToProperties(f.Annotations),
 	}
 
 	if len(f.Checksums) > 0 {
@@ -193,14 +273,34 @@ func (u *UnserializerSPDX23) fileToNode(f *spdx23.File) *sbom.Node {
 	return n
 }
 
-// relationshipToEdge converts the SPDX relationship to a protobom Edge
-func (*UnserializerSPDX23) relationshipToEdge(r *spdx23.Relationship) *sbom.Edge {
+// relationshipToEdge converts the SPDX relationship to a protobom Edge.
+// Inverse relationships are flipped, A CONTAINED_BY B becomes B contains A.
+func (*UnserializerSPDX23) relationshipToEdge(opts *options.Options, r *spdx23.Relationship) *sbom.Edge {
 	// TODO(degradation) How to handle external documents?
 	// TODO(degradation) How to handle NOASSERTION and NONE targets
+	t, inverse := sbom.EdgeTypeFromSPDX2Relationship(r.Relationship)
+	from, to := string(r.RefA.ElementRefID), string(r.RefB.ElementRefID)
+	if inverse {
+		from, to = to, from
+	}
 	e := &sbom.Edge{
-		Type: sbom.EdgeTypeFromSPDX2(r.Relationship),
-		From: string(r.RefA.ElementRefID),
-		To:   []string{string(r.RefB.ElementRefID)},
+		Type: t,
+		From: from,
+		To:   []string{to},
+	}
+
+	// Keep the semantics of relationships protobom does not model
+	switch e.Type {
+	case sbom.Edge_UNKNOWN:
+		e.Label = r.Relationship
+		warn(opts, options.Warning{
+			Relationship: r.Relationship,
+			From:         e.From,
+			To:           to,
+			Message:      "unknown relationship type, imported as an UNKNOWN edge",
+		})
+	case sbom.Edge_other:
+		e.Label = r.RelationshipComment
 	}
 	return e
 }
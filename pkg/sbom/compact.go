@@ -0,0 +1,82 @@
+package sbom
+
+// CompactOptions control what NodeList.CompactWithOptions does
+type CompactOptions struct {
+	// InternStrings makes repeated strings (node references, hash and
+	// identifier values) share a single copy in memory
+	InternStrings bool
+}
+
+// Compact rebuilds the nodes, edges and root elements of the NodeList into
+// slices sized to their contents, dropping nil entries and edges without
+// destinations. Long lived NodeLists can call it after removing elements
+// to release the memory held by the larger backing arrays.
+func (nl *NodeList) Compact() {
+	nl.CompactWithOptions(CompactOptions{})
+}
+
+// CompactWithOptions works as Compact and optionally interns strings
+func (nl *NodeList) CompactWithOptions(opts CompactOptions) {
+	intern := func(s string) string { return s }
+	if opts.InternStrings {
+		pool := map[string]string{}
+		intern = func(s string) string {
+			if interned, ok := pool[s]; ok {
+				return interned
+			}
+			pool[s] = s
+			return s
+		}
+	}
+
+	count := 0
+	for _, n := range nl.Nodes {
+		if n != nil {
+			count++
+		}
+	}
+	nodes := make([]*Node, 0, count)
+	for _, n := range nl.Nodes {
+		if n == nil {
+			continue
+		}
+		if opts.InternStrings {
+			n.Id = intern(n.Id)
+			for algo, digest := range n.Hashes {
+				n.Hashes[algo] = intern(digest)
+			}
+			for t, id := range n.Identifiers {
+				n.Identifiers[t] = intern(id)
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	nl.Nodes = nodes
+
+	count = 0
+	for _, e := range nl.Edges {
+		if e != nil && len(e.To) > 0 {
+			count++
+		}
+	}
+	edges := make([]*Edge, 0, count)
+	for _, e := range nl.Edges {
+		if e == nil || len(e.To) == 0 {
+			continue
+		}
+		to := make([]string, len(e.To))
+		for i := range e.To {
+			to[i] = intern(e.To[i])
+		}
+		e.To = to
+		e.From = intern(e.From)
+		edges = append(edges, e)
+	}
+	nl.Edges = edges
+
+	roots := make([]string, len(nl.RootElements))
+	for i, id := range nl.RootElements {
+		roots[i] = intern(id)
+	}
+	nl.RootElements = roots
+}
@@ -0,0 +1,89 @@
+package sbom
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// largeNodeList returns a NodeList with n nodes depending on the first one
+func largeNodeList(n int) *NodeList {
+	nl := &NodeList{RootElements: []string{"node-0"}}
+	to := []string{}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("node-%d", i)
+		nl.Nodes = append(nl.Nodes, &Node{
+			Id:     id,
+			Hashes: map[string]string{"SHA256": fmt.Sprintf("%064d", i%10)},
+		})
+		if i > 0 {
+			to = append(to, id)
+		}
+	}
+	nl.Edges = []*Edge{{Type: Edge_dependsOn, From: "node-0", To: to}}
+	return nl
+}
+
+func TestCompact(t *testing.T) {
+	nl := &NodeList{
+		Nodes: make([]*Node, 0, 100),
+		Edges: []*Edge{
+			nil,
+			{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			{Type: Edge_contains, From: "a", To: []string{}},
+		},
+		RootElements: []string{"a"},
+	}
+	nl.Nodes = append(nl.Nodes, &Node{Id: "a"}, nil, &Node{Id: "b"})
+	original := &NodeList{
+		Nodes:        []*Node{{Id: "a"}, {Id: "b"}},
+		Edges:        []*Edge{{Type: Edge_dependsOn, From: "a", To: []string{"b"}}},
+		RootElements: []string{"a"},
+	}
+
+	nl.Compact()
+	require.Len(t, nl.Nodes, 2)
+	require.Equal(t, 2, cap(nl.Nodes))
+	require.Len(t, nl.Edges, 1)
+	require.True(t, original.Equal(nl))
+
+	large := largeNodeList(1000)
+	large.CompactWithOptions(CompactOptions{InternStrings: true})
+	require.Len(t, large.Nodes, 1000)
+	require.Equal(t, large.Nodes[1].Hashes["SHA256"], large.Nodes[11].Hashes["SHA256"])
+}
+
+// BenchmarkCompact reports the heap retained by a NodeList after trimming
+// most of its nodes by reslicing, with and without compacting it. The
+// backing arrays keep the removed nodes alive until the list is compacted.
+func BenchmarkCompact(b *testing.B) {
+	retained := func(compact bool) uint64 {
+		nl := largeNodeList(20_000)
+		nl.Nodes = nl.Nodes[:100]
+		nl.Edges[0].To = nl.Edges[0].To[:99]
+		if compact {
+			nl.CompactWithOptions(CompactOptions{InternStrings: true})
+		}
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		runtime.KeepAlive(nl)
+		nl = nil //nolint:ineffassign,wastedassign
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		return before.HeapAlloc - after.HeapAlloc
+	}
+
+	for _, compact := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compact=%v", compact), func(b *testing.B) {
+			var total uint64
+			for i := 0; i < b.N; i++ {
+				total += retained(compact)
+			}
+			b.ReportMetric(float64(total)/float64(b.N), "retained-B/op")
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// distroPurlTypes are purl types where the namespace names the distribution
+// packaging the software and not its vendor
+var distroPurlTypes = map[string]struct{}{
+	"apk": {},
+	"deb": {},
+	"rpm": {},
+}
+
+// GeneratePossibleCPEs returns a list of candidate CPE 2.3 strings for the
+// node built from its package URL. As the vendor can rarely be determined
+// reliably from a purl, a candidate is generated for each plausible vendor
+// including a wildcard. Returns an empty list if the node has no purl.
+func (n *Node) GeneratePossibleCPEs() []string {
+	purl := n.Purl()
+	name := purl.Name()
+	if name == "" {
+		return []string{}
+	}
+
+	version := n.Version
+	if version == "" {
+		version = purl.Version()
+	}
+	if version == "" {
+		version = "*"
+	}
+
+	products := []string{name}
+	if strings.Contains(name, "-") {
+		products = append(products, strings.ReplaceAll(name, "-", "_"))
+	}
+
+	vendors := append(cpeVendorCandidates(purl.Type(), purl.Namespace()), name, "*")
+
+	ret := []string{}
+	seen := map[string]struct{}{}
+	for _, vendor := range vendors {
+		for _, product := range products {
+			cpe := fmt.Sprintf(
+				"cpe:2.3:a:%s:%s:%s:*:*:*:*:*:*:*",
+				escapeCPEComponent(vendor), escapeCPEComponent(product), escapeCPEComponent(version),
+			)
+			if _, ok := seen[cpe]; ok {
+				continue
+			}
+			seen[cpe] = struct{}{}
+			ret = append(ret, cpe)
+		}
+	}
+	return ret
+}
+
+// cpeVendorCandidates returns the vendor names that can be inferred from
+// the purl namespace
+func cpeVendorCandidates(purlType, namespace string) []string {
+	if _, ok := distroPurlTypes[purlType]; ok || namespace == "" {
+		return []string{}
+	}
+
+	switch purlType {
+	case "golang":
+		// Go modules are namespaced by their repository host: the
+		// organization is the vendor, eg github.com/sirupsen
+		parts := strings.Split(namespace, "/")
+		if len(parts) > 1 {
+			return []string{parts[1]}
+		}
+		return []string{parts[0]}
+	case "maven":
+		// Maven groups are reverse domain names, eg org.apache.commons
+		parts := strings.Split(namespace, ".")
+		ret := []string{}
+		if len(parts) > 1 {
+			ret = append(ret, parts[1])
+		}
+		return append(ret, parts[len(parts)-1])
+	default:
+		parts := strings.Split(namespace, "/")
+		return []string{strings.TrimPrefix(parts[len(parts)-1], "@")}
+	}
+}
+
+// escapeCPEComponent lowercases a value and quotes the characters not
+// allowed unescaped in a CPE 2.3 formatted string component
+func escapeCPEComponent(s string) string {
+	if s == "*" {
+		return s
+	}
+
+	var sb strings.Builder
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case c == ' ':
+			sb.WriteRune('_')
+		case (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' || c == '-' || c == '.':
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune('\\')
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// cpe23Attributes is the number of attributes in a CPE 2.3 name: part,
+// vendor, product, version, update, edition, language, sw_edition,
+// target_sw, target_hw and other
+const cpe23Attributes = 11
+
+// CPE22to23 converts a CPE 2.2 URI (cpe:/a:vendor:product:...) to a CPE 2.3
+// formatted string following the NISTIR 7695 bindings. Missing components
+// become ANY (*), a packed edition is unpacked into the extended attributes
+// and the special %01 and %02 sequences become the ? and * wildcards.
+func CPE22to23(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	if !strings.HasPrefix(strings.ToLower(uri), "cpe:/") {
+		return "", fmt.Errorf("CPE %q does not start with cpe:/", uri)
+	}
+
+	components := strings.Split(uri[len("cpe:/"):], ":")
+	if len(components) > 7 {
+		return "", fmt.Errorf("CPE %q has %d components, expected at most 7", uri, len(components))
+	}
+	for len(components) < 7 {
+		components = append(components, "")
+	}
+
+	values := make([]string, len(components))
+	extended := []string{"*", "*", "*", "*"}
+	for i, c := range components {
+		// A packed edition holds the extended attributes:
+		// ~edition~sw_edition~target_sw~target_hw~other
+		if i == 5 && strings.HasPrefix(c, "~") {
+			packed := strings.Split(c, "~")
+			if len(packed) != 6 {
+				return "", fmt.Errorf("CPE %q has an invalid packed edition", uri)
+			}
+			for j, p := range packed[1:] {
+				v, err := cpeURIToFS(p)
+				if err != nil {
+					return "", fmt.Errorf("invalid CPE %q: %w", uri, err)
+				}
+				if j == 0 {
+					values[i] = v
+				} else {
+					extended[j-1] = v
+				}
+			}
+			continue
+		}
+
+		v, err := cpeURIToFS(c)
+		if err != nil {
+			return "", fmt.Errorf("invalid CPE %q: %w", uri, err)
+		}
+		values[i] = v
+	}
+
+	// The extended attributes go after the language
+	attributes := append(values, extended...)
+	if !strings.Contains("aho*", attributes[0]) || len(attributes[0]) != 1 {
+		return "", fmt.Errorf("CPE %q has an invalid part %q", uri, attributes[0])
+	}
+	return "cpe:2.3:" + strings.Join(attributes, ":"), nil
+}
+
+// CPE23to22 converts a CPE 2.3 formatted string to a CPE 2.2 URI following
+// the NISTIR 7695 bindings. The extended attributes are packed in the
+// edition when any of them is set and trailing ANY components are dropped.
+func CPE23to22(fs string) (string, error) {
+	fs = strings.TrimSpace(fs)
+	if !strings.HasPrefix(strings.ToLower(fs), "cpe:2.3:") {
+		return "", fmt.Errorf("CPE %q does not start with cpe:2.3:", fs)
+	}
+
+	parts := splitCPE23(fs)
+	if len(parts) != cpe23Attributes+2 {
+		return "", fmt.Errorf("CPE %q has %d components, expected 13", fs, len(parts))
+	}
+
+	attributes := make([]string, 0, cpe23Attributes)
+	for _, p := range parts[2:] {
+		v, err := cpeFSToURI(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid CPE %q: %w", fs, err)
+		}
+		attributes = append(attributes, v)
+	}
+
+	// part:vendor:product:version:update:edition:language
+	components := attributes[:7]
+	if extended := attributes[7:]; strings.Join(extended, "") != "" {
+		components[5] = "~" + strings.Join(append([]string{components[5]}, extended...), "~")
+	}
+
+	return strings.TrimRight("cpe:/"+strings.Join(components, ":"), ":"), nil
+}
+
+// cpeURIToFS converts a component of a CPE 2.2 URI to its formatted string
+// binding
+func cpeURIToFS(c string) (string, error) {
+	switch c {
+	case "":
+		return "*", nil
+	case "-":
+		return "-", nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(c); i++ {
+		ch := c[i]
+		if ch == '%' {
+			if i+2 >= len(c) {
+				return "", fmt.Errorf("truncated percent encoding in %q", c)
+			}
+			code, err := strconv.ParseUint(c[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("invalid percent encoding in %q", c)
+			}
+			i += 2
+			switch code {
+			case 0x01:
+				sb.WriteByte('?')
+				continue
+			case 0x02:
+				sb.WriteByte('*')
+				continue
+			}
+			ch = byte(code)
+		}
+		sb.WriteString(cpeQuoteFS(ch))
+	}
+	return sb.String(), nil
+}
+
+// cpeFSToURI converts a component of a CPE 2.3 formatted string to its URI
+// binding. ANY is returned as an empty string.
+func cpeFSToURI(c string) (string, error) {
+	switch c {
+	case "*", "":
+		return "", nil
+	case "-":
+		return "-", nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(c); i++ {
+		ch := c[i]
+		switch ch {
+		case '\\':
+			if i+1 == len(c) {
+				return "", errors.New("CPE component ends with an escape character")
+			}
+			i++
+			sb.WriteString(cpeEncodeURI(c[i]))
+		case '?':
+			sb.WriteString("%01")
+		case '*':
+			sb.WriteString("%02")
+		default:
+			sb.WriteString(cpeEncodeURI(ch))
+		}
+	}
+	return sb.String(), nil
+}
+
+// cpeQuoteFS returns a character as written in a CPE 2.3 formatted string,
+// quoting everything but letters, digits and the _ - . characters
+func cpeQuoteFS(ch byte) string {
+	if cpeUnreserved(ch) {
+		return strings.ToLower(string(ch))
+	}
+	return "\\" + string(ch)
+}
+
+// cpeEncodeURI returns a character as written in a CPE 2.2 URI,
+// percent encoding everything but letters, digits and the _ - . characters
+func cpeEncodeURI(ch byte) string {
+	if cpeUnreserved(ch) {
+		return strings.ToLower(string(ch))
+	}
+	return fmt.Sprintf("%%%02x", ch)
+}
+
+// cpeUnreserved returns true for the characters written verbatim in both
+// CPE bindings
+func cpeUnreserved(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') ||
+		ch == '_' || ch == '-' || ch == '.'
+}
+
+// cpe23 returns the node's CPE as a normalized CPE 2.3 formatted string,
+// converting its CPE 2.2 URI when it does not have a CPE 2.3. Returns an
+// empty string if the node has no valid CPE.
+func (n *Node) cpe23() string {
+	if cpe := n.Identifiers[int32(SoftwareIdentifierType_CPE23)]; cpe != "" {
+		return normalizeCPE23(cpe)
+	}
+	if cpe, err := CPE22to23(n.Identifiers[int32(SoftwareIdentifierType_CPE22)]); err == nil {
+		return normalizeCPE23(cpe)
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePossibleCPEs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		node     *Node
+		expected []string
+	}{
+		{
+			name: "npm",
+			node: &Node{Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:npm/lodash@4.17.20"}},
+			expected: []string{
+				"cpe:2.3:a:lodash:lodash:4.17.20:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:lodash:4.17.20:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "npm scoped, version from node",
+			node: &Node{
+				Version:     "16.0.1",
+				Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:npm/%40angular/core@16.0.0"},
+			},
+			expected: []string{
+				"cpe:2.3:a:angular:core:16.0.1:*:*:*:*:*:*:*",
+				"cpe:2.3:a:core:core:16.0.1:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:core:16.0.1:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "maven",
+			node: &Node{Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:maven/org.apache.commons/commons-text@1.9"}},
+			expected: []string{
+				"cpe:2.3:a:apache:commons-text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:apache:commons_text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:commons:commons-text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:commons:commons_text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:commons-text:commons-text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:commons-text:commons_text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:commons-text:1.9:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:commons_text:1.9:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "golang",
+			node: &Node{Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:golang/github.com/sirupsen/logrus@v1.9.3"}},
+			expected: []string{
+				"cpe:2.3:a:sirupsen:logrus:v1.9.3:*:*:*:*:*:*:*",
+				"cpe:2.3:a:logrus:logrus:v1.9.3:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:logrus:v1.9.3:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "distro namespace is not a vendor",
+			node: &Node{Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:deb/debian/libc6@2.31+deb11?arch=amd64"}},
+			expected: []string{
+				"cpe:2.3:a:libc6:libc6:2.31\\+deb11:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:libc6:2.31\\+deb11:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "no version",
+			node: &Node{Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:generic/openssl"}},
+			expected: []string{
+				"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*",
+				"cpe:2.3:a:*:openssl:*:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name:     "no purl",
+			node:     &Node{Name: "openssl"},
+			expected: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.node.GeneratePossibleCPEs())
+		})
+	}
+}
+
+func TestCPEConversion(t *testing.T) {
+	for _, tc := range []struct {
+		cpe22 string
+		cpe23 string
+	}{
+		{"cpe:/a:nginx:nginx:1.21.1", "cpe:2.3:a:nginx:nginx:1.21.1:*:*:*:*:*:*:*"},
+		{"cpe:/o:microsoft:windows_xp::sp2:pro", "cpe:2.3:o:microsoft:windows_xp:*:sp2:pro:*:*:*:*:*"},
+		{"cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~", "cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:*"},
+		{"cpe:/a:foo%5cbar:big%24money_manager_2010::::en-us", "cpe:2.3:a:foo\\\\bar:big\\$money_manager_2010:*:*:*:en-us:*:*:*:*"},
+		{"cpe:/a:vendor:product:1.%02", "cpe:2.3:a:vendor:product:1.*:*:*:*:*:*:*:*"},
+		{"cpe:/h:cisco", "cpe:2.3:h:cisco:*:*:*:*:*:*:*:*:*"},
+	} {
+		cpe23, err := CPE22to23(tc.cpe22)
+		require.NoError(t, err, tc.cpe22)
+		require.Equal(t, tc.cpe23, cpe23)
+
+		cpe22, err := CPE23to22(tc.cpe23)
+		require.NoError(t, err, tc.cpe23)
+		require.Equal(t, tc.cpe22, cpe22)
+
+		// Converting back and forth is stable
+		again, err := CPE22to23(cpe22)
+		require.NoError(t, err)
+		require.Equal(t, cpe23, again)
+	}
+
+	for _, invalid := range []string{"cpe:2.3:a:b:c", "cpe:/a:b:c:d:e:f:g:h", "cpe:/x:vendor", "cpe:/a:v%zz", "cpe:/a:v:p:1:u:~a~b"} {
+		_, err := CPE22to23(invalid)
+		require.Error(t, err, invalid)
+	}
+	for _, invalid := range []string{"cpe:/a:nginx", "cpe:2.3:a:nginx:nginx", "cpe:2.3:a:v:p:1:*:*:*:*:*:*:\\"} {
+		_, err := CPE23to22(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestGetNodesByIdentifierCPEVersions(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "nginx", Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE22): "cpe:/a:nginx:nginx:1.21.1"}},
+			{Id: "bash", Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:5.0:*:*:*:*:*:*:*"}},
+		},
+	}
+
+	res := nl.GetNodesByIdentifier("cpe23", "cpe:2.3:a:nginx:nginx:1.21.1:*:*:*:*:*:*:*")
+	require.Len(t, res, 1)
+	require.Equal(t, "nginx", res[0].Id)
+
+	res = nl.GetNodesByIdentifier("cpe22", "cpe:/a:gnu:bash:5.0")
+	require.Len(t, res, 1)
+	require.Equal(t, "bash", res[0].Id)
+
+	match, err := nl.GetMatchingNode(&Node{
+		Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:nginx:nginx:1.21.1:*:*:*:*:*:*:*"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "nginx", match.Id)
+}
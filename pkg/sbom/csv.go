@@ -0,0 +1,86 @@
+package sbom
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvColumns are the columns supported in the tabular exports of the
+// NodeList and the functions to extract their values from a node
+var csvColumns = map[string]func(*Node) string{
+	"id":      func(n *Node) string { return n.Id },
+	"name":    func(n *Node) string { return n.Name },
+	"version": func(n *Node) string { return n.Version },
+	"purl":    func(n *Node) string { return string(n.Purl()) },
+	"cpe": func(n *Node) string {
+		if cpe, ok := n.Identifiers[int32(SoftwareIdentifierType_CPE23)]; ok {
+			return cpe
+		}
+		return n.Identifiers[int32(SoftwareIdentifierType_CPE22)]
+	},
+	"licenses": func(n *Node) string { return strings.Join(n.LicenseIDs(), "; ") },
+	"supplier": func(n *Node) string {
+		names := []string{}
+		for _, s := range n.Suppliers {
+			names = append(names, s.Name)
+		}
+		return strings.Join(names, "; ")
+	},
+	"sha256": func(n *Node) string { return n.Hashes[HashAlgorithm_SHA256.String()] },
+}
+
+// DefaultCSVColumns are the columns written when none are specified
+var DefaultCSVColumns = []string{"name", "version", "purl", "cpe", "licenses", "supplier", "sha256"}
+
+// WriteCSV writes the nodes of the NodeList to w as comma separated values,
+// one row per node preceded by a header. The columns argument selects the
+// columns to write (id, name, version, purl, cpe, licenses, supplier and
+// sha256), if empty, DefaultCSVColumns are used.
+func (nl *NodeList) WriteCSV(w io.Writer, columns []string) error {
+	return nl.writeTable(w, columns, ',')
+}
+
+// WriteTSV writes the nodes of the NodeList as tab separated values. It
+// takes the same columns as WriteCSV.
+func (nl *NodeList) WriteTSV(w io.Writer, columns []string) error {
+	return nl.writeTable(w, columns, '\t')
+}
+
+func (nl *NodeList) writeTable(w io.Writer, columns []string, separator rune) error {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	getters := []func(*Node) string{}
+	for _, c := range columns {
+		getter, ok := csvColumns[c]
+		if !ok {
+			return fmt.Errorf("unknown column %q", c)
+		}
+		getters = append(getters, getter)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = separator
+	if err := cw.Write(columns); err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+
+	for _, n := range nl.Nodes {
+		row := make([]string, len(getters))
+		for i, getter := range getters {
+			row[i] = getter(n)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing node %s: %w", n.Id, err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
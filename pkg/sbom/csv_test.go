@@ -0,0 +1,43 @@
+package sbom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{
+				Id:       "node1",
+				Name:     "curl",
+				Version:  "8.1.1",
+				Licenses: []string{"curl OR MIT"},
+				Hashes:   map[string]string{"SHA256": "abc123", "SHA1": "def"},
+				Identifiers: map[int32]string{
+					int32(SoftwareIdentifierType_PURL):  "pkg:apk/wolfi/curl@8.1.1",
+					int32(SoftwareIdentifierType_CPE22): "cpe:/a:haxx:curl:8.1.1",
+				},
+				Suppliers: []*Person{{Name: "Wolfi"}, {Name: "Chainguard, Inc"}},
+			},
+			{Id: "node2", Name: "readme"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, nl.WriteCSV(&buf, nil))
+	require.Equal(t, `name,version,purl,cpe,licenses,supplier,sha256
+curl,8.1.1,pkg:apk/wolfi/curl@8.1.1,cpe:/a:haxx:curl:8.1.1,curl; MIT,"Wolfi; Chainguard, Inc",abc123
+readme,,,,,,
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, nl.WriteTSV(&buf, []string{"id", "name"}))
+	require.Equal(t, "id\tname\nnode1\tcurl\nnode2\treadme\n", buf.String())
+
+	buf.Reset()
+	require.Error(t, nl.WriteCSV(&buf, []string{"name", "color"}))
+	require.Empty(t, buf.String())
+}
@@ -0,0 +1,201 @@
+package sbom
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NodeDiff lists the fields that differ between two versions of a node.
+// Fields are named after their protobuf names. Hashes, identifiers and
+// properties are reported per entry, eg "hashes[SHA256]" or "identifiers[PURL]".
+type NodeDiff struct {
+	ID      string
+	Added   []string // Fields only set in the new node
+	Removed []string // Fields only set in the original node
+	Changed []string // Fields set in both nodes with different values
+}
+
+// NodeListDiff describes the differences between two NodeLists. Edges are
+// reported with a single destination each.
+type NodeListDiff struct {
+	NodesAdded   []string
+	NodesRemoved []string
+	NodesChanged []NodeDiff
+	EdgesAdded   []*Edge
+	EdgesRemoved []*Edge
+	RootsAdded   []string
+	RootsRemoved []string
+}
+
+// Empty returns true if the diff has no differences
+func (d *NodeListDiff) Empty() bool {
+	return len(d.NodesAdded) == 0 && len(d.NodesRemoved) == 0 && len(d.NodesChanged) == 0 &&
+		len(d.EdgesAdded) == 0 && len(d.EdgesRemoved) == 0 &&
+		len(d.RootsAdded) == 0 && len(d.RootsRemoved) == 0
+}
+
+// Diff compares nl to nl2 and returns the differences between them. Nodes
+// are matched by ID and the results are sorted to make them deterministic.
+func (nl *NodeList) Diff(nl2 *NodeList) *NodeListDiff {
+	ret := &NodeListDiff{
+		NodesAdded:   []string{},
+		NodesRemoved: []string{},
+		NodesChanged: []NodeDiff{},
+		EdgesAdded:   []*Edge{},
+		EdgesRemoved: []*Edge{},
+		RootsAdded:   []string{},
+		RootsRemoved: []string{},
+	}
+
+	index1, index2 := nl.indexNodes(), nl2.indexNodes()
+	for id, n := range index1 {
+		n2, ok := index2[id]
+		if !ok {
+			ret.NodesRemoved = append(ret.NodesRemoved, id)
+			continue
+		}
+		if d := n.diff(n2); d != nil {
+			ret.NodesChanged = append(ret.NodesChanged, *d)
+		}
+	}
+	for id := range index2 {
+		if _, ok := index1[id]; !ok {
+			ret.NodesAdded = append(ret.NodesAdded, id)
+		}
+	}
+	sort.Strings(ret.NodesAdded)
+	sort.Strings(ret.NodesRemoved)
+	sort.Slice(ret.NodesChanged, func(i, j int) bool {
+		return ret.NodesChanged[i].ID < ret.NodesChanged[j].ID
+	})
+
+	edges1, edges2 := nl.flatEdges(), nl2.flatEdges()
+	for k, e := range edges1 {
+		if _, ok := edges2[k]; !ok {
+			ret.EdgesRemoved = append(ret.EdgesRemoved, e)
+		}
+	}
+	for k, e := range edges2 {
+		if _, ok := edges1[k]; !ok {
+			ret.EdgesAdded = append(ret.EdgesAdded, e)
+		}
+	}
+	sortEdges(ret.EdgesAdded)
+	sortEdges(ret.EdgesRemoved)
+
+	roots1, roots2 := nl.indexRootElements(), nl2.indexRootElements()
+	for id := range roots1 {
+		if _, ok := roots2[id]; !ok {
+			ret.RootsRemoved = append(ret.RootsRemoved, id)
+		}
+	}
+	for id := range roots2 {
+		if _, ok := roots1[id]; !ok {
+			ret.RootsAdded = append(ret.RootsAdded, id)
+		}
+	}
+	sort.Strings(ret.RootsAdded)
+	sort.Strings(ret.RootsRemoved)
+
+	return ret
+}
+
+// flatEdges indexes the edges of the NodeList split into one edge per
+// destination
+func (nl *NodeList) flatEdges() map[string]*Edge {
+	ret := map[string]*Edge{}
+	if nl == nil {
+		return ret
+	}
+	for _, e := range nl.Edges {
+		for _, to := range e.To {
+			single := &Edge{Type: e.Type, From: e.From, To: []string{to}, Label: e.Label}
+			ret[single.flatString()] = single
+		}
+	}
+	return ret
+}
+
+// sortEdges sorts single destination edges by origin, type and destination
+func sortEdges(edges []*Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].flatString() < edges[j].flatString()
+	})
+}
+
+// diff returns the fields that differ between n and n2 or nil if the nodes
+// are equal
+func (n *Node) diff(n2 *Node) *NodeDiff {
+	if n.Equal(n2) {
+		return nil
+	}
+
+	ret := &NodeDiff{ID: n.Id, Added: []string{}, Removed: []string{}, Changed: []string{}}
+	add := func(name string, v1, v2 string, has1, has2 bool) {
+		switch {
+		case has1 && !has2:
+			ret.Removed = append(ret.Removed, name)
+		case !has1 && has2:
+			ret.Added = append(ret.Added, name)
+		case has1 && has2 && v1 != v2:
+			ret.Changed = append(ret.Changed, name)
+		}
+	}
+
+	m1, m2 := n.ProtoReflect(), n2.ProtoReflect()
+	fields := m1.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		switch fd.Name() {
+		case "hashes":
+			for _, algo := range mapKeys(n.Hashes, n2.Hashes) {
+				h1, ok1 := n.Hashes[algo]
+				h2, ok2 := n2.Hashes[algo]
+				add(fmt.Sprintf("hashes[%s]", algo), h1, h2, ok1, ok2)
+			}
+		case "identifiers":
+			for _, t := range mapKeys(n.Identifiers, n2.Identifiers) {
+				id1, ok1 := n.Identifiers[t]
+				id2, ok2 := n2.Identifiers[t]
+				add(fmt.Sprintf("identifiers[%s]", SoftwareIdentifierType(t)), id1, id2, ok1, ok2)
+			}
+		case "properties":
+			for _, k := range mapKeys(n.Properties, n2.Properties) {
+				p1, ok1 := n.Properties[k]
+				p2, ok2 := n2.Properties[k]
+				add(fmt.Sprintf("properties[%s]", k), p1, p2, ok1, ok2)
+			}
+		default:
+			add(string(fd.Name()), singleFieldString(m1, fd), singleFieldString(m2, fd), m1.Has(fd), m2.Has(fd))
+		}
+	}
+	return ret
+}
+
+// singleFieldString returns the flat string of a node with only the field
+// fd of m set, reusing the node comparison semantics for a single field
+func singleFieldString(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	tmp := &Node{}
+	if m.Has(fd) {
+		tmp.ProtoReflect().Set(fd, m.Get(fd))
+	}
+	return tmp.flatString()
+}
+
+// mapKeys returns the sorted union of the keys of two maps
+func mapKeys[K string | int32, V any](m1, m2 map[K]V) []K {
+	keys := []K{}
+	seen := map[K]struct{}{}
+	for _, m := range []map[K]V{m1, m2} {
+		for k := range m {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
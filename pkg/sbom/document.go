@@ -1,5 +1,14 @@
 package sbom
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
 func NewDocument() *Document {
 	return &Document{
 		Metadata: &Metadata{
@@ -18,8 +27,108 @@ func NewDocument() *Document {
 	}
 }
 
+// Clone returns a deep copy of the document. Changes to the nodes, edges or
+// metadata of the clone do not affect the original document.
+func (d *Document) Clone() *Document {
+	if d == nil {
+		return nil
+	}
+	return &Document{
+		Metadata: proto.Clone(d.Metadata).(*Metadata),
+		NodeList: d.NodeList.Copy(),
+	}
+}
+
 // GetRootNodes returns the top level nodes of the document. It calls the underlying
 // method in the document's NodeList.
 func (d *Document) GetRootNodes() []*Node {
 	return d.NodeList.GetRootNodes()
 }
+
+// SetRoot makes the node with the specified ID the only root element of the
+// document. Former root elements that are not reachable from the new root are
+// rewired under it with a contains edge to avoid leaving them dangling.
+func (d *Document) SetRoot(id string) error {
+	if d.NodeList == nil || d.NodeList.GetNodeByID(id) == nil {
+		return fmt.Errorf("node %q not found in document", id)
+	}
+
+	reachable := d.NodeList.reachableNodes(id)
+	dangling := []string{}
+	for _, rootID := range d.NodeList.RootElements {
+		if _, ok := reachable[rootID]; ok {
+			continue
+		}
+		if d.NodeList.GetNodeByID(rootID) == nil {
+			continue
+		}
+		dangling = append(dangling, rootID)
+		reachable[rootID] = struct{}{}
+	}
+
+	if len(dangling) > 0 {
+		if e := d.NodeList.GetEdgeByType(id, Edge_contains); e != nil {
+			e.To = append(e.To, dangling...)
+		} else {
+			d.NodeList.AddEdge(&Edge{Type: Edge_contains, From: id, To: dangling})
+		}
+	}
+
+	d.NodeList.RootElements = []string{id}
+	return nil
+}
+
+// EnsureDescribes makes sure the document describes at least one node, as
+// required by SPDX. Root elements not found in the nodes are removed and,
+// if no roots are left, they are inferred with DescribedElements.
+func (d *Document) EnsureDescribes() {
+	if d.NodeList == nil {
+		return
+	}
+	d.NodeList.RootElements = d.NodeList.DescribedElements()
+}
+
+// AddTool records a tool that generated or processed the document in its
+// metadata. Adding a tool identical to one already listed is a noop so that
+// repeated processing stages don't duplicate entries.
+func (d *Document) AddTool(name, version, vendor string) {
+	if d.Metadata == nil {
+		d.Metadata = &Metadata{}
+	}
+
+	for _, t := range d.Metadata.Tools {
+		if t.Name == name && t.Version == version && t.Vendor == vendor {
+			return
+		}
+	}
+
+	d.Metadata.Tools = append(d.Metadata.Tools, &Tool{
+		Name:    name,
+		Version: version,
+		Vendor:  vendor,
+	})
+}
+
+// MarshalJSON renders the document as protobuf JSON. Fields are emitted in
+// the order of the proto definition and the output is compacted as protojson
+// intentionally randomizes its whitespace to discourage byte comparisons.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	data, err := protojson.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling document to protojson: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, fmt.Errorf("compacting document json: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads a document from its protobuf JSON representation
+func (d *Document) UnmarshalJSON(data []byte) error {
+	if err := protojson.Unmarshal(data, d); err != nil {
+		return fmt.Errorf("unmarshaling document from protojson: %w", err)
+	}
+	return nil
+}
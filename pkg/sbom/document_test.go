@@ -1 +1,191 @@
 package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSetRoot(t *testing.T) {
+	doc := NewDocument()
+	doc.NodeList = &NodeList{
+		Nodes: []*Node{
+			{Id: "app"}, {Id: "lib"}, {Id: "tool"}, {Id: "docs"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib"}},
+		},
+		RootElements: []string{"app", "lib", "tool", "docs"},
+	}
+
+	require.Error(t, doc.SetRoot("nonexistent"))
+	require.Len(t, doc.NodeList.RootElements, 4)
+
+	require.NoError(t, doc.SetRoot("app"))
+	require.Equal(t, []string{"app"}, doc.NodeList.RootElements)
+
+	// lib was already reachable, tool and docs are rewired under app
+	e := doc.NodeList.GetEdgeByType("app", Edge_contains)
+	require.NotNil(t, e)
+	require.Equal(t, []string{"tool", "docs"}, e.To)
+	require.Len(t, doc.NodeList.Edges, 2)
+
+	// Setting the root again does not add new edges
+	require.NoError(t, doc.SetRoot("app"))
+	require.Len(t, doc.NodeList.Edges, 2)
+	require.Equal(t, []string{"tool", "docs"}, e.To)
+}
+
+func TestEnsureDescribes(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		nl       *NodeList
+		expected []string
+	}{
+		{
+			name: "existing roots are kept",
+			nl: &NodeList{
+				Nodes:        []*Node{{Id: "a"}, {Id: "b"}},
+				RootElements: []string{"b", "missing"},
+			},
+			expected: []string{"b"},
+		},
+		{
+			name: "nodes without incoming edges",
+			nl: &NodeList{
+				Nodes: []*Node{{Id: "a"}, {Id: "b"}, {Id: "c"}},
+				Edges: []*Edge{{Type: Edge_dependsOn, From: "a", To: []string{"b"}}},
+			},
+			expected: []string{"a", "c"},
+		},
+		{
+			name: "cycle",
+			nl: &NodeList{
+				Nodes: []*Node{{Id: "a"}, {Id: "b"}},
+				Edges: []*Edge{
+					{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+					{Type: Edge_dependsOn, From: "b", To: []string{"a"}},
+				},
+			},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "empty",
+			nl:       &NodeList{RootElements: []string{"missing"}},
+			expected: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := &Document{NodeList: tc.nl}
+			doc.EnsureDescribes()
+			require.Equal(t, tc.expected, doc.NodeList.RootElements)
+		})
+	}
+}
+
+func TestDocumentJSONRoundTrip(t *testing.T) {
+	doc := NewDocument()
+	doc.Metadata.Id = "urn:uuid:test"
+	doc.Metadata.Name = "test document"
+	doc.Metadata.Date = timestamppb.Now()
+	doc.Metadata.Authors = append(doc.Metadata.Authors, &Person{Name: "John Doe", Email: "john@example.com"})
+	doc.NodeList = &NodeList{
+		Nodes: []*Node{
+			{
+				Id:          "app",
+				Name:        "app",
+				Version:     "1.0.0",
+				Hashes:      map[string]string{"SHA256": "abc", "SHA1": "def"},
+				Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:generic/app@1.0.0"},
+				ExternalReferences: []*ExternalReference{
+					{ReferenceType: ExternalReference_VCS, Url: "https://github.com/example/app"},
+				},
+			},
+			{Id: "lib", Name: "lib", Type: Node_FILE},
+		},
+		Edges:        []*Edge{{Type: Edge_dependsOn, From: "app", To: []string{"lib"}}},
+		RootElements: []string{"app"},
+	}
+
+	data, err := doc.MarshalJSON()
+	require.NoError(t, err)
+
+	// The output must be stable
+	data2, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.Equal(t, data, data2)
+
+	doc2 := &Document{}
+	require.NoError(t, doc2.UnmarshalJSON(data))
+	require.True(t, proto.Equal(doc, doc2))
+	require.True(t, doc.NodeList.Equal(doc2.NodeList))
+
+	require.Error(t, doc2.UnmarshalJSON([]byte("{\"bogus\": 1}")))
+}
+
+func TestAddTool(t *testing.T) {
+	doc := &Document{}
+	doc.AddTool("protobom", "v0.1.0", "BOM Squad")
+	doc.AddTool("protobom", "v0.1.0", "BOM Squad")
+	doc.AddTool("protobom", "v0.2.0", "BOM Squad")
+	require.Len(t, doc.Metadata.Tools, 2)
+	require.Equal(t, "v0.2.0", doc.Metadata.Tools[1].Version)
+}
+
+func TestClone(t *testing.T) {
+	doc := NewDocument()
+	doc.Metadata.Name = "original"
+	doc.AddTool("protobom", "v0.1.0", "BOM Squad")
+	doc.NodeList = &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app", Hashes: map[string]string{"SHA256": "abc"}},
+			{Id: "lib", Name: "lib"},
+		},
+		Edges:        []*Edge{{Type: Edge_dependsOn, From: "app", To: []string{"lib"}}},
+		RootElements: []string{"app"},
+	}
+	original := proto.Clone(doc).(*Document)
+
+	clone := doc.Clone()
+	require.True(t, proto.Equal(doc, clone))
+
+	clone.Metadata.Name = "clone"
+	clone.Metadata.Tools[0].Version = "v0.2.0"
+	clone.AddTool("other", "v1", "")
+	clone.NodeList.Nodes[0].Name = "changed"
+	clone.NodeList.Nodes[0].Hashes["SHA256"] = "def"
+	clone.NodeList.Edges[0].To = append(clone.NodeList.Edges[0].To, "app")
+	clone.NodeList.RemoveNodes([]string{"lib"})
+	require.True(t, proto.Equal(original, doc))
+
+	require.Nil(t, (*Document)(nil).Clone())
+	empty := (&Document{}).Clone()
+	require.Nil(t, empty.Metadata)
+	require.Nil(t, empty.NodeList)
+}
+
+func TestDocumentJSONLocation(t *testing.T) {
+	doc := NewDocument()
+	doc.NodeList.AddNode(&Node{
+		Id:       "file1",
+		Type:     Node_FILE,
+		Name:     "main.go",
+		FileName: "cmd/main.go",
+		Location: &SourceLocation{StartLine: 10, EndLine: 12, StartByte: 230, EndByte: 310},
+	})
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	doc2 := &Document{}
+	require.NoError(t, json.Unmarshal(data, doc2))
+	n := doc2.NodeList.GetNodeByID("file1")
+	require.NotNil(t, n)
+	require.Equal(t, "cmd/main.go", n.FileName)
+	require.Equal(t, int64(230), n.Location.StartByte)
+	require.Equal(t, int64(310), n.Location.EndByte)
+	require.True(t, n.Equal(doc.NodeList.Nodes[0]))
+}
@@ -0,0 +1,163 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dotNodeColors are the fill colors used when coloring nodes by type
+var dotNodeColors = map[Node_NodeType]string{
+	Node_PACKAGE: "lightblue",
+	Node_FILE:    "lightyellow",
+}
+
+type dotOptions struct {
+	colorByType      bool
+	collapseContains bool
+}
+
+// DOTOption is a function that configures how WriteDOT renders the graph
+type DOTOption func(*dotOptions)
+
+// WithDOTColorByType fills the nodes with a different color per node type
+func WithDOTColorByType() DOTOption {
+	return func(o *dotOptions) {
+		o.colorByType = true
+	}
+}
+
+// WithDOTCollapseContains hides the nodes related to others with a contains
+// edge. Their edges are redirected to the top level node containing them,
+// which is labeled with the number of nodes collapsed into it.
+func WithDOTCollapseContains() DOTOption {
+	return func(o *dotOptions) {
+		o.collapseContains = true
+	}
+}
+
+// WriteDOT writes the NodeList graph to w as a Graphviz digraph. Nodes are
+// labeled with their name and version and edges with their type.
+func (nl *NodeList) WriteDOT(w io.Writer, opts ...DOTOption) error {
+	o := &dotOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	containers := map[string]string{}
+	if o.collapseContains {
+		containers = nl.topContainers()
+	}
+
+	collapsed := map[string]int{}
+	for _, container := range containers {
+		collapsed[container]++
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph protobom {\n")
+
+	for _, n := range nl.Nodes {
+		if _, ok := containers[n.Id]; ok {
+			continue
+		}
+		label := n.Name
+		if label == "" {
+			label = n.Id
+		}
+		if n.Version != "" {
+			label += "@" + n.Version
+		}
+		if c := collapsed[n.Id]; c > 0 {
+			label += fmt.Sprintf(" (+%d)", c)
+		}
+
+		attrs := []string{"label=" + dotQuote(label)}
+		if o.colorByType {
+			attrs = append(attrs, "style=filled", "fillcolor="+dotQuote(dotNodeColors[n.Type]))
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", dotQuote(n.Id), strings.Join(attrs, ", "))
+	}
+
+	seen := map[string]struct{}{}
+	for _, e := range nl.Edges {
+		if o.collapseContains && e.Type == Edge_contains {
+			continue
+		}
+		from := e.From
+		if c, ok := containers[from]; ok {
+			from = c
+		}
+		for _, to := range e.To {
+			if c, ok := containers[to]; ok {
+				to = c
+			}
+			if from == to {
+				continue
+			}
+			key := from + "+++" + to + "+++" + e.Type.String()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", dotQuote(from), dotQuote(to), dotQuote(e.Type.String()))
+		}
+	}
+
+	sb.WriteString("}\n")
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing dot graph: %w", err)
+	}
+	return nil
+}
+
+// topContainers returns a map of all nodes that are the target of a contains
+// edge, pointing to the top level node that contains them
+func (nl *NodeList) topContainers() map[string]string {
+	parents := map[string]string{}
+	for _, e := range nl.Edges {
+		if e.Type != Edge_contains {
+			continue
+		}
+		for _, to := range e.To {
+			if _, ok := parents[to]; !ok && to != e.From {
+				parents[to] = e.From
+			}
+		}
+	}
+
+	ret := map[string]string{}
+	for id := range parents {
+		top := id
+		visited := map[string]struct{}{}
+		for {
+			p, ok := parents[top]
+			if !ok {
+				break
+			}
+			// Break cycles by stopping at the first repeated node
+			if _, ok := visited[p]; ok {
+				break
+			}
+			visited[top] = struct{}{}
+			top = p
+		}
+		if top != id {
+			ret[id] = top
+		}
+	}
+
+	// Nodes in a containment cycle are not collapsed
+	for id, top := range ret {
+		if _, ok := ret[top]; ok {
+			delete(ret, id)
+		}
+	}
+	return ret
+}
+
+// dotQuote returns s as a quoted DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,55 @@
+package sbom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDOTNodeList() *NodeList {
+	return &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app", Version: "1.0"},
+			{Id: "lib", Name: "lib", Version: "2.0"},
+			{Id: "file", Name: "main.go", Type: Node_FILE},
+			{Id: "dep", Name: "dep"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "app", To: []string{"lib"}},
+			{Type: Edge_contains, From: "lib", To: []string{"file"}},
+			{Type: Edge_dependsOn, From: "lib", To: []string{"dep"}},
+		},
+		RootElements: []string{"app"},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testDOTNodeList().WriteDOT(&buf))
+	require.Equal(t, `digraph protobom {
+  "app" [label="app@1.0"];
+  "lib" [label="lib@2.0"];
+  "file" [label="main.go"];
+  "dep" [label="dep"];
+  "app" -> "lib" [label="contains"];
+  "lib" -> "file" [label="contains"];
+  "lib" -> "dep" [label="dependsOn"];
+}
+`, buf.String())
+}
+
+func TestWriteDOTOptions(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testDOTNodeList().WriteDOT(&buf, WithDOTColorByType(), WithDOTCollapseContains()))
+	require.Equal(t, `digraph protobom {
+  "app" [label="app@1.0 (+2)", style=filled, fillcolor="lightblue"];
+  "dep" [label="dep", style=filled, fillcolor="lightblue"];
+  "app" -> "dep" [label="dependsOn"];
+}
+`, buf.String())
+}
+
+func TestDOTQuote(t *testing.T) {
+	require.Equal(t, `"say \"hi\" \\o/"`, dotQuote(`say "hi" \o/`))
+}
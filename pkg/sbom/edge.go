@@ -1,6 +1,7 @@
 package sbom
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 )
@@ -8,12 +9,18 @@ import (
 // Copy returns a new edge with copies of all edges
 func (e *Edge) Copy() *Edge {
 	return &Edge{
-		Type: e.Type,
-		From: e.From,
-		To:   e.To,
+		Type:  e.Type,
+		From:  e.From,
+		To:    append([]string{}, e.To...),
+		Label: e.Label,
 	}
 }
 
+// SortTo sorts the destinations of the edge alphabetically
+func (e *Edge) SortTo() {
+	sort.Strings(e.To)
+}
+
 // PointsTo returns true if an edge points to a node, in other words if it has
 // id in its list of Tos
 func (e *Edge) PointsTo(id string) bool {
@@ -123,6 +130,63 @@ func (et Edge_Type) ToSPDX2() string {
 	}
 }
 
+// dependentEdgeTypes are the edge types that point from a dependency or
+// part to the element using it, like the SPDX 2 *_OF relationships
+var dependentEdgeTypes = map[Edge_Type]struct{}{
+	Edge_buildDependency:    {},
+	Edge_buildTool:          {},
+	Edge_contained_by:       {},
+	Edge_dataFile:           {},
+	Edge_dependencyManifest: {},
+	Edge_dependencyOf:       {},
+	Edge_describedBy:        {},
+	Edge_devDependency:      {},
+	Edge_devTool:            {},
+	Edge_documentation:      {},
+	Edge_example:            {},
+	Edge_metafile:           {},
+	Edge_optionalComponent:  {},
+	Edge_optionalDependency: {},
+	Edge_packages:           {},
+	Edge_prerequisiteFor:    {},
+	Edge_providedDependency: {},
+	Edge_runtimeDependency:  {},
+	Edge_test:               {},
+	Edge_testCase:           {},
+	Edge_testDependency:     {},
+	Edge_testTool:           {},
+}
+
+// PointsToDependent returns true if edges of the type go from a dependency
+// or part to the element that uses it, eg jest devDependency app, instead
+// of from the element to its parts as in app dependsOn lodash
+func (et Edge_Type) PointsToDependent() bool {
+	_, ok := dependentEdgeTypes[et]
+	return ok
+}
+
+// spdx2InverseRelationships are the SPDX 2 relationships that point in the
+// opposite direction of an edge type, which they are normalized to
+var spdx2InverseRelationships = map[string]Edge_Type{
+	"CONTAINED_BY":     Edge_contains,
+	"DEPENDENCY_OF":    Edge_dependsOn,
+	"DESCRIBED_BY":     Edge_describes,
+	"GENERATED_FROM":   Edge_generates,
+	"PREREQUISITE_FOR": Edge_prerequisite,
+}
+
+// EdgeTypeFromSPDX2Relationship returns the edge type of an SPDX 2
+// relationship normalized to the direction of the protobom edges. For
+// relationships that are the inverse of an edge type it returns that type
+// and true, meaning the elements must be swapped: A CONTAINED_BY B becomes
+// B contains A. Other relationships are mapped as in EdgeTypeFromSPDX2.
+func EdgeTypeFromSPDX2Relationship(spdx2Type string) (Edge_Type, bool) {
+	if t, ok := spdx2InverseRelationships[strings.ToUpper(spdx2Type)]; ok {
+		return t, true
+	}
+	return EdgeTypeFromSPDX2(spdx2Type), false
+}
+
 func EdgeTypeFromSPDX2(spdx2Type string) Edge_Type {
 	spdx2Type = strings.ToUpper(spdx2Type)
 
@@ -222,18 +286,68 @@ func EdgeTypeFromSPDX2(spdx2Type string) Edge_Type {
 	}
 }
 
-// Equal compares Edge e to e2 and returns true if they are the same
+// CanonicalName returns the human readable name of the edge type. Names
+// follow the SPDX 2 relationship vocabulary (eg DEPENDS_ON) and can be
+// parsed back with ParseEdgeType.
+func (et Edge_Type) CanonicalName() string {
+	if et == Edge_UNKNOWN {
+		return "UNKNOWN"
+	}
+	return et.ToSPDX2()
+}
+
+// ParseEdgeType returns the edge type from its canonical name. Names are
+// matched case insensitively and the protobuf enum names (eg dependsOn)
+// are accepted too.
+func ParseEdgeType(s string) (Edge_Type, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "UNKNOWN") {
+		return Edge_UNKNOWN, nil
+	}
+
+	for v, name := range Edge_Type_name {
+		et := Edge_Type(v)
+		if strings.EqualFold(s, et.CanonicalName()) || strings.EqualFold(s, name) {
+			return et, nil
+		}
+	}
+	return Edge_UNKNOWN, fmt.Errorf("unknown edge type %q", s)
+}
+
+// Equal compares Edge e to e2 and returns true if they are the same. The
+// destinations are compared as a set: their order and repeated entries are
+// not significant.
 func (e *Edge) Equal(e2 *Edge) bool {
-	if e2 == nil {
+	if e == nil || e2 == nil {
+		return e == nil && e2 == nil
+	}
+	if e.Type != e2.Type || e.From != e2.From || e.Label != e2.Label {
 		return false
 	}
-	return e.flatString() == e2.flatString()
+
+	tos := map[string]struct{}{}
+	for _, id := range e.To {
+		tos[id] = struct{}{}
+	}
+	tos2 := map[string]struct{}{}
+	for _, id := range e2.To {
+		if _, ok := tos[id]; !ok {
+			return false
+		}
+		tos2[id] = struct{}{}
+	}
+	return len(tos) == len(tos2)
 }
 
 // flatString returns the edge serialized into a string that can be used
-// to index or compare the contents of Edge e
+// to index or compare the contents of Edge e. The order of the
+// destination nodes is not significant.
 func (e *Edge) flatString() string {
-	tos := e.To
+	tos := append([]string{}, e.To...)
 	sort.Strings(tos)
-	return e.From + ":" + e.Type.String() + ":" + strings.Join(tos, "+")
+	ret := e.From + ":" + e.Type.String() + ":" + strings.Join(tos, "+")
+	if e.Label != "" {
+		ret += ":" + e.Label
+	}
+	return ret
 }
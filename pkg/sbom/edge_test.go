@@ -0,0 +1,137 @@
+package sbom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeEqual(t *testing.T) {
+	for m, tc := range map[string]struct {
+		e1, e2 *Edge
+		expect bool
+	}{
+		"same edge": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b", "c"}},
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b", "c"}},
+			true,
+		},
+		"to in different order": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"c", "b", "d"}},
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"d", "b", "c"}},
+			true,
+		},
+		"different type": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			&Edge{Type: Edge_contains, From: "a", To: []string{"b"}},
+			false,
+		},
+		"different from": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			&Edge{Type: Edge_dependsOn, From: "x", To: []string{"b"}},
+			false,
+		},
+		"different to": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b", "c"}},
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			false,
+		},
+		"repeated to": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b", "c", "b"}},
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"c", "b"}},
+			true,
+		},
+		"to with separator": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b+c"}},
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b", "c"}},
+			false,
+		},
+		"different label": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b"}, Label: "build"},
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			false,
+		},
+		"nil": {
+			&Edge{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			nil,
+			false,
+		},
+	} {
+		require.Equal(t, tc.expect, tc.e1.Equal(tc.e2), m)
+		require.Equal(t, tc.expect, tc.e2.Equal(tc.e1), m)
+	}
+
+	// Comparing must not reorder the edge destinations
+	e := &Edge{Type: Edge_dependsOn, From: "a", To: []string{"c", "b"}}
+	require.True(t, e.Equal(e.Copy()))
+	require.Equal(t, []string{"c", "b"}, e.To)
+}
+
+func TestEdgeTypePointsToDependent(t *testing.T) {
+	require.False(t, Edge_dependsOn.PointsToDependent())
+	require.False(t, Edge_contains.PointsToDependent())
+	require.True(t, Edge_devDependency.PointsToDependent())
+	require.True(t, Edge_contained_by.PointsToDependent())
+	require.True(t, Edge_dependencyOf.PointsToDependent())
+}
+
+func TestParseEdgeType(t *testing.T) {
+	seen := map[string]Edge_Type{}
+	for v := range Edge_Type_name {
+		et := Edge_Type(v)
+		name := et.CanonicalName()
+		require.NotEmpty(t, name)
+		require.NotContains(t, seen, name, "%s and %s share a name", et, seen[name])
+		seen[name] = et
+
+		parsed, err := ParseEdgeType(name)
+		require.NoError(t, err)
+		require.Equal(t, et, parsed)
+
+		parsed, err = ParseEdgeType(et.String())
+		require.NoError(t, err)
+		require.Equal(t, et, parsed)
+	}
+
+	et, err := ParseEdgeType(" depends_on ")
+	require.NoError(t, err)
+	require.Equal(t, Edge_dependsOn, et)
+
+	_, err = ParseEdgeType("USES")
+	require.Error(t, err)
+}
+
+func TestEdgeTypeFromSPDX2Relationship(t *testing.T) {
+	for _, tc := range []struct {
+		relationship string
+		expected     Edge_Type
+		inverse      bool
+	}{
+		{"CONTAINED_BY", Edge_contains, true},
+		{"dependency_of", Edge_dependsOn, true},
+		{"DESCRIBED_BY", Edge_describes, true},
+		{"GENERATED_FROM", Edge_generates, true},
+		{"PREREQUISITE_FOR", Edge_prerequisite, true},
+		{"PATCH_APPLIED", Edge_patch, false},
+		{"CONTAINS", Edge_contains, false},
+		{"NOT_A_RELATIONSHIP", Edge_UNKNOWN, false},
+	} {
+		et, inverse := EdgeTypeFromSPDX2Relationship(tc.relationship)
+		require.Equal(t, tc.expected, et, tc.relationship)
+		require.Equal(t, tc.inverse, inverse, tc.relationship)
+		if tc.relationship == strings.ToUpper(tc.relationship) {
+			require.Equal(t, tc.expected, EdgeTypeFromSPDX(tc.relationship), tc.relationship)
+		}
+	}
+}
+
+func TestEdgeSortTo(t *testing.T) {
+	e := &Edge{Type: Edge_dependsOn, From: "a", To: []string{"d", "b", "c"}}
+	e.SortTo()
+	require.Equal(t, []string{"b", "c", "d"}, e.To)
+
+	e = &Edge{}
+	e.SortTo()
+	require.Empty(t, e.To)
+}
@@ -2,19 +2,35 @@ package sbom
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
 
 	"github.com/bom-squad/protobom/pkg/formats/spdx"
 )
 
+// EffectiveType returns the type of the external reference. References
+// read from documents serialized before the type was an enum only have
+// the free form type string, which is mapped from the SPDX and CycloneDX
+// type names.
+func (e *ExternalReference) EffectiveType() ExternalReference_ExternalReferenceType {
+	legacy := e.Type //nolint:staticcheck // Still read to support older documents
+	if e.ReferenceType != ExternalReference_UNKNOWN || legacy == "" {
+		return e.ReferenceType
+	}
+	return ExternalReferenceTypeFromSPDX2(legacy)
+}
+
 // ToSPDX2Category returns the type of the external reference in the
 // spdx 2.x vocabulary.
 func (e *ExternalReference) ToSPDX2Category() string {
-	switch e.ToSPDX2Type() {
-	case "cpe22Type", "cpe23Type", "advisory", "fix", "url", "swid":
+	switch e.EffectiveType() {
+	case ExternalReference_ADVISORIES, ExternalReference_FIX, ExternalReference_URL, ExternalReference_SWID:
 		return spdx.CategorySecurity
-	case "maven-central", "npm", "nuget", "bower", "purl":
+	case ExternalReference_MAVEN_CENTRAL, ExternalReference_NPM, ExternalReference_NUGET, ExternalReference_BOWER:
 		return spdx.CategoryPackageManager
-	case "swh", "gitoid":
+	case ExternalReference_SWH:
 		return spdx.CategoryPersistentID
 	default:
 		return spdx.CategoryOther
@@ -22,16 +38,153 @@ func (e *ExternalReference) ToSPDX2Category() string {
 }
 
 // ToSPDX2Type converts the external reference type to the SPDX 2.x equivalent.
+// Types without an SPDX counterpart are returned in their CycloneDX form as
+// they are still valid in the SPDX "OTHER" category.
 func (e *ExternalReference) ToSPDX2Type() string {
-	// TODO: Shoud we be more prescriptive here?
-	return e.Type
+	switch e.EffectiveType() {
+	case ExternalReference_UNKNOWN:
+		return ""
+	case ExternalReference_ADVISORIES:
+		return spdx.ExtRefTypeAdvisory
+	case ExternalReference_FIX:
+		return spdx.ExtRefTypeFix
+	case ExternalReference_URL:
+		return spdx.ExtRefTypeURL
+	case ExternalReference_SWID:
+		return spdx.ExtRefTypeSwid
+	case ExternalReference_MAVEN_CENTRAL:
+		return spdx.ExtRefTypeMavenCentral
+	case ExternalReference_NPM:
+		return spdx.ExtRefTypeNpm
+	case ExternalReference_NUGET:
+		return spdx.ExtRefTypeNuget
+	case ExternalReference_BOWER:
+		return spdx.ExtRefTypeBower
+	case ExternalReference_SWH:
+		return spdx.ExtRefTypeSwh
+	default:
+		return string(e.ToCycloneDX())
+	}
+}
+
+// ToCycloneDX returns the CycloneDX type of the external reference. Types that
+// only exist in SPDX are degraded to "other".
+func (e *ExternalReference) ToCycloneDX() cdx.ExternalReferenceType {
+	switch e.EffectiveType() {
+	case ExternalReference_ADVISORIES:
+		return cdx.ERTypeAdvisories
+	case ExternalReference_BOM:
+		return cdx.ERTypeBOM
+	case ExternalReference_BUILD_META:
+		return cdx.ERTypeBuildMeta
+	case ExternalReference_BUILD_SYSTEM:
+		return cdx.ERTypeBuildSystem
+	case ExternalReference_CHAT:
+		return cdx.ERTypeChat
+	case ExternalReference_DISTRIBUTION:
+		return cdx.ERTypeDistribution
+	case ExternalReference_DOCUMENTATION:
+		return cdx.ERTypeDocumentation
+	case ExternalReference_ISSUE_TRACKER:
+		return cdx.ERTypeIssueTracker
+	case ExternalReference_LICENSE:
+		return cdx.ERTypeLicense
+	case ExternalReference_MAILING_LIST:
+		return cdx.ERTypeMailingList
+	case ExternalReference_RELEASE_NOTES:
+		return cdx.ERTypeReleaseNotes
+	case ExternalReference_SOCIAL:
+		return cdx.ERTypeSocial
+	case ExternalReference_SUPPORT:
+		return cdx.ERTypeSupport
+	case ExternalReference_VCS:
+		return cdx.ERTypeVCS
+	case ExternalReference_WEBSITE:
+		return cdx.ERTypeWebsite
+	default:
+		// TODO(degradation): SPDX-only types are lost when rendering to CycloneDX
+		return cdx.ERTypeOther
+	}
+}
+
+// ExternalReferenceTypeFromSPDX2 returns the external reference type from one
+// of the SPDX 2.x reference type strings. Any unrecognized type is returned
+// as ExternalReference_OTHER as SPDX allows arbitrary types in that category.
+func ExternalReferenceTypeFromSPDX2(spdx2Type string) ExternalReference_ExternalReferenceType {
+	switch spdx2Type {
+	case spdx.ExtRefTypeAdvisory:
+		return ExternalReference_ADVISORIES
+	case spdx.ExtRefTypeFix:
+		return ExternalReference_FIX
+	case spdx.ExtRefTypeURL:
+		return ExternalReference_URL
+	case spdx.ExtRefTypeSwid:
+		return ExternalReference_SWID
+	case spdx.ExtRefTypeMavenCentral:
+		return ExternalReference_MAVEN_CENTRAL
+	case spdx.ExtRefTypeNpm:
+		return ExternalReference_NPM
+	case spdx.ExtRefTypeNuget:
+		return ExternalReference_NUGET
+	case spdx.ExtRefTypeBower:
+		return ExternalReference_BOWER
+	case spdx.ExtRefTypeSwh:
+		return ExternalReference_SWH
+	}
+
+	// Types in the OTHER category may use the CycloneDX vocabulary
+	if t := ExternalReferenceTypeFromCycloneDX(cdx.ExternalReferenceType(strings.ToLower(spdx2Type))); t != ExternalReference_UNKNOWN {
+		return t
+	}
+	return ExternalReference_OTHER
+}
+
+// ExternalReferenceTypeFromCycloneDX returns the external reference type
+// corresponding to a CycloneDX external reference type.
+func ExternalReferenceTypeFromCycloneDX(cdxType cdx.ExternalReferenceType) ExternalReference_ExternalReferenceType {
+	switch cdxType {
+	case cdx.ERTypeAdvisories:
+		return ExternalReference_ADVISORIES
+	case cdx.ERTypeBOM:
+		return ExternalReference_BOM
+	case cdx.ERTypeBuildMeta:
+		return ExternalReference_BUILD_META
+	case cdx.ERTypeBuildSystem:
+		return ExternalReference_BUILD_SYSTEM
+	case cdx.ERTypeChat:
+		return ExternalReference_CHAT
+	case cdx.ERTypeDistribution:
+		return ExternalReference_DISTRIBUTION
+	case cdx.ERTypeDocumentation:
+		return ExternalReference_DOCUMENTATION
+	case cdx.ERTypeIssueTracker:
+		return ExternalReference_ISSUE_TRACKER
+	case cdx.ERTypeLicense:
+		return ExternalReference_LICENSE
+	case cdx.ERTypeMailingList:
+		return ExternalReference_MAILING_LIST
+	case cdx.ERTypeOther:
+		return ExternalReference_OTHER
+	case cdx.ERTypeReleaseNotes:
+		return ExternalReference_RELEASE_NOTES
+	case cdx.ERTypeSocial:
+		return ExternalReference_SOCIAL
+	case cdx.ERTypeSupport:
+		return ExternalReference_SUPPORT
+	case cdx.ERTypeVCS:
+		return ExternalReference_VCS
+	case cdx.ERTypeWebsite:
+		return ExternalReference_WEBSITE
+	default:
+		return ExternalReference_UNKNOWN
+	}
 }
 
 // flatString returns a deterministic string that can be used to hash the external reference
 func (e *ExternalReference) flatString() string {
 	ret := ""
-	if e.Type != "" {
-		ret += fmt.Sprintf("(t)%s", e.Type)
+	if t := e.EffectiveType(); t != ExternalReference_UNKNOWN {
+		ret += fmt.Sprintf("(t)%s", t)
 	}
 	if e.Url != "" {
 		ret += fmt.Sprintf("(u)%s", e.Url)
@@ -45,5 +198,16 @@ func (e *ExternalReference) flatString() string {
 		ret += fmt.Sprintf("(a)%s", e.Authority)
 	}
 
+	if len(e.Hashes) > 0 {
+		algos := []string{}
+		for algo := range e.Hashes {
+			algos = append(algos, algo)
+		}
+		sort.Strings(algos)
+		for _, algo := range algos {
+			ret += fmt.Sprintf("(h)%s:%s", algo, e.Hashes[algo])
+		}
+	}
+
 	return ret
 }
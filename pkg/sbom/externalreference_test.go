@@ -0,0 +1,76 @@
+package sbom
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bom-squad/protobom/pkg/formats/spdx"
+)
+
+func TestExtRefToSPDX2(t *testing.T) {
+	for _, tc := range []struct {
+		sut              *ExternalReference
+		expectedType     string
+		expectedCategory string
+	}{
+		{&ExternalReference{ReferenceType: ExternalReference_ADVISORIES}, "advisory", spdx.CategorySecurity},
+		{&ExternalReference{ReferenceType: ExternalReference_NPM}, "npm", spdx.CategoryPackageManager},
+		{&ExternalReference{ReferenceType: ExternalReference_SWH}, "swh", spdx.CategoryPersistentID},
+		{&ExternalReference{ReferenceType: ExternalReference_VCS}, "vcs", spdx.CategoryOther},
+		{&ExternalReference{ReferenceType: ExternalReference_UNKNOWN}, "", spdx.CategoryOther},
+	} {
+		require.Equal(t, tc.expectedType, tc.sut.ToSPDX2Type())
+		require.Equal(t, tc.expectedCategory, tc.sut.ToSPDX2Category())
+	}
+}
+
+func TestExtRefTypeRoundTrip(t *testing.T) {
+	for i := range ExternalReference_ExternalReferenceType_name {
+		extRef := &ExternalReference{ReferenceType: ExternalReference_ExternalReferenceType(i)}
+		if extRef.ReferenceType == ExternalReference_UNKNOWN {
+			continue
+		}
+		require.Equal(t, extRef.ReferenceType, ExternalReferenceTypeFromSPDX2(extRef.ToSPDX2Type()), extRef.ReferenceType.String())
+
+		// SPDX only types degrade to "other" in CycloneDX
+		cdxType := ExternalReferenceTypeFromCycloneDX(extRef.ToCycloneDX())
+		if extRef.ToCycloneDX() == cdx.ERTypeOther {
+			require.Equal(t, ExternalReference_OTHER, cdxType)
+			continue
+		}
+		require.Equal(t, extRef.ReferenceType, cdxType)
+	}
+	require.Equal(t, ExternalReference_OTHER, ExternalReferenceTypeFromSPDX2("my-custom-type"))
+}
+
+func TestExtRefEffectiveType(t *testing.T) {
+	for _, tc := range []struct {
+		sut      *ExternalReference
+		expected ExternalReference_ExternalReferenceType
+	}{
+		{&ExternalReference{}, ExternalReference_UNKNOWN},
+		{&ExternalReference{ReferenceType: ExternalReference_NPM}, ExternalReference_NPM},
+		{&ExternalReference{Type: "vcs"}, ExternalReference_VCS},
+		{&ExternalReference{Type: "VCS"}, ExternalReference_VCS},
+		{&ExternalReference{Type: "advisory"}, ExternalReference_ADVISORIES},
+		{&ExternalReference{Type: "my-custom-type"}, ExternalReference_OTHER},
+		{&ExternalReference{Type: "vcs", ReferenceType: ExternalReference_WEBSITE}, ExternalReference_WEBSITE},
+	} {
+		require.Equal(t, tc.expected, tc.sut.EffectiveType())
+	}
+
+	// References serialized when the type was a string still decode
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendString(data, "https://github.com/example/app")
+	data = protowire.AppendTag(data, 2, protowire.BytesType)
+	data = protowire.AppendString(data, "vcs")
+	legacy := &ExternalReference{}
+	require.NoError(t, proto.Unmarshal(data, legacy))
+	require.Equal(t, "https://github.com/example/app", legacy.Url)
+	require.Equal(t, ExternalReference_VCS, legacy.EffectiveType())
+	require.Equal(t, "vcs", legacy.ToSPDX2Type())
+}
@@ -2,19 +2,56 @@ package sbom
 
 import (
 	"fmt"
+	"math/rand"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/CycloneDX/cyclonedx-go"
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/uuid"
+
+	"github.com/bom-squad/protobom/pkg/formats/spdx"
 )
 
 const NodeIdentifierPrefix = "protobom"
 
 var invalidIDCharsRe = regexp.MustCompile(`[^a-zA-Z0-9-.]+`)
 
+var (
+	idSourceMu sync.RWMutex
+	idSource   = uuid.NewString
+)
+
+// SetIDSource replaces the function NewNodeIdentifier uses to generate
+// identifiers when it gets no seed strings. The strings it returns must
+// only contain the characters valid in identifiers: letters, digits, dots
+// and dashes. Passing nil restores the default random UUIDs.
+func SetIDSource(fn func() string) {
+	idSourceMu.Lock()
+	defer idSourceMu.Unlock()
+	if fn == nil {
+		fn = uuid.NewString
+	}
+	idSource = fn
+}
+
+// SeededIDSource returns an ID source for SetIDSource that generates a
+// deterministic sequence of UUIDs from seed, useful to build reproducible
+// documents. The returned function is safe for concurrent use.
+func SeededIDSource(seed int64) func() string {
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // Reproducible IDs are the point
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		// Reading from a math/rand source never fails
+		id, _ := uuid.NewRandomFromReader(rng)
+		return id.String()
+	}
+}
+
 // NewNodeIdentifier returns an identifier string that can be used in a node
 // and that is guaranteed to be compatible with CycloneDX and SPDX.
 //
@@ -25,7 +62,7 @@ var invalidIDCharsRe = regexp.MustCompile(`[^a-zA-Z0-9-.]+`)
 // come from an ingested SBOM.
 //
 // Without any strings seeding it, NewNodeIdentifier generates the identifier
-// using an UUID. If a string is provided, any invalid characters will be removed and the
+// using an UUID, or the ID source installed with SetIDSource. If a string is provided, any invalid characters will be removed and the
 // new string will be used as the identifier.
 func NewNodeIdentifier(prefixes ...string) string {
 	validPrefixes := []string{}
@@ -55,7 +92,9 @@ func NewNodeIdentifier(prefixes ...string) string {
 
 	// If we did not get any seeds, use an UUID
 	if len(validPrefixes) == 0 {
-		validPrefixes = append(validPrefixes, uuid.New().String())
+		idSourceMu.RLock()
+		validPrefixes = append(validPrefixes, idSource())
+		idSourceMu.RUnlock()
 	}
 
 	validPrefixes[0] = "-" + validPrefixes[0]
@@ -63,7 +102,16 @@ func NewNodeIdentifier(prefixes ...string) string {
 	return strings.Join(append(knownPrefixes, validPrefixes...), "-")
 }
 
+// EdgeTypeFromSPDX returns the edge type of an SPDX relationship name. The
+// relationships that are the inverse of an edge type (CONTAINED_BY,
+// DEPENDENCY_OF, DESCRIBED_BY, GENERATED_FROM and PREREQUISITE_FOR) return
+// that type and their elements must be swapped, use
+// EdgeTypeFromSPDX2Relationship to know when that is the case.
 func EdgeTypeFromSPDX(spdxName string) Edge_Type {
+	if t, ok := spdx2InverseRelationships[spdxName]; ok {
+		return t
+	}
+
 	switch spdxName {
 	case "AMENDS":
 		return Edge_amends
@@ -73,7 +121,6 @@ func EdgeTypeFromSPDX(spdxName string) Edge_Type {
 		return Edge_buildDependency
 	case "BUILD_TOOL_OF":
 		return Edge_buildTool
-		// case "CONTAINED_BY":
 	case "CONTAINS":
 		return Edge_contains
 	case "COPY_OF":
@@ -82,12 +129,10 @@ func EdgeTypeFromSPDX(spdxName string) Edge_Type {
 		return Edge_dataFile
 	case "DEPENDENCY_MANIFEST_OF":
 		return Edge_dependencyManifest
-		// case "DEPENDENCY_OF":
 	case "DEPENDS_ON":
 		return Edge_dependsOn
 	case "DESCENDANT_OF":
 		return Edge_descendant
-		// case "DESCRIBED_BY":
 	case "DESCRIBES":
 		return Edge_describes
 	case "DEV_DEPENDENCY_OF":
@@ -110,7 +155,6 @@ func EdgeTypeFromSPDX(spdxName string) Edge_Type {
 		return Edge_fileDeleted
 	case "FILE_MODIFIED":
 		return Edge_fileModified
-		// case "GENERATED_FROM":
 	case "GENERATES":
 		return Edge_generates
 	case "METAFILE_OF":
@@ -123,10 +167,10 @@ func EdgeTypeFromSPDX(spdxName string) Edge_Type {
 		return Edge_other
 	case "PACKAGE_OF":
 		return Edge_packages
-	// case "PATCH_APPLIED":
-	case "PATCH_FOR":
+	// TODO(degradation): PATCH_APPLIED is recorded as PATCH_FOR, both point
+	// from the patch to the patched element
+	case "PATCH_APPLIED", "PATCH_FOR":
 		return Edge_patch
-	// case "PREREQUISITE_FOR":
 	case "HAS_PREREQUISITE":
 		return Edge_prerequisite
 	case "PROVIDED_DEPENDENCY_OF":
@@ -184,3 +228,76 @@ func HashAlgorithmFromCDX(cdxAlgorithm cyclonedx.HashAlgorithm) HashAlgorithm {
 		return HashAlgorithm_UNKNOWN
 	}
 }
+
+// PurposeFromCDXComponentType returns the node primary purpose that
+// corresponds to a CycloneDX component type. Purposes are stored in the
+// SPDX 2.3 primaryPackagePurpose vocabulary. Component types without a
+// counterpart are returned as OTHER.
+func PurposeFromCDXComponentType(cdxType cdx.ComponentType) string {
+	switch cdxType {
+	case cdx.ComponentTypeApplication:
+		return spdx.PurposeApplication
+	case cdx.ComponentTypeContainer:
+		return spdx.PurposeContainer
+	case cdx.ComponentTypeDevice:
+		return spdx.PurposeDevice
+	case cdx.ComponentTypeFile:
+		return spdx.PurposeFile
+	case cdx.ComponentTypeFirmware:
+		return spdx.PurposeFirmware
+	case cdx.ComponentTypeFramework:
+		return spdx.PurposeFramework
+	case cdx.ComponentTypeLibrary:
+		return spdx.PurposeLibrary
+	case cdx.ComponentTypeOS:
+		return spdx.PurposeOperatingSystem
+	case "":
+		return ""
+	default:
+		return spdx.PurposeOther
+	}
+}
+
+// PurposeToSPDX returns the node purpose as a valid SPDX 2.3
+// primaryPackagePurpose value. The purpose is matched case insensitively,
+// unknown purposes are returned as OTHER.
+func PurposeToSPDX(purpose string) string {
+	purpose = strings.ToUpper(strings.TrimSpace(purpose))
+	switch purpose {
+	case "":
+		return ""
+	case spdx.PurposeApplication, spdx.PurposeFramework, spdx.PurposeLibrary,
+		spdx.PurposeContainer, spdx.PurposeOperatingSystem, spdx.PurposeDevice,
+		spdx.PurposeFirmware, spdx.PurposeSource, spdx.PurposeArchive,
+		spdx.PurposeFile, spdx.PurposeInstall, spdx.PurposeOther:
+		return purpose
+	default:
+		return spdx.PurposeOther
+	}
+}
+
+// PurposeToCDXComponentType returns the CycloneDX component type for a node
+// purpose. CycloneDX requires a type in all components so purposes without a
+// CycloneDX equivalent (and empty ones) are returned as a library.
+func PurposeToCDXComponentType(purpose string) cdx.ComponentType {
+	switch PurposeToSPDX(purpose) {
+	case spdx.PurposeApplication:
+		return cdx.ComponentTypeApplication
+	case spdx.PurposeFramework:
+		return cdx.ComponentTypeFramework
+	case spdx.PurposeContainer:
+		return cdx.ComponentTypeContainer
+	case spdx.PurposeOperatingSystem:
+		return cdx.ComponentTypeOS
+	case spdx.PurposeDevice:
+		return cdx.ComponentTypeDevice
+	case spdx.PurposeFirmware:
+		return cdx.ComponentTypeFirmware
+	case spdx.PurposeFile:
+		return cdx.ComponentTypeFile
+	default:
+		// TODO(degradation): SOURCE, ARCHIVE, INSTALL and OTHER have no
+		// equivalent in CycloneDX
+		return cdx.ComponentTypeLibrary
+	}
+}
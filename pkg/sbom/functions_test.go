@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"testing"
 
+	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +34,61 @@ func TestNewNodeIdentifier(t *testing.T) {
 		}
 	}
 }
+
+func TestSetIDSource(t *testing.T) {
+	build := func() []string {
+		SetIDSource(SeededIDSource(42))
+		ids := []string{}
+		for i := 0; i < 5; i++ {
+			ids = append(ids, NewNodeIdentifier())
+		}
+		return ids
+	}
+	t.Cleanup(func() { SetIDSource(nil) })
+
+	ids := build()
+	require.Equal(t, ids, build())
+	require.NotEqual(t, ids[0], ids[1])
+
+	// Another seed produces different identifiers
+	SetIDSource(SeededIDSource(1))
+	require.NotEqual(t, ids[0], NewNodeIdentifier())
+
+	// Seeded identifiers are not affected by the source
+	require.Equal(t, "protobom--hello", NewNodeIdentifier("hello"))
+
+	// Restoring the default source generates random identifiers again
+	SetIDSource(nil)
+	require.NotEqual(t, NewNodeIdentifier(), NewNodeIdentifier())
+}
+
+func TestPurposeRoundTrip(t *testing.T) {
+	for _, cdxType := range []cdx.ComponentType{
+		cdx.ComponentTypeApplication, cdx.ComponentTypeContainer, cdx.ComponentTypeDevice,
+		cdx.ComponentTypeFile, cdx.ComponentTypeFirmware, cdx.ComponentTypeFramework,
+		cdx.ComponentTypeLibrary, cdx.ComponentTypeOS,
+	} {
+		purpose := PurposeFromCDXComponentType(cdxType)
+		require.Equal(t, purpose, PurposeToSPDX(purpose))
+		require.Equal(t, cdxType, PurposeToCDXComponentType(purpose))
+	}
+
+	for _, tc := range []struct {
+		purpose string
+		spdx    string
+		cdx     cdx.ComponentType
+	}{
+		{"application", "APPLICATION", cdx.ComponentTypeApplication},
+		{"operating-system", "OPERATING-SYSTEM", cdx.ComponentTypeOS},
+		{"SOURCE", "SOURCE", cdx.ComponentTypeLibrary},
+		{"INSTALL", "INSTALL", cdx.ComponentTypeLibrary},
+		{"something", "OTHER", cdx.ComponentTypeLibrary},
+		{"", "", cdx.ComponentTypeLibrary},
+	} {
+		require.Equal(t, tc.spdx, PurposeToSPDX(tc.purpose), tc.purpose)
+		require.Equal(t, tc.cdx, PurposeToCDXComponentType(tc.purpose), tc.purpose)
+	}
+
+	require.Equal(t, "", PurposeFromCDXComponentType(""))
+	require.Equal(t, "OTHER", PurposeFromCDXComponentType("machine-learning-model"))
+}
@@ -0,0 +1,114 @@
+package sbom
+
+import (
+	"crypto/md5"  //nolint:gosec // Used to verify, not to secure
+	"crypto/sha1" //nolint:gosec // Used to verify, not to secure
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"io"
+	"os"
+	"strings"
+)
+
+// hashFunctions are the hash algorithms protobom can compute
+var hashFunctions = map[HashAlgorithm]func() hash.Hash{
+	HashAlgorithm_ADLER32: func() hash.Hash { return adler32.New() },
+	HashAlgorithm_MD5:     md5.New,
+	HashAlgorithm_SHA1:    sha1.New,
+	HashAlgorithm_SHA224:  sha256.New224,
+	HashAlgorithm_SHA256:  sha256.New,
+	HashAlgorithm_SHA384:  sha512.New384,
+	HashAlgorithm_SHA512:  sha512.New,
+}
+
+// hashReader reads r once computing the digests of all the algorithms. The
+// returned map is keyed by algorithm name, as in Node.Hashes.
+func hashReader(r io.Reader, algos []HashAlgorithm) (map[string]string, error) {
+	hashers := map[HashAlgorithm]hash.Hash{}
+	writers := []io.Writer{}
+	for _, algo := range algos {
+		newHash, ok := hashFunctions[algo]
+		if !ok {
+			return nil, fmt.Errorf("hash algorithm %s is not supported", algo)
+		}
+		if _, ok := hashers[algo]; ok {
+			continue
+		}
+		hashers[algo] = newHash()
+		writers = append(writers, hashers[algo])
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return nil, fmt.Errorf("reading data: %w", err)
+	}
+
+	ret := map[string]string{}
+	for algo, h := range hashers {
+		ret[algo.String()] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+	return ret, nil
+}
+
+// ComputeHashes reads r once, hashing it with all the algorithms and
+// records the digests in the node hashes, replacing existing values of the
+// same algorithms. If no algorithms are specified, SHA1, SHA256 and SHA512
+// are computed.
+func (n *Node) ComputeHashes(r io.Reader, algos ...HashAlgorithm) error {
+	if len(algos) == 0 {
+		algos = []HashAlgorithm{HashAlgorithm_SHA1, HashAlgorithm_SHA256, HashAlgorithm_SHA512}
+	}
+
+	digests, err := hashReader(r, algos)
+	if err != nil {
+		return fmt.Errorf("computing hashes: %w", err)
+	}
+
+	if n.Hashes == nil {
+		n.Hashes = map[string]string{}
+	}
+	for algo, digest := range digests {
+		n.Hashes[algo] = digest
+	}
+	return nil
+}
+
+// VerifyFile hashes the file at path with all the algorithms in the node
+// hashes and returns true if all digests match. A mismatch returns false,
+// an error is only returned if the file cannot be read or one of the
+// algorithms is not supported. Nodes without hashes cannot be verified and
+// return an error.
+func (n *Node) VerifyFile(path string) (bool, error) {
+	if len(n.Hashes) == 0 {
+		return false, fmt.Errorf("node %s has no hashes to verify", n.Id)
+	}
+
+	algos := []HashAlgorithm{}
+	for algoName := range n.Hashes {
+		algoVal, ok := HashAlgorithm_value[algoName]
+		if !ok {
+			return false, fmt.Errorf("unknown hash algorithm %q", algoName)
+		}
+		algos = append(algos, HashAlgorithm(algoVal))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	digests, err := hashReader(f, algos)
+	if err != nil {
+		return false, fmt.Errorf("hashing %s: %w", path, err)
+	}
+
+	for algoName, digest := range n.Hashes {
+		if !strings.EqualFold(digest, digests[algoName]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,99 @@
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello protobom\n"), 0o600))
+
+	for _, tc := range []struct {
+		name        string
+		hashes      map[string]string
+		expected    bool
+		shouldError bool
+	}{
+		{
+			name: "all match",
+			hashes: map[string]string{
+				"SHA1":   "fb39541be0f25900d41d5af742b2a6ccca53f5cb",
+				"SHA256": "2B8927718AE7B36A19FCB013C8295CCE3A6C552840AFF032DEB2805F5FD4ECAB",
+			},
+			expected: true,
+		},
+		{
+			name: "one algorithm mismatches",
+			hashes: map[string]string{
+				"SHA1":   "fb39541be0f25900d41d5af742b2a6ccca53f5cb",
+				"SHA256": "0000000000000000000000000000000000000000000000000000000000000000",
+			},
+			expected: false,
+		},
+		{
+			name:        "unsupported algorithm",
+			hashes:      map[string]string{"BLAKE3": "00"},
+			shouldError: true,
+		},
+		{
+			name:        "no hashes",
+			hashes:      map[string]string{},
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &Node{Id: "file", Hashes: tc.hashes}
+			ok, err := n.VerifyFile(path)
+			if tc.shouldError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ok)
+		})
+	}
+
+	_, err := (&Node{Hashes: map[string]string{"SHA1": "00"}}).VerifyFile(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestComputeHashes(t *testing.T) {
+	n := &Node{Id: "file", Hashes: map[string]string{"MD5": "stale", "SHA1": "stale"}}
+	require.NoError(t, n.ComputeHashes(
+		strings.NewReader("hello protobom\n"), HashAlgorithm_SHA1, HashAlgorithm_SHA256, HashAlgorithm_SHA256,
+	))
+	require.Equal(t, map[string]string{
+		"MD5":    "stale",
+		"SHA1":   "fb39541be0f25900d41d5af742b2a6ccca53f5cb",
+		"SHA256": "2b8927718ae7b36a19fcb013c8295cce3a6c552840aff032deb2805f5fd4ecab",
+	}, n.Hashes)
+
+	// The keys match the ones used in the hash index
+	nl := &NodeList{Nodes: []*Node{n}}
+	require.Len(t, nl.indexNodesByHash()["SHA256:2b8927718ae7b36a19fcb013c8295cce3a6c552840aff032deb2805f5fd4ecab"], 1)
+
+	n = &Node{}
+	require.NoError(t, n.ComputeHashes(strings.NewReader("")))
+	require.Len(t, n.Hashes, 3)
+	require.Equal(t, "da39a3ee5e6b4b0d3255bfef95601890afd80709", n.Hashes["SHA1"])
+
+	require.Error(t, n.ComputeHashes(strings.NewReader(""), HashAlgorithm_BLAKE3))
+}
+
+func TestHashAlgorithmFromString(t *testing.T) {
+	for s, expected := range map[string]HashAlgorithm{
+		"SHA256":      HashAlgorithm_SHA256,
+		"sha-256":     HashAlgorithm_SHA256,
+		"sha1":        HashAlgorithm_SHA1,
+		"SHA3-256":    HashAlgorithm_SHA3_256,
+		"blake2b-512": HashAlgorithm_BLAKE2B_512,
+		"crc32":       HashAlgorithm_UNKNOWN,
+	} {
+		require.Equal(t, expected, HashAlgorithmFromString(s), s)
+	}
+}
@@ -1,11 +1,28 @@
 package sbom
 
 import (
+	"strings"
+
 	"github.com/CycloneDX/cyclonedx-go"
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/spdx/tools-golang/spdx/v2/common"
 )
 
+// HashAlgorithmFromString returns the hash algorithm from its name. Names
+// are matched case insensitively and dashes are accepted as separators so
+// "sha-256", "SHA256" and "sha3-256" are all recognized. Unknown names
+// return HashAlgorithm_UNKNOWN.
+func HashAlgorithmFromString(s string) HashAlgorithm {
+	s = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", "_"))
+	if v, ok := HashAlgorithm_value[s]; ok {
+		return HashAlgorithm(v)
+	}
+	if v, ok := HashAlgorithm_value[strings.ReplaceAll(s, "_", "")]; ok {
+		return HashAlgorithm(v)
+	}
+	return HashAlgorithm_UNKNOWN
+}
+
 func HashAlgorithmFromCycloneDX(cdxAlgo cdx.HashAlgorithm) HashAlgorithm {
 	switch cdxAlgo {
 	case cdx.HashAlgoMD5:
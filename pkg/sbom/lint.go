@@ -0,0 +1,120 @@
+package sbom
+
+import "fmt"
+
+// LintSeverity indicates how serious a lint finding is
+type LintSeverity string
+
+const (
+	// LintError findings break the document graph and will result in
+	// malformed or lossy output when serializing
+	LintError LintSeverity = "error"
+
+	// LintWarning findings are tolerated by the serializers but are
+	// probably a mistake
+	LintWarning LintSeverity = "warning"
+)
+
+// LintFinding is a problem found in a document by Lint. ID is the node
+// or edge source ID where the problem was found.
+type LintFinding struct {
+	Severity LintSeverity
+	ID       string
+	Message  string
+}
+
+// String returns a human readable representation of the finding
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.ID, f.Message)
+}
+
+// Lint checks the document for structural problems such as edges or root
+// elements pointing to nodes not in the NodeList, duplicate node IDs and
+// nodes without a name. The findings are returned in the order in which
+// the offending elements appear in the document.
+func (d *Document) Lint() []LintFinding {
+	ret := []LintFinding{}
+	if d.NodeList == nil {
+		return append(ret, LintFinding{
+			Severity: LintError, Message: "document has no nodelist",
+		})
+	}
+
+	duplicates := map[string]struct{}{}
+	for _, id := range d.NodeList.DuplicateIDs() {
+		duplicates[id] = struct{}{}
+	}
+
+	nodeIndex := map[string]struct{}{}
+	for _, n := range d.NodeList.Nodes {
+		if n.Id == "" {
+			ret = append(ret, LintFinding{
+				Severity: LintError, ID: n.Id, Message: fmt.Sprintf("node %q has no ID", n.Name),
+			})
+			continue
+		}
+		if _, ok := duplicates[n.Id]; ok {
+			// Flag the ID once, where it is first declared
+			delete(duplicates, n.Id)
+			ret = append(ret, LintFinding{
+				Severity: LintError, ID: n.Id, Message: "node ID is used by more than one node",
+			})
+		}
+		nodeIndex[n.Id] = struct{}{}
+
+		if n.Name == "" {
+			ret = append(ret, LintFinding{
+				Severity: LintWarning, ID: n.Id, Message: "node has no name",
+			})
+		}
+	}
+
+	for _, id := range d.NodeList.RootElements {
+		if _, ok := nodeIndex[id]; !ok {
+			ret = append(ret, LintFinding{
+				Severity: LintError, ID: id, Message: "root element not found in nodes",
+			})
+		}
+	}
+
+	// These checks mirror the edges dropped or merged by cleanEdges
+	seenEdges := map[string]struct{}{}
+	for _, e := range d.NodeList.Edges {
+		if _, ok := nodeIndex[e.From]; !ok {
+			ret = append(ret, LintFinding{
+				Severity: LintError, ID: e.From, Message: fmt.Sprintf("%s edge source not found in nodes", e.Type),
+			})
+		}
+
+		if len(e.To) == 0 {
+			ret = append(ret, LintFinding{
+				Severity: LintWarning, ID: e.From, Message: fmt.Sprintf("%s edge has no targets", e.Type),
+			})
+		}
+
+		edgeKey := e.From + "+++" + e.Type.String() + "+++" + e.Label
+		if _, ok := seenEdges[edgeKey]; ok {
+			ret = append(ret, LintFinding{
+				Severity: LintWarning, ID: e.From, Message: fmt.Sprintf("duplicate %s edge from node", e.Type),
+			})
+		}
+		seenEdges[edgeKey] = struct{}{}
+
+		seenTos := map[string]struct{}{}
+		for _, to := range e.To {
+			if _, ok := nodeIndex[to]; !ok {
+				ret = append(ret, LintFinding{
+					Severity: LintError, ID: e.From, Message: fmt.Sprintf("%s edge target %q not found in nodes", e.Type, to),
+				})
+			}
+			if _, ok := seenTos[to]; ok {
+				ret = append(ret, LintFinding{
+					Severity: LintWarning, ID: e.From, Message: fmt.Sprintf("%s edge lists target %q more than once", e.Type, to),
+				})
+			}
+			seenTos[to] = struct{}{}
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,56 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	valid := func() *Document {
+		return &Document{
+			NodeList: &NodeList{
+				Nodes: []*Node{
+					{Id: "root", Name: "root"},
+					{Id: "dep", Name: "dep"},
+				},
+				Edges: []*Edge{
+					{Type: Edge_dependsOn, From: "root", To: []string{"dep"}},
+				},
+				RootElements: []string{"root"},
+			},
+		}
+	}
+	require.Len(t, valid().Lint(), 0)
+	require.Len(t, (&Document{}).Lint(), 1)
+
+	// Edges of the same type with different labels are not duplicates
+	labeled := valid()
+	labeled.NodeList.Edges[0].Label = "runtime"
+	labeled.NodeList.AddEdge(&Edge{Type: Edge_dependsOn, From: "root", To: []string{"dep"}, Label: "build"})
+	require.Len(t, labeled.Lint(), 0)
+
+	for name, tc := range map[string]struct {
+		mutate   func(*Document)
+		severity LintSeverity
+		id       string
+	}{
+		"empty-name":     {func(d *Document) { d.NodeList.Nodes[1].Name = "" }, LintWarning, "dep"},
+		"duplicate-id":   {func(d *Document) { d.NodeList.AddNode(&Node{Id: "dep", Name: "dep"}) }, LintError, "dep"},
+		"missing-root":   {func(d *Document) { d.NodeList.RootElements = append(d.NodeList.RootElements, "nope") }, LintError, "nope"},
+		"empty-to":       {func(d *Document) { d.NodeList.Edges[0].To = []string{} }, LintWarning, "root"},
+		"missing-target": {func(d *Document) { d.NodeList.Edges[0].To = append(d.NodeList.Edges[0].To, "nope") }, LintError, "root"},
+		"missing-source": {func(d *Document) { d.NodeList.Edges[0].From = "nope" }, LintError, "nope"},
+		"duplicate-edge": {func(d *Document) {
+			d.NodeList.AddEdge(&Edge{Type: Edge_dependsOn, From: "root", To: []string{"dep"}})
+		}, LintWarning, "root"},
+		"duplicate-target": {func(d *Document) { d.NodeList.Edges[0].To = append(d.NodeList.Edges[0].To, "dep") }, LintWarning, "root"},
+	} {
+		doc := valid()
+		tc.mutate(doc)
+		findings := doc.Lint()
+		require.Len(t, findings, 1, name)
+		require.Equal(t, tc.severity, findings[0].Severity, name)
+		require.Equal(t, tc.id, findings[0].ID, name)
+	}
+}
@@ -0,0 +1,18 @@
+package sbom
+
+import "fmt"
+
+// HasLines returns true if the location has a line range
+func (l *SourceLocation) HasLines() bool {
+	return l != nil && (l.StartLine != 0 || l.EndLine != 0)
+}
+
+// HasBytes returns true if the location has a byte range
+func (l *SourceLocation) HasBytes() bool {
+	return l != nil && (l.StartByte != 0 || l.EndByte != 0)
+}
+
+// flatString returns a deterministic string that can be used to hash the location
+func (l *SourceLocation) flatString() string {
+	return fmt.Sprintf("(l)%d-%d(b)%d-%d", l.StartLine, l.EndLine, l.StartByte, l.EndByte)
+}
@@ -0,0 +1,62 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteMermaid writes the NodeList graph to w as a Mermaid flowchart. To keep
+// the diagram readable, nodes are declared with short aliases (n0, n1, ...)
+// assigned in the order of their IDs, so the aliases do not change when the
+// nodes are reordered. Nodes are labeled with their name and version and
+// edges with their type.
+func (nl *NodeList) WriteMermaid(w io.Writer) error {
+	ids := []string{}
+	nodes := nl.indexNodes()
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	aliases := map[string]string{}
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	for i, id := range ids {
+		aliases[id] = fmt.Sprintf("n%d", i)
+		n := nodes[id]
+		label := n.Name
+		if label == "" {
+			label = n.Id
+		}
+		if n.Version != "" {
+			label += "@" + n.Version
+		}
+		fmt.Fprintf(&sb, "  %s[%s]\n", aliases[id], mermaidQuote(label))
+	}
+
+	for _, e := range nl.Edges {
+		from, ok := aliases[e.From]
+		if !ok {
+			continue
+		}
+		for _, id := range e.To {
+			to, ok := aliases[id]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&sb, "  %s -->|%s| %s\n", from, e.Type.String(), to)
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing mermaid graph: %w", err)
+	}
+	return nil
+}
+
+// mermaidQuote returns s as a quoted Mermaid label
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
@@ -0,0 +1,39 @@
+package sbom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMermaid(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "pkg:generic/app@1.0", Name: "app", Version: "1.0"},
+			{Id: "SPDXRef-Package-lib", Name: `lib "core"`},
+			{Id: "dep"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "pkg:generic/app@1.0", To: []string{"SPDXRef-Package-lib", "missing"}},
+			{Type: Edge_dependsOn, From: "SPDXRef-Package-lib", To: []string{"dep"}},
+		},
+	}
+
+	expected := `graph TD
+  n0["lib #quot;core#quot;"]
+  n1["dep"]
+  n2["app@1.0"]
+  n2 -->|contains| n0
+  n0 -->|dependsOn| n1
+`
+	var buf bytes.Buffer
+	require.NoError(t, nl.WriteMermaid(&buf))
+	require.Equal(t, expected, buf.String())
+
+	// Aliases do not depend on the order of the nodes
+	nl.Nodes[0], nl.Nodes[2] = nl.Nodes[2], nl.Nodes[0]
+	buf.Reset()
+	require.NoError(t, nl.WriteMermaid(&buf))
+	require.Equal(t, expected, buf.String())
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bom-squad/protobom/pkg/formats/spdx"
 )
 
 // This file contains methods to work with the generated node type
@@ -89,11 +91,24 @@ func (n *Node) Update(n2 *Node) {
 	if len(n2.FileTypes) > 0 {
 		n.FileTypes = n2.FileTypes
 	}
+	if len(n2.Vulnerabilities) > 0 {
+		n.Vulnerabilities = n2.Vulnerabilities
+	}
+	if n2.Location != nil {
+		n.Location = n2.Location
+	}
+	if len(n2.Properties) > 0 {
+		n.Properties = n2.Properties
+	}
 }
 
 // Augment takes updates fields in n with data from n2 which is not already defined
 // (not empty string, not 0 length string, not nill pointer).
 func (n *Node) Augment(n2 *Node) {
+	// Placeholder nodes take the type of the node with the actual data
+	if n.Type == Node_UNKNOWN {
+		n.Type = n2.Type
+	}
 	if n.Name == "" && n2.Name != "" {
 		n.Name = n2.Name
 	}
@@ -166,6 +181,84 @@ func (n *Node) Augment(n2 *Node) {
 	if len(n.FileTypes) == 0 && len(n2.FileTypes) > 0 {
 		n.FileTypes = n2.FileTypes
 	}
+	if len(n.Vulnerabilities) == 0 && len(n2.Vulnerabilities) > 0 {
+		n.Vulnerabilities = n2.Vulnerabilities
+	}
+	if n.Location == nil && n2.Location != nil {
+		n.Location = n2.Location
+	}
+	if len(n.Properties) == 0 && len(n2.Properties) > 0 {
+		n.Properties = n2.Properties
+	}
+}
+
+// merge folds the data of n2 into n: hashes and identifiers missing in n are
+// added and its empty fields are filled by Augment. It returns the fields
+// where n2 conflicts with n, whose values are left untouched.
+func (n *Node) merge(n2 *Node) []string {
+	conflicts := n.mergeConflicts(n2)
+
+	if len(n2.Hashes) > 0 && n.Hashes == nil {
+		n.Hashes = map[string]string{}
+	}
+	for algo, h := range n2.Hashes {
+		if _, ok := n.Hashes[algo]; !ok {
+			n.Hashes[algo] = h
+		}
+	}
+
+	if len(n2.Identifiers) > 0 && n.Identifiers == nil {
+		n.Identifiers = map[int32]string{}
+	}
+	for t, v := range n2.Identifiers {
+		if _, ok := n.Identifiers[t]; !ok {
+			n.Identifiers[t] = v
+		}
+	}
+
+	n.Augment(n2)
+	return conflicts
+}
+
+// mergeConflicts returns the names of the fields where n2 has a value that
+// differs from a value already set in n. Only string fields, hashes and
+// identifiers are considered.
+func (n *Node) mergeConflicts(n2 *Node) []string {
+	conflicts := []string{}
+	m2 := n2.ProtoReflect()
+	n.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Name() == "id" || fd.Kind() != protoreflect.StringKind || fd.Cardinality() == protoreflect.Repeated {
+			return true
+		}
+		if v2 := m2.Get(fd).String(); v2 != "" && v2 != v.String() {
+			conflicts = append(conflicts, string(fd.Name()))
+		}
+		return true
+	})
+
+	algos := []string{}
+	for algo, h := range n2.Hashes {
+		if existing, ok := n.Hashes[algo]; ok && existing != h {
+			algos = append(algos, algo)
+		}
+	}
+	sort.Strings(algos)
+	for _, algo := range algos {
+		conflicts = append(conflicts, fmt.Sprintf("hashes[%s]", algo))
+	}
+
+	types := []int{}
+	for t, id := range n2.Identifiers {
+		if existing, ok := n.Identifiers[t]; ok && existing != id {
+			types = append(types, int(t))
+		}
+	}
+	sort.Ints(types)
+	for _, t := range types {
+		conflicts = append(conflicts, fmt.Sprintf("identifiers[%s]", SoftwareIdentifierType(t)))
+	}
+
+	return conflicts
 }
 
 // Copy returns a new node that is a copy of the node
@@ -197,15 +290,36 @@ func (n *Node) Copy() *Node {
 		ExternalReferences: n.ExternalReferences,
 		Identifiers:        n.Identifiers,
 		FileTypes:          n.FileTypes,
+		Vulnerabilities:    n.Vulnerabilities,
+		Location:           n.Location,
+		Properties:         n.Properties,
 	}
 }
 
-// Equal compares Node n to n2 and returns true if they are the same
+// Equal compares Node n to n2 and returns true if they are the same. The
+// order of the entries in the hashes and identifiers maps is not significant.
 func (n *Node) Equal(n2 *Node) bool {
-	if n2 == nil {
+	if n == nil || n2 == nil {
+		return n == nil && n2 == nil
+	}
+	// The flat string does not separate map entries, so the maps are
+	// compared directly to tell apart values that flatten the same
+	return n.flatString() == n2.flatString() &&
+		equalStringMaps(n.Hashes, n2.Hashes) &&
+		equalStringMaps(n.Properties, n2.Properties)
+}
+
+// equalStringMaps returns true if both maps have the same entries
+func equalStringMaps(m1, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
 		return false
 	}
-	return n.flatString() == n2.flatString()
+	for k, v := range m1 {
+		if v2, ok := m2[k]; !ok || v != v2 {
+			return false
+		}
+	}
+	return true
 }
 
 // flatString returns a string representation of the node which can be used to
@@ -222,6 +336,12 @@ func (n *Node) flatString() string {
 			for _, i := range n.Suppliers {
 				pairs = append(pairs, fmt.Sprintf("supplier:%s", i.flatString()))
 			}
+		case "bomsquad.protobom.Node.vulnerabilities":
+			for _, vuln := range n.Vulnerabilities {
+				pairs = append(pairs, fmt.Sprintf("vulnerability:%s", vuln.flatString()))
+			}
+		case "bomsquad.protobom.Node.location":
+			pairs = append(pairs, fmt.Sprintf("location:%s", n.Location.flatString()))
 		case "bomsquad.protobom.Node.originators":
 			for _, i := range n.Originators {
 				pairs = append(pairs, fmt.Sprintf("originator:%s", i.flatString()))
@@ -253,7 +373,7 @@ func (n *Node) flatString() string {
 			if n.BuildDate != nil {
 				pairs = append(pairs, fmt.Sprintf("%s:%d", fd.FullName(), n.BuildDate.AsTime().Unix()))
 			}
-		case "bomsquad.protobom.Node.hashes":
+		case "bomsquad.protobom.Node.hashes", "bomsquad.protobom.Node.properties":
 			pairs = append(pairs, string(fd.FullName())+":"+flatStringMap(v.Map()))
 		default:
 			pairs = append(pairs, string(fd.FullName())+":"+v.String())
@@ -289,6 +409,30 @@ func (n *Node) Checksum() string {
 	return fmt.Sprintf("%x", sum)
 }
 
+// ContentID returns a deterministic identifier derived from the node's
+// identity fields: its type, name, version, purl and hashes. Two nodes
+// describing the same piece of software will return the same ID, which makes
+// it suitable for reproducible documents and joining nodes across SBOMs.
+//
+// The identifier follows the NodeIdentifierPrefix convention, flagged
+// with "content", for example: protobom-content--5ad3ff...
+func (n *Node) ContentID() string {
+	s := fmt.Sprintf("t(%d)n(%s)v(%s)p(%s)", n.Type, n.Name, n.Version, n.Purl().Canonical())
+
+	// Hashes are keyed by normalized algorithm and lowercase digest so
+	// spelling variations do not change the ID
+	hashes := []string{}
+	for algo, digest := range n.Hashes {
+		hashes = append(hashes, hashIndexKey(algo, digest))
+	}
+	sort.Strings(hashes)
+	for _, h := range hashes {
+		s += fmt.Sprintf("h(%s)", h)
+	}
+
+	return fmt.Sprintf("%s-content--%x", NodeIdentifierPrefix, sha256.Sum256([]byte(s)))
+}
+
 type PackageURL string
 
 // Purl returns the node purl as a string
@@ -304,6 +448,51 @@ func (n *Node) Purl() PackageURL {
 	return ""
 }
 
+// identifier returns the node's software identifier of type t. Package urls
+// are read with Purl so they are ignored in file nodes. Both CPE types are
+// returned as normalized CPE 2.3 strings so nodes match regardless of the
+// CPE version they carry.
+func (n *Node) identifier(t SoftwareIdentifierType) string {
+	switch t {
+	case SoftwareIdentifierType_PURL:
+		return string(n.Purl())
+	case SoftwareIdentifierType_CPE23, SoftwareIdentifierType_CPE22:
+		if cpe := n.cpe23(); cpe != "" {
+			return cpe
+		}
+	}
+	return n.Identifiers[int32(t)]
+}
+
+// LicenseIDs returns the license identifiers found in the node's declared
+// (Licenses) and concluded license expressions, flattened and without
+// duplicates. Expressions that cannot be parsed are returned verbatim and
+// the NOASSERTION and NONE values are ignored.
+func (n *Node) LicenseIDs() []string {
+	ret := []string{}
+	seen := map[string]struct{}{}
+	for _, l := range append(append([]string{}, n.Licenses...), n.LicenseConcluded) {
+		l = strings.TrimSpace(l)
+		if l == "" || l == spdx.NOASSERTION || l == spdx.NONE {
+			continue
+		}
+
+		ids := []string{l}
+		if expr, err := spdx.ParseLicenseExpression(l); err == nil {
+			ids = expr.LicenseIDs()
+		}
+
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
 // HashesMatch takes a map of hashes th and returns a boolean indicating
 // if the test hashes match those of the node. The algorithm will only take
 // into account algorithms that are common to the node and test set.
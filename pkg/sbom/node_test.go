@@ -1,6 +1,7 @@
 package sbom
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -171,6 +172,21 @@ func TestGetRootNodes(t *testing.T) {
 	}
 }
 
+func TestRootNodes(t *testing.T) {
+	nl := &NodeList{
+		RootElements: []string{"node3", "missing", "node1"},
+		Nodes: []*Node{
+			{Id: "node1"}, {Id: "node2"}, {Id: "node3"},
+		},
+	}
+	require.Equal(t, []*Node{{Id: "node3"}, {Id: "node1"}}, nl.RootNodes())
+	require.Equal(t, []string{"missing"}, nl.MissingRootElements())
+
+	nl.RootElements = []string{}
+	require.Empty(t, nl.RootNodes())
+	require.Empty(t, nl.MissingRootElements())
+}
+
 func TestNodeFlatString(t *testing.T) {
 	t1 := time.Date(2023, 11, 15, 20, 34, 58, 651387237, time.UTC)
 	t2 := time.Date(2023, 11, 16, 20, 34, 58, 651387237, time.UTC)
@@ -264,3 +280,106 @@ func TestNodeFlatString(t *testing.T) {
 		require.Equal(t, tc.expectedString, s)
 	}
 }
+
+func TestNodeEqual(t *testing.T) {
+	n := &Node{
+		Id:          "node1",
+		Name:        "test",
+		Hashes:      map[string]string{"SHA1": "aaa", "SHA256": "bbb", "SHA512": "ccc"},
+		Identifiers: map[int32]string{1: "pkg:generic/test@1.0", 2: "cpe:2.3:a:test:test:1.0:*:*:*:*:*:*:*"},
+	}
+
+	// Maps built in a different order compare equal
+	n2 := &Node{
+		Id:          "node1",
+		Name:        "test",
+		Hashes:      map[string]string{"SHA512": "ccc", "SHA256": "bbb", "SHA1": "aaa"},
+		Identifiers: map[int32]string{2: "cpe:2.3:a:test:test:1.0:*:*:*:*:*:*:*", 1: "pkg:generic/test@1.0"},
+	}
+	require.True(t, n.Equal(n2))
+	require.True(t, n2.Equal(n))
+
+	// A single differing hash makes them unequal
+	n2.Hashes["SHA256"] = "bbc"
+	require.False(t, n.Equal(n2))
+
+	n2 = n.Copy()
+	n2.Identifiers = map[int32]string{1: "pkg:generic/test@1.0"}
+	require.False(t, n.Equal(n2))
+
+	// Hashes that flatten to the same string are told apart
+	n2 = n.Copy()
+	n2.Hashes = map[string]string{"SHA1": "aaaSHA256:bbbSHA512:ccc"}
+	require.Equal(t, n.flatString(), n2.flatString())
+	require.False(t, n.Equal(n2))
+
+	n2 = n.Copy()
+	n2.Properties = map[string]string{"build": "1"}
+	require.False(t, n.Equal(n2))
+	require.False(t, n2.Equal(n))
+
+	// Nil and empty fields are the same
+	n2 = n.Copy()
+	n.Properties = map[string]string{}
+	require.True(t, n.Equal(n2))
+
+	require.False(t, n.Equal(nil))
+	require.True(t, (*Node)(nil).Equal(nil))
+}
+
+func TestContentID(t *testing.T) {
+	node1 := &Node{
+		Id:          "node1",
+		Name:        "bash",
+		Version:     "4.0.1",
+		Hashes:      map[string]string{"SHA1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1", "SHA256": "e63a4879428aad2c768954d7be753fde3997771b2ce45bc7f99c35ff00d2a98b"},
+		Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:apk/wolfi/bash@4.0.1"},
+	}
+
+	// Same identity, different ID and non-identity fields
+	node2 := node1.Copy()
+	node2.Id = "node2"
+	node2.Description = "The Bourne Again Shell"
+	require.Equal(t, node1.ContentID(), node2.ContentID())
+	require.True(t, strings.HasPrefix(node1.ContentID(), NodeIdentifierPrefix+"-content--"))
+
+	// Changing the version or a hash changes the ID
+	node3 := node1.Copy()
+	node3.Version = "4.0.2"
+	require.NotEqual(t, node1.ContentID(), node3.ContentID())
+
+	node4 := node1.Copy()
+	node4.Hashes = map[string]string{"SHA1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}
+	require.NotEqual(t, node1.ContentID(), node4.ContentID())
+
+	// Spelling of algorithms, digest case and qualifier order do not matter
+	node5 := &Node{
+		Name:        "bash",
+		Version:     "4.0.1",
+		Hashes:      map[string]string{"sha-256": "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"},
+		Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:apk/wolfi/bash@4.0.1?distro=wolfi&arch=x86_64"},
+	}
+	node6 := &Node{
+		Name:        "bash",
+		Version:     "4.0.1",
+		Hashes:      map[string]string{"SHA256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:apk/wolfi/bash@4.0.1?arch=x86_64&distro=wolfi"},
+	}
+	require.Equal(t, node5.ContentID(), node6.ContentID())
+}
+
+func TestLicenseIDs(t *testing.T) {
+	for _, tc := range []struct {
+		sut      *Node
+		expected []string
+	}{
+		{&Node{}, []string{}},
+		{&Node{Licenses: []string{"MIT"}, LicenseConcluded: "NOASSERTION"}, []string{"MIT"}},
+		{&Node{Licenses: []string{"GPL-2.0-only OR MIT"}, LicenseConcluded: "MIT"}, []string{"GPL-2.0-only", "MIT"}},
+		{&Node{Licenses: []string{"Apache-2.0", "BSD-3-Clause AND MIT"}}, []string{"Apache-2.0", "BSD-3-Clause", "MIT"}},
+		{&Node{LicenseConcluded: "GPL-2.0-or-later WITH Classpath-exception-2.0"}, []string{"GPL-2.0-or-later"}},
+		{&Node{Licenses: []string{"invalid ( expression"}}, []string{"invalid ( expression"}},
+	} {
+		require.Equal(t, tc.expected, tc.sut.LicenseIDs())
+	}
+}
@@ -1,12 +1,15 @@
 package sbom
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
-	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // This file adds a few methods to the NodeList type which
@@ -27,6 +30,9 @@ type hashIndex map[string][]*Node
 // purlIndex captures the SBOM nodelist ordered by package url
 type purlIndex map[PackageURL][]*Node
 
+// cpeIndex captures the SBOM nodelist ordered by normalized CPE 2.3 string
+type cpeIndex map[string][]*Node
+
 var ErrorMoreThanOneMatch = fmt.Errorf("More than one node matches")
 
 // indexNodes returns an inverse dictionary with the IDs of the nodes
@@ -65,7 +71,7 @@ func (nl *NodeList) indexRootElements() rootElementsIndex {
 }
 
 // indexNodesByHash returns an index of all nodes by their hash value.
-// More than one node can have the same hash.
+// More than one node can have the same hash. Keys are built by hashIndexKey.
 func (nl *NodeList) indexNodesByHash() hashIndex {
 	ret := hashIndex{}
 	for _, n := range nl.Nodes {
@@ -73,97 +79,366 @@ func (nl *NodeList) indexNodesByHash() hashIndex {
 			if hashVal == "" {
 				continue
 			}
-			s := fmt.Sprintf("%s:%s", algo, hashVal)
+			s := hashIndexKey(algo, hashVal)
 			ret[s] = append(ret[s], n)
 		}
 	}
 	return ret
 }
 
-// Returns an indexed map of nodes by their package URLs. Note that more than
-// one node may have the same purl.
-func (nl *NodeList) indexNodesByPurl() map[PackageURL][]*Node {
-	ret := map[PackageURL][]*Node{}
+// hashIndexKey returns the key of a hash in the hash index: the algorithm
+// name normalized with HashAlgorithmFromString (kept verbatim when unknown)
+// and the lowercased digest, eg SHA256:e3b0c4...
+func hashIndexKey(algo, digest string) string {
+	if a := HashAlgorithmFromString(algo); a != HashAlgorithm_UNKNOWN {
+		algo = a.String()
+	}
+	return algo + ":" + strings.ToLower(digest)
+}
+
+// GetNodesByHash returns the nodes that have the digest value under the
+// hash algorithm algo. Algorithm names are normalized, so "SHA-256" and
+// "sha256" return the same nodes, and digests are compared ignoring case.
+func (nl *NodeList) GetNodesByHash(algo, value string) []*Node {
+	ret := []*Node{}
+	if value == "" {
+		return ret
+	}
+	return append(ret, nl.indexNodesByHash()[hashIndexKey(algo, value)]...)
+}
+
+// Returns an indexed map of nodes by their package URLs in canonical form.
+// Note that more than one node may have the same purl.
+func (nl *NodeList) indexNodesByPurl() purlIndex {
+	ret := purlIndex{}
 	for _, n := range nl.Nodes {
 		nodePurl := n.Purl()
 		if nodePurl == "" {
 			continue
 		}
 
-		ret[nodePurl] = append(ret[nodePurl], n)
+		key := nodePurl.Canonical()
+		ret[key] = append(ret[key], n)
+	}
+	return ret
+}
+
+// IndexByHash returns the nodes of the NodeList keyed by their hashes in
+// algorithm:digest form (eg SHA256:e3b0c4...). Keys are normalized as in
+// GetNodesByHash: the algorithm name is normalized and the digest
+// lowercased. All nodes sharing a hash are listed under its key in the
+// order in which they appear in the NodeList.
+func (nl *NodeList) IndexByHash() map[string][]*Node {
+	return nl.indexNodesByHash()
+}
+
+// IndexByPurl returns the nodes of the NodeList keyed by their package URL
+// in canonical form (see PackageURL.Canonical), so that nodes with
+// equivalent purls are grouped under the same key. Nodes without a purl are
+// not included. Nodes under each key keep their order in the NodeList.
+func (nl *NodeList) IndexByPurl() map[string][]*Node {
+	ret := map[string][]*Node{}
+	for purl, nodes := range nl.indexNodesByPurl() {
+		ret[string(purl)] = nodes
+	}
+	return ret
+}
+
+// indexNodesByCPE returns an index of the nodes by their CPE 2.3 identifier,
+// normalized with normalizeCPE23. Nodes with only a CPE 2.2 are indexed by
+// its CPE 2.3 form. More than one node may have the same CPE.
+func (nl *NodeList) indexNodesByCPE() cpeIndex {
+	ret := cpeIndex{}
+	for _, n := range nl.Nodes {
+		cpe := n.cpe23()
+		if cpe == "" {
+			continue
+		}
+
+		ret[cpe] = append(ret[cpe], n)
+	}
+	return ret
+}
+
+// normalizeCPE23 lowercases a CPE 2.3 formatted string and fills any
+// missing trailing components with the ANY (*) value
+func normalizeCPE23(cpe string) string {
+	cpe = strings.ToLower(strings.TrimSpace(cpe))
+	if cpe == "" {
+		return ""
+	}
+
+	// cpe:2.3:part:vendor:product:version:update:edition:language:sw_edition:target_sw:target_hw:other
+	const cpe23Components = 13
+	parts := strings.Split(cpe, ":")
+	for len(parts) < cpe23Components {
+		parts = append(parts, "*")
+	}
+	return strings.Join(parts, ":")
+}
+
+// indexNodesByIdentifier returns the nodes indexed by their software
+// identifier of type t. More than one node may have the same identifier.
+func (nl *NodeList) indexNodesByIdentifier(t SoftwareIdentifierType) map[string][]*Node {
+	ret := map[string][]*Node{}
+	for _, n := range nl.Nodes {
+		id := n.identifier(t)
+		if id == "" {
+			continue
+		}
+
+		ret[id] = append(ret[id], n)
+	}
+	return ret
+}
+
+// indexNodesByExternalReference returns the nodes indexed by the URL of
+// their external references of type t
+func (nl *NodeList) indexNodesByExternalReference(t ExternalReference_ExternalReferenceType) map[string][]*Node {
+	ret := map[string][]*Node{}
+	for _, n := range nl.Nodes {
+		seen := map[string]struct{}{}
+		for _, er := range n.ExternalReferences {
+			if er.Url == "" || er.EffectiveType() != t {
+				continue
+			}
+			if _, ok := seen[er.Url]; ok {
+				continue
+			}
+			seen[er.Url] = struct{}{}
+			ret[er.Url] = append(ret[er.Url], n)
+		}
+	}
+	return ret
+}
+
+// reachableNodes returns an index of the IDs of all nodes that can be reached
+// from the nodes in ids by walking the graph edges. The starting nodes are
+// included in the returned index.
+func (nl *NodeList) reachableNodes(ids ...string) map[string]struct{} {
+	edgeIndex := nl.indexEdges()
+	ret := map[string]struct{}{}
+	queue := append([]string{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := ret[id]; ok {
+			continue
+		}
+		ret[id] = struct{}{}
+		for _, edges := range edgeIndex[id] {
+			for _, e := range edges {
+				queue = append(queue, e.To...)
+			}
+		}
 	}
 	return ret
 }
 
 // cleanEdges is a utility function that removes broken
-// connection and orphaned edges
+// connection and orphaned edges. The destinations of the consolidated
+// edges are sorted so the result does not depend on the order in which
+// the edges were added.
 func (nl *NodeList) cleanEdges() {
+	nl.RepairEdges()
+	nl.SortEdgesTo()
+}
+
+// SortEdgesTo sorts the destinations of all the edges in the NodeList
+// without reordering the edges themselves
+func (nl *NodeList) SortEdgesTo() {
+	for _, e := range nl.Edges {
+		e.SortTo()
+	}
+}
+
+// BrokenEdge is a relationship pointing to a node not in the NodeList
+type BrokenEdge struct {
+	From string
+	Type Edge_Type
+	To   string
+}
+
+// EdgeRepairReport summarizes the changes made by RepairEdges
+type EdgeRepairReport struct {
+	// DroppedEdges are the edges removed because their source node does
+	// not exist
+	DroppedEdges []*Edge
+
+	// DroppedTargets are the destinations removed from edges because the
+	// node they point to does not exist
+	DroppedTargets []BrokenEdge
+
+	// MergedEdges is the number of edges merged into another edge with the
+	// same source, type and label
+	MergedEdges int
+
+	// DuplicateTargets is the number of repeated destinations removed
+	DuplicateTargets int
+}
+
+// Empty returns true if the report has no repairs
+func (r *EdgeRepairReport) Empty() bool {
+	return len(r.DroppedEdges) == 0 && len(r.DroppedTargets) == 0 &&
+		r.MergedEdges == 0 && r.DuplicateTargets == 0
+}
+
+// String returns a one line summary of the report
+func (r *EdgeRepairReport) String() string {
+	return fmt.Sprintf(
+		"%d edges with unknown source dropped, %d unknown targets dropped, %d edges merged, %d duplicate targets removed",
+		len(r.DroppedEdges), len(r.DroppedTargets), r.MergedEdges, r.DuplicateTargets,
+	)
+}
+
+// RepairEdges removes the edges whose source is not in the NodeList and the
+// destinations pointing to missing nodes, merges the edges with the same
+// source, type and label and removes duplicate destinations. Edges left
+// without destinations are dropped. It returns a report of the repairs.
+func (nl *NodeList) RepairEdges() *EdgeRepairReport {
+	report := &EdgeRepairReport{
+		DroppedEdges:   []*Edge{},
+		DroppedTargets: []BrokenEdge{},
+	}
+
 	// Build a catalog of the elements ids
 	nodeIndex := nl.indexNodes()
 
-	// Add a seen cache to dedupe edges when
-	// cleaning them up
-	seenCache := map[string]*Edge{}
-	newTos := map[string]map[string]string{}
+	// Edges are consolidated by key, keeping the order in which they
+	// are first seen
+	keys := []string{}
+	newEdges := map[string]*Edge{}
+	seenTos := map[string]map[string]struct{}{}
 
 	// Now list all edges and rebuild the list
 	for _, edge := range nl.Edges {
 		// If the from node is not in the index, skip it
 		if _, ok := nodeIndex[edge.From]; !ok {
+			report.DroppedEdges = append(report.DroppedEdges, edge)
 			continue
 		}
 
-		// Use a string key for a simpler datastruct
-		edgeKey := edge.From + "+++" + edge.Type.String()
-		if _, ok := newTos[edgeKey]; !ok {
-			newTos[edgeKey] = map[string]string{}
-		}
+		// Use a string key for a simpler datastruct. Edges with different
+		// labels are not merged as they represent different relationships
+		edgeKey := edge.From + "+++" + edge.Type.String() + "+++" + edge.Label
 
 		// If we already saw an equivalent edge, reuse it
-		if _, ok := seenCache[edgeKey]; !ok {
-			seenCache[edgeKey] = &Edge{
-				Type: edge.Type,
-				From: edge.From,
-				To:   []string{},
+		if _, ok := newEdges[edgeKey]; ok {
+			report.MergedEdges++
+		} else {
+			keys = append(keys, edgeKey)
+			newEdges[edgeKey] = &Edge{
+				Type:  edge.Type,
+				From:  edge.From,
+				To:    []string{},
+				Label: edge.Label,
 			}
+			seenTos[edgeKey] = map[string]struct{}{}
 		}
 
 		for _, s := range edge.To {
 			if _, ok := nodeIndex[s]; !ok {
+				report.DroppedTargets = append(report.DroppedTargets, BrokenEdge{
+					From: edge.From, Type: edge.Type, To: s,
+				})
 				continue
 			}
-			newTos[edgeKey][s] = s
+			if _, ok := seenTos[edgeKey][s]; ok {
+				report.DuplicateTargets++
+				continue
+			}
+			seenTos[edgeKey][s] = struct{}{}
+			newEdges[edgeKey].To = append(newEdges[edgeKey].To, s)
 		}
 	}
 
-	newEdges := []*Edge{}
-	for f := range seenCache {
-		for s := range newTos[f] {
-			seenCache[f].To = append(seenCache[f].To, s)
-		}
-		if len(seenCache[f].To) > 0 {
-			newEdges = append(newEdges, seenCache[f])
+	nl.Edges = []*Edge{}
+	for _, k := range keys {
+		if len(newEdges[k].To) > 0 {
+			nl.Edges = append(nl.Edges, newEdges[k])
 		}
 	}
 
-	nl.Edges = newEdges
+	return report
 }
 
 func (nl *NodeList) AddEdge(e *Edge) {
 	nl.Edges = append(nl.Edges, e)
 }
 
+// ErrDanglingEdge is returned when adding an edge that points from or to
+// nodes not in the NodeList
+var ErrDanglingEdge = errors.New("edge refers to nodes not in the nodelist")
+
+// AddEdgeChecked appends an edge to the NodeList after checking the nodes
+// it relates exist. When createMissing is true, a placeholder node of type
+// UNKNOWN is added for each missing ID so the graph can be built before the
+// node data arrives; adding the actual node later with AddNodes fills the
+// placeholder. Otherwise an error wrapping ErrDanglingEdge is returned and
+// the edge is not added.
+func (nl *NodeList) AddEdgeChecked(e *Edge, createMissing bool) error {
+	if e == nil {
+		return errors.New("edge is nil")
+	}
+
+	index := nl.indexNodes()
+	missing := []string{}
+	for _, id := range append([]string{e.From}, e.To...) {
+		if _, ok := index[id]; ok {
+			continue
+		}
+		if createMissing {
+			n := &Node{Id: id, Type: Node_UNKNOWN}
+			nl.Nodes = append(nl.Nodes, n)
+			index[id] = n
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrDanglingEdge, strings.Join(missing, ", "))
+	}
+	nl.Edges = append(nl.Edges, e)
+	return nil
+}
+
 func (nl *NodeList) AddNode(n *Node) {
 	nl.Nodes = append(nl.Nodes, n)
 }
 
+// AddNodes appends nodes to the NodeList. Nodes with an ID already in the
+// NodeList are not added again, instead the existing node is augmented
+// with their data.
+func (nl *NodeList) AddNodes(nodes ...*Node) {
+	index := nl.indexNodes()
+	for _, n := range nodes {
+		if existing, ok := index[n.Id]; ok {
+			existing.Augment(n)
+			continue
+		}
+		nl.Nodes = append(nl.Nodes, n)
+		index[n.Id] = n
+	}
+}
+
+// AddEdges appends edges to the NodeList and consolidates them with the
+// existing ones, merging edges of the same type and origin and removing
+// duplicate destinations. As edges to or from nodes not in the NodeList are
+// dropped, nodes should be added before their edges.
+func (nl *NodeList) AddEdges(edges ...*Edge) {
+	nl.Edges = append(nl.Edges, edges...)
+	nl.cleanEdges()
+}
+
 // Add combines NodeList nl2 into nl. It is the equivalent to Union but
-// instead of returning a new NodeList it modifies nl.
+// instead of returning a new NodeList it modifies nl. Nodes already in nl
+// are augmented with the data of the incoming nodes with the same ID.
 func (nl *NodeList) Add(nl2 *NodeList) {
 	existingNodes := nl.indexNodes()
 	for i := range nl2.Nodes {
 		if n, ok := existingNodes[nl2.Nodes[i].Id]; ok {
-			existingNodes[nl2.Nodes[i].Id].Augment(n)
+			n.Augment(nl2.Nodes[i])
 		} else {
 			nl.Nodes = append(nl.Nodes, nl2.Nodes[i])
 		}
@@ -171,18 +446,22 @@ func (nl *NodeList) Add(nl2 *NodeList) {
 
 	existingEdges := nl.indexEdges()
 	for i := range nl2.Edges {
-		if _, ok := existingEdges[nl2.Edges[i].From]; !ok {
-			nl.Edges = append(nl.Edges, nl2.Edges[i])
-			continue
+		// Edges are merged only with an existing edge with the same source,
+		// type and label, others represent different relationships
+		var existing *Edge
+		for _, e := range existingEdges[nl2.Edges[i].From][nl2.Edges[i].Type] {
+			if e.Label == nl2.Edges[i].Label {
+				existing = e
+				break
+			}
 		}
-
-		if _, ok := existingEdges[nl2.Edges[i].From][nl2.Edges[i].Type]; !ok {
+		if existing == nil {
 			nl.Edges = append(nl.Edges, nl2.Edges[i])
 			continue
 		}
 
 		// Add it here to the existing edge
-		existingEdges[nl2.Edges[i].From][nl2.Edges[i].Type][0].To = append(existingEdges[nl2.Edges[i].From][nl2.Edges[i].Type][0].To, nl2.Edges[i].To...)
+		existing.To = append(existing.To, nl2.Edges[i].To...)
 	}
 
 	rootElements := nl.indexRootElements()
@@ -195,6 +474,112 @@ func (nl *NodeList) Add(nl2 *NodeList) {
 	nl.cleanEdges()
 }
 
+// AddReport describes the outcome of adding a NodeList to another one
+type AddReport struct {
+	// Added lists the IDs of the nodes that were new to the NodeList
+	Added []string
+
+	// Collided lists the IDs of the incoming nodes that already existed
+	Collided []string
+
+	// Differed lists the collided IDs where the incoming node was not
+	// equal to the existing one
+	Differed []string
+
+	// HashConflicts lists the collided nodes that have a different digest
+	// for the same hash algorithm, which means they are not the same
+	// software even when they share an ID
+	HashConflicts []HashConflict
+}
+
+// HashConflict records two nodes with the same ID and different digests of
+// the same hash algorithm
+type HashConflict struct {
+	ID        string
+	Algorithm HashAlgorithm
+	Existing  string
+	Incoming  string
+}
+
+// ErrHashConflict is returned when merging nodes with conflicting hashes
+// while MergeOptions.RejectHashConflicts is set
+var ErrHashConflict = errors.New("nodes have conflicting hashes")
+
+// HashConflicts returns the conflicts between the hashes of the nodes in nl
+// and the nodes with the same ID in nl2. Algorithm names are normalized and
+// digests compared case insensitively.
+func (nl *NodeList) HashConflicts(nl2 *NodeList) []HashConflict {
+	ret := []HashConflict{}
+	if nl2 == nil {
+		return ret
+	}
+
+	existingNodes := nl.indexNodes()
+	for _, n2 := range nl2.Nodes {
+		n, ok := existingNodes[n2.Id]
+		if !ok {
+			continue
+		}
+		ret = append(ret, hashConflicts(n, n2)...)
+	}
+	return ret
+}
+
+// hashConflicts compares the hashes of two nodes
+func hashConflicts(n, n2 *Node) []HashConflict {
+	existing := map[HashAlgorithm]string{}
+	for algo, digest := range n.Hashes {
+		if a := HashAlgorithmFromString(algo); a != HashAlgorithm_UNKNOWN {
+			existing[a] = digest
+		}
+	}
+
+	ret := []HashConflict{}
+	for algo, digest := range n2.Hashes {
+		a := HashAlgorithmFromString(algo)
+		prev, ok := existing[a]
+		if !ok || strings.EqualFold(prev, digest) {
+			continue
+		}
+		ret = append(ret, HashConflict{ID: n.Id, Algorithm: a, Existing: prev, Incoming: digest})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Algorithm < ret[j].Algorithm })
+	return ret
+}
+
+// AddWithReport combines nl2 into nl exactly like Add does but returns a
+// report listing the nodes that were added and the ones that collided
+// with existing nodes, so callers can detect when both lists disagree.
+func (nl *NodeList) AddWithReport(nl2 *NodeList) (*AddReport, error) {
+	if nl2 == nil {
+		return nil, errors.New("nodelist to add is nil")
+	}
+
+	report := &AddReport{
+		Added:         []string{},
+		Collided:      []string{},
+		Differed:      []string{},
+		HashConflicts: []HashConflict{},
+	}
+
+	existingNodes := nl.indexNodes()
+	for _, n := range nl2.Nodes {
+		existing, ok := existingNodes[n.Id]
+		if !ok {
+			report.Added = append(report.Added, n.Id)
+			continue
+		}
+		report.Collided = append(report.Collided, n.Id)
+		if !existing.Equal(n) {
+			report.Differed = append(report.Differed, n.Id)
+		}
+		report.HashConflicts = append(report.HashConflicts, hashConflicts(existing, n)...)
+	}
+
+	nl.Add(nl2)
+	return report, nil
+}
+
 // RemoveNodes removes a list of nodes and its edges from the nodelist
 func (nl *NodeList) RemoveNodes(ids []string) {
 	// build an inverse dict of the IDs
@@ -214,121 +599,532 @@ func (nl *NodeList) RemoveNodes(ids []string) {
 	nl.cleanEdges()
 }
 
-// GetEdgeByType returns a pointer to the first edge found from fromElement
-// of type t.
-func (nl *NodeList) GetEdgeByType(fromElement string, t Edge_Type) *Edge {
+// Orphans returns the nodes that are not root elements and are not the
+// destination of any edge. Orphans are always unreachable but a node can be
+// unreachable without being an orphan, use PruneUnreachableDryRun to list
+// all the nodes that PruneUnreachable would remove.
+func (nl *NodeList) Orphans() []*Node {
+	ret := []*Node{}
+	targets := nl.indexRootElements()
 	for _, e := range nl.Edges {
-		if e.From == fromElement && e.Type == t {
-			return e
+		for _, id := range e.To {
+			targets[id] = struct{}{}
 		}
 	}
-	return nil
+	for _, n := range nl.Nodes {
+		if _, ok := targets[n.Id]; !ok {
+			ret = append(ret, n)
+		}
+	}
+	return ret
 }
 
-// copyEdgeList is a utility function that deep copies a list of edges
-func copyEdgeList(original []*Edge) []*Edge {
-	nodeCopy := []*Edge{}
-	for _, e := range original {
-		nodeCopy = append(nodeCopy, e.Copy())
+// PruneUnreachable removes all nodes that cannot be reached from the root
+// elements of the NodeList by following its edges, fixing the edges and
+// root elements in place. Unlike removing orphan nodes, the graph is walked
+// transitively so nodes left dangling after a link in a chain is removed
+// are pruned as well. NodeLists without root elements are left untouched.
+func (nl *NodeList) PruneUnreachable() {
+	ids := nl.PruneUnreachableDryRun()
+	if len(ids) == 0 {
+		return
 	}
-	return nodeCopy
+	nl.RemoveNodes(ids)
 }
 
-// Intersect returns a new NodeList with nodes which are common in nl and nl2.
-// All common nodes will be copied from nl and then `Update`d with data from nl2
-func (nl *NodeList) Intersect(nl2 *NodeList) *NodeList {
-	rootElements := nl.indexRootElements()
-	rootElements2 := nl2.indexRootElements()
+// Prune is an alias of PruneUnreachable: it removes the nodes that cannot
+// be reached from the root elements of the NodeList.
+func (nl *NodeList) Prune() {
+	nl.PruneUnreachable()
+}
 
-	ret := &NodeList{
-		Nodes:        []*Node{},
-		Edges:        copyEdgeList(nl.Edges), // copied as they will be cleaned
-		RootElements: []string{},
+// PruneUnreachableDryRun returns the IDs of the nodes that PruneUnreachable
+// would remove, without modifying the NodeList.
+func (nl *NodeList) PruneUnreachableDryRun() []string {
+	ret := []string{}
+	if len(nl.RootElements) == 0 {
+		return ret
 	}
-	var ni1, ni2 nodeIndex
 
-	ni1 = nl.indexNodes()
-	ni2 = nl2.indexNodes()
+	reachable := nl.reachableNodes(nl.RootElements...)
+	for _, n := range nl.Nodes {
+		if _, ok := reachable[n.Id]; !ok {
+			ret = append(ret, n.Id)
+		}
+	}
+	return ret
+}
 
-	for id, node := range ni1 {
-		if _, ok := ni2[id]; !ok {
-			continue
+// IsRoot returns true if the node id is one of the root elements
+func (nl *NodeList) IsRoot(id string) bool {
+	for _, r := range nl.RootElements {
+		if r == id {
+			return true
 		}
-		// Clone the node
-		newnode := node.Copy()
-		newnode.Update(ni2[id])
-		ret.Nodes = append(ret.Nodes, newnode)
+	}
+	return false
+}
 
-		_, ok := rootElements[id]
-		_, ok2 := rootElements2[id]
+// MakeRoot adds the node id to the root elements. The root elements are
+// left deduplicated and sorted. Making a root node a root again is a noop.
+// It returns an error if the node is not in the NodeList.
+func (nl *NodeList) MakeRoot(id string) error {
+	if nl.GetNodeByID(id) == nil {
+		return fmt.Errorf("node with ID %s not found", id)
+	}
+	nl.setRootElements(append(nl.RootElements, id))
+	return nil
+}
 
-		if ok || ok2 {
-			ret.RootElements = append(ret.RootElements, id)
+// UnmakeRoot removes the node id from the root elements, which are left
+// deduplicated and sorted. The node and its edges are not modified.
+func (nl *NodeList) UnmakeRoot(id string) {
+	roots := []string{}
+	for _, r := range nl.RootElements {
+		if r != id {
+			roots = append(roots, r)
 		}
 	}
+	nl.setRootElements(roots)
+}
 
-	// Copy root elements
-	for _, e := range nl2.Edges {
-		existingEdge := ret.GetEdgeByType(e.From, e.Type)
-		if existingEdge == nil {
-			ret.Edges = append(ret.Edges, e.Copy())
-		} else {
-			// Apppend data to existing edge
-			invDict := map[string]struct{}{}
-			for _, t := range existingEdge.To {
-				invDict[t] = struct{}{}
-			}
-
-			for _, to := range e.To {
-				if _, ok := invDict[to]; !ok {
-					existingEdge.To = append(existingEdge.To, to)
-				}
-			}
+// setRootElements replaces the root elements with a sorted deduplicated
+// copy of ids
+func (nl *NodeList) setRootElements(ids []string) {
+	seen := rootElementsIndex{}
+	roots := []string{}
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
 		}
+		seen[id] = struct{}{}
+		roots = append(roots, id)
 	}
+	sort.Strings(roots)
+	nl.RootElements = roots
+}
 
-	// Clean edges
-	ret.cleanEdges()
+// Sort orders the nodes by ID and the edges by origin and type (and then
+// destinations and label), sorting the destinations of each edge. The
+// NodeList is modified in place, calling Sort more than once has no effect.
+// Serializers may call it when canonical output is requested.
+func (nl *NodeList) Sort() {
+	sort.SliceStable(nl.Nodes, func(i, j int) bool {
+		return nl.Nodes[i].Id < nl.Nodes[j].Id
+	})
 
-	return ret
+	nl.SortEdgesTo()
+
+	sort.SliceStable(nl.Edges, func(i, j int) bool {
+		if nl.Edges[i].From != nl.Edges[j].From {
+			return nl.Edges[i].From < nl.Edges[j].From
+		}
+		if nl.Edges[i].Type != nl.Edges[j].Type {
+			return nl.Edges[i].Type < nl.Edges[j].Type
+		}
+		if to1, to2 := strings.Join(nl.Edges[i].To, "+"), strings.Join(nl.Edges[j].To, "+"); to1 != to2 {
+			return to1 < to2
+		}
+		return nl.Edges[i].Label < nl.Edges[j].Label
+	})
 }
 
-// Union returns a new NodeList with all nodes from nl and nl2 joined together
-// any nodes common in nl also found in nl2 will be `Update`d from data from the
-// former.
-func (nl *NodeList) Union(nl2 *NodeList) *NodeList {
-	ret := &NodeList{
-		Nodes:        []*Node{},
-		Edges:        copyEdgeList(nl.Edges),
-		RootElements: nl.RootElements,
+// Canonicalize sorts the NodeList data to give it a stable ordering: nodes
+// and edges are ordered as in Sort and the root elements alphabetically.
+// The graph semantics are not modified and calling it more than once has
+// no effect.
+func (nl *NodeList) Canonicalize() {
+	nl.Sort()
+	sort.Strings(nl.RootElements)
+}
+
+// ReplaceNode swaps the node identified by oldID with newNode. If the ID of
+// newNode is different, all edges and root elements pointing to oldID are
+// rewritten to point to the new ID. It returns an error if oldID cannot be
+// found or if another node already has the new ID (use MergeNodes to
+// consolidate two existing nodes).
+func (nl *NodeList) ReplaceNode(oldID string, newNode *Node) error {
+	if newNode == nil {
+		return errors.New("replacement node is nil")
 	}
 
-	// Copy all nodes from the original nodelist
-	for _, n := range nl.Nodes {
-		ret.Nodes = append(ret.Nodes, n.Copy())
+	index := nl.indexNodes()
+	if _, ok := index[oldID]; !ok {
+		return fmt.Errorf("node with ID %s not found", oldID)
 	}
 
-	// Now reindex to know which one to append or update
-	nodeindex := ret.indexNodes()
-	for _, n := range nl2.Nodes {
-		if _, ok := nodeindex[n.Id]; ok {
-			nodeindex[n.Id].Update(n)
-		} else {
-			ret.Nodes = append(ret.Nodes, n)
+	if newNode.Id != oldID {
+		if _, ok := index[newNode.Id]; ok {
+			return fmt.Errorf("a node with ID %s already exists", newNode.Id)
 		}
 	}
 
-	// Add or append all edges from nl2
-	for _, e := range nl2.Edges {
-		existingEdge := ret.GetEdgeByType(e.From, e.Type)
-		if existingEdge == nil {
-			ret.Edges = append(ret.Edges, e.Copy())
-		} else {
-			for _, to := range e.To {
-				if !existingEdge.PointsTo(to) {
-					existingEdge.To = append(existingEdge.To, to)
-				}
-			}
+	for i := range nl.Nodes {
+		if nl.Nodes[i].Id == oldID {
+			nl.Nodes[i] = newNode
+			break
+		}
+	}
+
+	if newNode.Id != oldID {
+		nl.rewriteID(oldID, newNode.Id)
+	}
+	return nil
+}
+
+// RenameNode changes the ID of the node identified by oldID to newID and
+// rewrites all edges and root elements pointing to it. It returns an error if
+// oldID cannot be found or if newID is already taken by another node.
+func (nl *NodeList) RenameNode(oldID, newID string) error {
+	if oldID == newID {
+		return nil
+	}
+
+	index := nl.indexNodes()
+	if _, ok := index[oldID]; !ok {
+		return fmt.Errorf("node with ID %s not found", oldID)
+	}
+
+	if _, ok := index[newID]; ok {
+		return fmt.Errorf("a node with ID %s already exists", newID)
+	}
+
+	index[oldID].Id = newID
+	nl.rewriteID(oldID, newID)
+	return nil
+}
+
+// DuplicateIDs returns the IDs shared by more than one node of the
+// NodeList in the order they first appear. ID lookups such as GetNodeByID
+// only find the first node with a duplicate ID.
+func (nl *NodeList) DuplicateIDs() []string {
+	ret := []string{}
+	count := map[string]int{}
+	for _, n := range nl.Nodes {
+		count[n.Id]++
+		if count[n.Id] == 2 {
+			ret = append(ret, n.Id)
+		}
+	}
+	return ret
+}
+
+// DisambiguateIDs gives a unique ID to the nodes whose ID is already taken
+// by a previous node by appending a numeric suffix to it. Edges and root
+// elements keep pointing to the first node with the ID. Returns a map of
+// the new IDs to the original ones.
+func (nl *NodeList) DisambiguateIDs() map[string]string {
+	ret := map[string]string{}
+	taken := map[string]struct{}{}
+	for _, n := range nl.Nodes {
+		taken[n.Id] = struct{}{}
+	}
+
+	seen := map[string]struct{}{}
+	for _, n := range nl.Nodes {
+		if _, ok := seen[n.Id]; !ok {
+			seen[n.Id] = struct{}{}
+			continue
+		}
+		for i := 2; ; i++ {
+			id := fmt.Sprintf("%s-%d", n.Id, i)
+			if _, ok := taken[id]; ok {
+				continue
+			}
+			ret[id] = n.Id
+			taken[id] = struct{}{}
+			seen[id] = struct{}{}
+			n.Id = id
+			break
+		}
+	}
+	return ret
+}
+
+// rewriteID replaces all references to oldID in the edges and root elements
+// of the NodeList with newID. It does not modify the nodes.
+func (nl *NodeList) rewriteID(oldID, newID string) {
+	nl.rewriteIDs(map[string]string{oldID: newID})
+}
+
+// rewriteIDs replaces all references to the keys of ids in the edges and
+// root elements of the NodeList with their values in a single pass. It does
+// not modify the nodes.
+func (nl *NodeList) rewriteIDs(ids map[string]string) {
+	for _, e := range nl.Edges {
+		if newID, ok := ids[e.From]; ok {
+			e.From = newID
+		}
+		for i := range e.To {
+			if newID, ok := ids[e.To[i]]; ok {
+				e.To[i] = newID
+			}
+		}
+	}
+
+	rootElements := []string{}
+	seen := rootElementsIndex{}
+	for _, id := range nl.RootElements {
+		if newID, ok := ids[id]; ok {
+			id = newID
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		rootElements = append(rootElements, id)
+	}
+	nl.RootElements = rootElements
+}
+
+// MergeConflictError is returned by MergeNodes when the merged nodes have
+// values that conflict with the kept node. The merge is still performed,
+// keeping the values of the kept node.
+type MergeConflictError struct {
+	KeepID    string
+	Conflicts []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("conflicting values merging into %s: %s", e.KeepID, strings.Join(e.Conflicts, ", "))
+}
+
+// MergeNodes folds the nodes in mergeIDs into the node identified by keepID.
+// The hashes and identifiers of the merged nodes are added to the kept node
+// and any of its empty fields are filled with their data. All edges and root
+// elements pointing to the merged nodes are rewired to keepID and the merged
+// nodes are removed.
+//
+// When a merged node has a value that conflicts with the kept node, the kept
+// node's value wins and the merge returns a *MergeConflictError listing the
+// conflicting fields. Any other error is returned before modifying the
+// NodeList.
+func (nl *NodeList) MergeNodes(keepID string, mergeIDs ...string) error {
+	index := nl.indexNodes()
+	keep, ok := index[keepID]
+	if !ok {
+		return fmt.Errorf("node with ID %s not found", keepID)
+	}
+	for _, id := range mergeIDs {
+		if _, ok := index[id]; !ok {
+			return fmt.Errorf("node with ID %s not found", id)
+		}
+	}
+
+	conflicts := []string{}
+	removed := []string{}
+	for _, id := range mergeIDs {
+		if id == keepID {
+			continue
+		}
+		conflicts = append(conflicts, keep.merge(index[id])...)
+		nl.rewriteID(id, keepID)
+		removed = append(removed, id)
+	}
+
+	// Edges between the merged nodes would now point to the kept node itself.
+	// Edges left with no destinations are dropped.
+	edges := []*Edge{}
+	for _, e := range nl.Edges {
+		if e.From == keepID {
+			tos := []string{}
+			for _, to := range e.To {
+				if to != keepID {
+					tos = append(tos, to)
+				}
+			}
+			e.To = tos
+		}
+		if len(e.To) > 0 {
+			edges = append(edges, e)
+		}
+	}
+	nl.Edges = edges
+
+	nl.RemoveNodes(removed)
+
+	if len(conflicts) > 0 {
+		return &MergeConflictError{KeepID: keepID, Conflicts: conflicts}
+	}
+	return nil
+}
+
+// ReIDByContent renames all nodes in the NodeList to their content ID (see
+// Node.ContentID). Nodes whose content IDs collide are considered to be the
+// same piece of software and are deduplicated: the first node is kept,
+// augmented with the data of the rest as MergeNodes does, and all edges are
+// rewired to it. Merge conflicts are not reported. The old to new ID map is
+// built first and edges and root elements are rewritten in a single pass.
+// An error is returned, before modifying the NodeList, if nodes sharing an
+// ID have different content IDs as their edges could not be rewired.
+func (nl *NodeList) ReIDByContent() error {
+	// Content IDs are computed before any node is modified
+	ids := map[string]string{}
+	for _, n := range nl.Nodes {
+		contentID := n.ContentID()
+		if existing, ok := ids[n.Id]; ok && existing != contentID {
+			return fmt.Errorf("nodes with ID %s have different content", n.Id)
+		}
+		ids[n.Id] = contentID
+	}
+
+	kept := map[string]*Node{}
+	nodes := []*Node{}
+	for _, n := range nl.Nodes {
+		contentID := ids[n.Id]
+		if keep, ok := kept[contentID]; ok {
+			keep.merge(n)
+			continue
+		}
+		kept[contentID] = n
+		nodes = append(nodes, n)
+	}
+
+	// Relationships between merged nodes would point the kept node to itself
+	for _, e := range nl.Edges {
+		tos := []string{}
+		for _, to := range e.To {
+			if to == e.From || ids[to] != ids[e.From] {
+				tos = append(tos, to)
+			}
+		}
+		e.To = tos
+	}
+
+	nl.rewriteIDs(ids)
+	for _, n := range nodes {
+		n.Id = ids[n.Id]
+	}
+	nl.Nodes = nodes
+	nl.cleanEdges()
+	return nil
+}
+
+// Copy returns a deep copy of the NodeList. Modifying the copy or any of its
+// nodes and edges does not affect the original NodeList.
+func (nl *NodeList) Copy() *NodeList {
+	if nl == nil {
+		return nil
+	}
+	return proto.Clone(nl).(*NodeList)
+}
+
+// GetEdgeByType returns a pointer to the first edge found from fromElement
+// of type t.
+func (nl *NodeList) GetEdgeByType(fromElement string, t Edge_Type) *Edge {
+	for _, e := range nl.Edges {
+		if e.From == fromElement && e.Type == t {
+			return e
+		}
+	}
+	return nil
+}
+
+// copyEdgeList is a utility function that deep copies a list of edges
+func copyEdgeList(original []*Edge) []*Edge {
+	nodeCopy := []*Edge{}
+	for _, e := range original {
+		nodeCopy = append(nodeCopy, e.Copy())
+	}
+	return nodeCopy
+}
+
+// Intersect returns a new NodeList with nodes which are common in nl and nl2.
+// All common nodes will be copied from nl and then `Update`d with data from nl2
+func (nl *NodeList) Intersect(nl2 *NodeList) *NodeList {
+	rootElements := nl.indexRootElements()
+	rootElements2 := nl2.indexRootElements()
+
+	ret := &NodeList{
+		Nodes:        []*Node{},
+		Edges:        copyEdgeList(nl.Edges), // copied as they will be cleaned
+		RootElements: []string{},
+	}
+	var ni1, ni2 nodeIndex
+
+	ni1 = nl.indexNodes()
+	ni2 = nl2.indexNodes()
+
+	for id, node := range ni1 {
+		if _, ok := ni2[id]; !ok {
+			continue
+		}
+		// Clone the node
+		newnode := node.Copy()
+		newnode.Update(ni2[id])
+		ret.Nodes = append(ret.Nodes, newnode)
+
+		_, ok := rootElements[id]
+		_, ok2 := rootElements2[id]
+
+		if ok || ok2 {
+			ret.RootElements = append(ret.RootElements, id)
+		}
+	}
+
+	// Copy root elements
+	for _, e := range nl2.Edges {
+		existingEdge := ret.GetEdgeByType(e.From, e.Type)
+		if existingEdge == nil {
+			ret.Edges = append(ret.Edges, e.Copy())
+		} else {
+			// Apppend data to existing edge
+			invDict := map[string]struct{}{}
+			for _, t := range existingEdge.To {
+				invDict[t] = struct{}{}
+			}
+
+			for _, to := range e.To {
+				if _, ok := invDict[to]; !ok {
+					existingEdge.To = append(existingEdge.To, to)
+				}
+			}
+		}
+	}
+
+	// Clean edges
+	ret.cleanEdges()
+
+	return ret
+}
+
+// Union returns a new NodeList with all nodes from nl and nl2 joined together
+// any nodes common in nl also found in nl2 will be `Update`d from data from the
+// former.
+func (nl *NodeList) Union(nl2 *NodeList) *NodeList {
+	ret := &NodeList{
+		Nodes:        []*Node{},
+		Edges:        copyEdgeList(nl.Edges),
+		RootElements: nl.RootElements,
+	}
+
+	// Copy all nodes from the original nodelist
+	for _, n := range nl.Nodes {
+		ret.Nodes = append(ret.Nodes, n.Copy())
+	}
+
+	// Now reindex to know which one to append or update
+	nodeindex := ret.indexNodes()
+	for _, n := range nl2.Nodes {
+		if _, ok := nodeindex[n.Id]; ok {
+			nodeindex[n.Id].Update(n)
+		} else {
+			ret.Nodes = append(ret.Nodes, n)
+		}
+	}
+
+	// Add or append all edges from nl2. The combined list is consolidated
+	// below so identical edges from both lists collapse into one.
+	for _, e := range nl2.Edges {
+		existingEdge := ret.GetEdgeByType(e.From, e.Type)
+		if existingEdge == nil || existingEdge.Label != e.Label {
+			ret.Edges = append(ret.Edges, e.Copy())
+		} else {
+			for _, to := range e.To {
+				if !existingEdge.PointsTo(to) {
+					existingEdge.To = append(existingEdge.To, to)
+				}
+			}
 		}
 	}
 
@@ -342,132 +1138,542 @@ func (nl *NodeList) Union(nl2 *NodeList) *NodeList {
 		}
 	}
 
-	return ret
-}
-
-// GetNodesByName returns a list of node pointers whose name equals name
-func (nl *NodeList) GetNodesByName(name string) []*Node {
-	ret := []*Node{}
+	return ret
+}
+
+// MergeSourceProperty is the node property where the merge functions
+// record the MergeOptions.SourceTag of the nodes they add
+const MergeSourceProperty = "protobom:merge:source"
+
+// MergeOptions control how NodeLists are combined by UnionWithOptions and
+// AddWithOptions
+type MergeOptions struct {
+	// EdgePrecedence ranks edge types. When the merged graph relates the
+	// same two nodes with more than one ranked type (precedence above zero)
+	// only the relationship with the highest precedence is kept, ties are
+	// broken by keeping the lowest edge type. Types ranked zero or lower
+	// are never collapsed.
+	EdgePrecedence func(Edge_Type) int
+
+	// SourceTag identifies the origin of the merged in NodeList. When set,
+	// the nodes it adds are stamped with it in their MergeSourceProperty
+	// property. Nodes already present are not modified.
+	SourceTag string
+
+	// RejectHashConflicts makes AddWithOptions fail with ErrHashConflict,
+	// leaving the NodeList untouched, when a node being merged has a
+	// different digest than the existing one for the same algorithm.
+	// Otherwise the conflicts are only recorded in the AddReport. Union
+	// cannot fail, check NodeList.HashConflicts before calling it.
+	RejectHashConflicts bool
+}
+
+// UnionWithOptions works as Union but resolves conflicting relationships
+// and records provenance according to the merge options
+func (nl *NodeList) UnionWithOptions(nl2 *NodeList, opts MergeOptions) *NodeList {
+	ret := nl.Union(nl2.withSourceTag(nl.indexNodes(), opts.SourceTag))
+	ret.applyMergeOptions(opts)
+	return ret
+}
+
+// AddWithOptions works as Add but resolves conflicting relationships,
+// records provenance and checks hash conflicts according to the merge
+// options. It returns a report of the nodes that were added and the ones
+// already present, see AddWithReport.
+func (nl *NodeList) AddWithOptions(nl2 *NodeList, opts MergeOptions) (*AddReport, error) {
+	if nl2 == nil {
+		return nil, errors.New("nodelist to add is nil")
+	}
+
+	if opts.RejectHashConflicts {
+		if conflicts := nl.HashConflicts(nl2); len(conflicts) > 0 {
+			c := conflicts[0]
+			return nil, fmt.Errorf(
+				"node %s %s digest %s does not match %s: %w",
+				c.ID, c.Algorithm, c.Incoming, c.Existing, ErrHashConflict,
+			)
+		}
+	}
+
+	report, err := nl.AddWithReport(nl2.withSourceTag(nl.indexNodes(), opts.SourceTag))
+	if err != nil {
+		return nil, err
+	}
+	nl.applyMergeOptions(opts)
+	return report, nil
+}
+
+// withSourceTag returns a NodeList where the nodes not in existing are
+// copies stamped with the source tag. The original nodes are not modified.
+// If tag is empty, nl is returned unchanged.
+func (nl *NodeList) withSourceTag(existing map[string]*Node, tag string) *NodeList {
+	if nl == nil || tag == "" {
+		return nl
+	}
+
+	ret := &NodeList{
+		Nodes:        make([]*Node, 0, len(nl.Nodes)),
+		Edges:        nl.Edges,
+		RootElements: nl.RootElements,
+	}
+	for _, n := range nl.Nodes {
+		if _, ok := existing[n.Id]; ok {
+			ret.Nodes = append(ret.Nodes, n)
+			continue
+		}
+		n = n.Copy()
+		if n.Properties == nil {
+			n.Properties = map[string]string{}
+		}
+		n.Properties[MergeSourceProperty] = tag
+		ret.Nodes = append(ret.Nodes, n)
+	}
+	return ret
+}
+
+// applyMergeOptions collapses the conflicting edges of the nodelist
+func (nl *NodeList) applyMergeOptions(opts MergeOptions) {
+	if opts.EdgePrecedence == nil {
+		return
+	}
+
+	// Find the winning type of every ranked relationship
+	winners := map[[2]string]Edge_Type{}
+	for _, e := range nl.Edges {
+		p := opts.EdgePrecedence(e.Type)
+		if p <= 0 {
+			continue
+		}
+		for _, to := range e.To {
+			key := [2]string{e.From, to}
+			w, ok := winners[key]
+			if !ok {
+				winners[key] = e.Type
+				continue
+			}
+			if wp := opts.EdgePrecedence(w); p > wp || (p == wp && e.Type < w) {
+				winners[key] = e.Type
+			}
+		}
+	}
+
+	edges := []*Edge{}
+	for _, e := range nl.Edges {
+		if opts.EdgePrecedence(e.Type) <= 0 {
+			edges = append(edges, e)
+			continue
+		}
+		tos := []string{}
+		for _, to := range e.To {
+			if winners[[2]string{e.From, to}] == e.Type {
+				tos = append(tos, to)
+			}
+		}
+		switch {
+		case len(tos) == len(e.To):
+			edges = append(edges, e)
+		case len(tos) > 0:
+			// Edges may be shared with the merged nodelist, copy it
+			newEdge := e.Copy()
+			newEdge.To = tos
+			edges = append(edges, newEdge)
+		}
+	}
+	nl.Edges = edges
+}
+
+// GetNodesByName returns a list of node pointers whose name equals name
+func (nl *NodeList) GetNodesByName(name string) []*Node {
+	ret := []*Node{}
+	for i := range nl.Nodes {
+		if nl.Nodes[i].Name == name {
+			ret = append(ret, nl.Nodes[i])
+		}
+	}
+	return ret
+}
+
+// GetNodeByID returns a node with the specified ID
+func (nl *NodeList) GetNodeByID(id string) *Node {
+	for i := range nl.Nodes {
+		if nl.Nodes[i].Id == id {
+			return nl.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+// matchIdentifierTypes lists the software identifiers used to match nodes
+// ordered from the strongest to the weakest
+var matchIdentifierTypes = []SoftwareIdentifierType{
+	SoftwareIdentifierType_GITOID,
+	SoftwareIdentifierType_PURL,
+	SoftwareIdentifierType_CPE23,
+	SoftwareIdentifierType_CPE22,
+}
+
+// matchExternalReferenceTypes lists the external reference types pointing
+// to a single artifact, used to match nodes with no common identifiers
+var matchExternalReferenceTypes = []ExternalReference_ExternalReferenceType{
+	ExternalReference_DISTRIBUTION,
+	ExternalReference_SWH,
+	ExternalReference_SWID,
+}
+
+// GetMatchingNode looks up a node in the NodeList that matches the piece of
+// software described by testNode. It will not match on ID but rather matching
+// is performed by hash and then by software identifier in order of strength:
+// gitoid, purl, CPE 2.3 and CPE 2.2. Nodes with no identifier in common are
+// finally matched by their distribution, Software Heritage or SWID external
+// references.
+//
+// A node is never matched on a weaker identifier when it has a stronger one
+// that is also present in testNode but differs from it.
+//
+// This function is guaranteed to only return a node when there is a single node
+// match. If more than one node matches, an ErrorMoreThanOneMatch is returned.
+//
+// See node.HashesMatch to understand how hashes are compared.
+func (nl *NodeList) GetMatchingNode(node *Node) (*Node, error) {
+	// If the target node has hashes, look for it
+	foundNodes := map[string]*Node{}
+	if len(node.Hashes) > 0 {
+		hashIndex := nl.indexNodesByHash()
+		for algo, hashVal := range node.Hashes {
+			// If there is at least one node with one of the hashes:
+			if _, ok := hashIndex[hashIndexKey(algo, hashVal)]; !ok {
+				continue
+			}
+			// Collect all node where hashes match excactly
+			for _, n := range hashIndex[hashIndexKey(algo, hashVal)] {
+				// Ignore if we've seen the node
+				if _, ok := foundNodes[n.Id]; ok {
+					continue
+				}
+
+				// Collect the node if hashes match
+				if n.HashesMatch(node.Hashes) {
+					foundNodes[n.Id] = n
+				}
+			}
+		}
+	}
+
+	// Here, if we have exactly one node, then we have a match. If we have zero
+	// then we reindex and match on the identifiers. If more than one node matched
+	// on the hashes, we try to disabiguate by looking at their identifiers.
+	switch len(foundNodes) {
+	case 1:
+		// If there is a single match, our job is done.
+		for _, n := range foundNodes {
+			return n, nil
+		}
+	case 0:
+		// No matches by hash, try to match by each identifier type
+		// TODO(puerco): Purls should be normalized to match correctly,
+		// even more: ensuring correct globing of qualifiers.
+		for i, t := range matchIdentifierTypes {
+			testID := node.identifier(t)
+			if testID == "" {
+				continue
+			}
+
+			matches := []*Node{}
+			for _, n := range nl.indexNodesByIdentifier(t)[testID] {
+				if !identifiersConflict(node, n, matchIdentifierTypes[:i]) {
+					matches = append(matches, n)
+				}
+			}
+
+			switch len(matches) {
+			case 0:
+				continue
+			case 1:
+				return matches[0], nil
+			default:
+				// If there is more than one matching, its a tie. Error.
+				return nil, ErrorMoreThanOneMatch
+			}
+		}
+		return nl.matchByExternalReference(node)
+	default:
+		// Multiple hash matches, look to see if there is a single one where
+		// an identifier matches to break the ambiguity:
+		for _, t := range matchIdentifierTypes {
+			testID := node.identifier(t)
+			if testID == "" {
+				continue
+			}
+
+			foundByID := []*Node{}
+			for _, n := range foundNodes {
+				if n.identifier(t) == testID {
+					foundByID = append(foundByID, n)
+				}
+			}
+
+			if len(foundByID) == 1 {
+				return foundByID[0], nil
+			}
+		}
+		return nil, ErrorMoreThanOneMatch
+	}
+	return nil, nil
+}
+
+// matchByExternalReference looks up the node that shares an external
+// reference to the same artifact with testNode, see
+// matchExternalReferenceTypes. Nodes with conflicting identifiers are not
+// matched.
+func (nl *NodeList) matchByExternalReference(testNode *Node) (*Node, error) {
+	for _, t := range matchExternalReferenceTypes {
+		var index map[string][]*Node
+		for _, er := range testNode.ExternalReferences {
+			if er.Url == "" || er.EffectiveType() != t {
+				continue
+			}
+			if index == nil {
+				index = nl.indexNodesByExternalReference(t)
+			}
+
+			matches := []*Node{}
+			for _, n := range index[er.Url] {
+				if !identifiersConflict(testNode, n, matchIdentifierTypes) {
+					matches = append(matches, n)
+				}
+			}
+
+			switch len(matches) {
+			case 0:
+				continue
+			case 1:
+				return matches[0], nil
+			default:
+				return nil, ErrorMoreThanOneMatch
+			}
+		}
+	}
+	return nil, nil
+}
+
+// MatchOptions control how GetMatchingNodeWithOptions looks up nodes
+type MatchOptions struct {
+	// StrongHashAlgorithms lists the hash algorithms trusted to identify
+	// a node. Matches on other algorithms are only reported as weak
+	// matches. When empty, all algorithms are trusted.
+	StrongHashAlgorithms []HashAlgorithm
+}
+
+// MatchResult is the outcome of GetMatchingNodeWithOptions
+type MatchResult struct {
+	// Node is the node confidently matched, nil if there is none
+	Node *Node
+
+	// WeakMatches are the nodes that, without a confident match, share
+	// hashes computed with weak algorithms with the test node
+	WeakMatches []*Node
+}
+
+// GetMatchingNodeWithOptions works as GetMatchingNode but only hashes of
+// the strong algorithms in opts (and the software identifiers) produce a
+// confident match. If none is found, the nodes matching on the weak hashes
+// are returned as weak matches instead of being trusted, so two nodes
+// sharing an MD5 hash are not an error but two weak candidates.
+func (nl *NodeList) GetMatchingNodeWithOptions(node *Node, opts MatchOptions) (*MatchResult, error) {
+	if len(opts.StrongHashAlgorithms) == 0 {
+		n, err := nl.GetMatchingNode(node)
+		if err != nil {
+			return nil, err
+		}
+		return &MatchResult{Node: n, WeakMatches: []*Node{}}, nil
+	}
+
+	strong := map[HashAlgorithm]struct{}{}
+	for _, a := range opts.StrongHashAlgorithms {
+		strong[a] = struct{}{}
+	}
+	strongHashes := map[string]string{}
+	weakHashes := map[string]string{}
+	for algo, digest := range node.Hashes {
+		if _, ok := strong[HashAlgorithmFromString(algo)]; ok {
+			strongHashes[algo] = digest
+		} else {
+			weakHashes[algo] = digest
+		}
+	}
+
+	// Look for a confident match with the strong hashes and identifiers
+	probe := &Node{
+		Type:               node.Type,
+		Identifiers:        node.Identifiers,
+		Hashes:             strongHashes,
+		ExternalReferences: node.ExternalReferences,
+	}
+	n, err := nl.GetMatchingNode(probe)
+	if err != nil {
+		return nil, err
+	}
+	ret := &MatchResult{Node: n, WeakMatches: []*Node{}}
+	if n != nil || len(weakHashes) == 0 {
+		return ret, nil
+	}
+
+	for _, n := range nl.Nodes {
+		if n.HashesMatch(weakHashes) && !identifiersConflict(node, n, matchIdentifierTypes) {
+			ret.WeakMatches = append(ret.WeakMatches, n)
+		}
+	}
+	return ret, nil
+}
+
+// identifiersConflict returns true if n1 and n2 have different values for
+// any of the identifier types in types
+func identifiersConflict(n1, n2 *Node, types []SoftwareIdentifierType) bool {
+	for _, t := range types {
+		id1, id2 := n1.identifier(t), n2.identifier(t)
+		if id1 != "" && id2 != "" && id1 != id2 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNodesByIdentifier returns nodes that match an identifier of type t and
+// value v, for example t = "purl" v = "pkg:deb/debian/libpam-modules@1.4.0-9+deb11u1?arch=i386"
+// Not that this only does "dumb" string matching no assumptions are made on the
+// identifier type, except for CPEs which are normalized and compared in
+// their CPE 2.3 form, so a CPE 2.2 finds the nodes with the equivalent CPE
+// 2.3 and vice versa.
+func (nl *NodeList) GetNodesByIdentifier(t, v string) []*Node {
+	ret := []*Node{}
+	idType := SoftwareIdentifierTypeFromString(t)
+
+	// CPE 2.3 strings are normalized before comparing
+	if idType == SoftwareIdentifierType_CPE23 {
+		return append(ret, nl.indexNodesByCPE()[normalizeCPE23(v)]...)
+	}
+	if idType == SoftwareIdentifierType_CPE22 {
+		if cpe, err := CPE22to23(v); err == nil {
+			return append(ret, nl.indexNodesByCPE()[normalizeCPE23(cpe)]...)
+		}
+	}
+
 	for i := range nl.Nodes {
-		if nl.Nodes[i].Name == name {
+		if nl.Nodes[i].Identifiers == nil {
+			continue
+		}
+
+		if _, ok := nl.Nodes[i].Identifiers[int32(idType)]; ok && nl.Nodes[i].Identifiers[int32(idType)] == v {
 			ret = append(ret, nl.Nodes[i])
 		}
 	}
 	return ret
 }
 
-// GetNodeByID returns a node with the specified ID
-func (nl *NodeList) GetNodeByID(id string) *Node {
+// GetNodesReleasedBefore returns the nodes with a release date before t.
+// Nodes without a release date are not returned.
+func (nl *NodeList) GetNodesReleasedBefore(t time.Time) []*Node {
+	ret := []*Node{}
 	for i := range nl.Nodes {
-		if nl.Nodes[i].Id == id {
-			return nl.Nodes[i]
+		if nl.Nodes[i].ReleaseDate == nil {
+			continue
+		}
+		if nl.Nodes[i].ReleaseDate.AsTime().Before(t) {
+			ret = append(ret, nl.Nodes[i])
 		}
 	}
-
-	return nil
+	return ret
 }
 
-// GetMatchingNode looks up a node in the NodeList that matches the piece of
-// software described by testNode. It will not match on ID but rather matching
-// is performed by hash then by purl.
-//
-// This function is guaranteed to only return a node when there is a single node
-// match. If more than one node matches, an ErrorMoreThanOneMatch is returned.
-//
-// See node.HashesMatch to understand how hashes are compared.
-func (nl *NodeList) GetMatchingNode(node *Node) (*Node, error) {
-	// If the target node has hashes, look for it
-	foundNodes := map[string]*Node{}
-	if len(node.Hashes) > 0 {
-		hashIndex := nl.indexNodesByHash()
-		for algo, hashVal := range node.Hashes {
-			// If there is at least one node with one of the hashes:
-			if _, ok := hashIndex[fmt.Sprintf("%s:%s", algo, hashVal)]; !ok {
-				continue
-			}
-			// Collect all node where hashes match excactly
-			for _, n := range hashIndex[fmt.Sprintf("%s:%s", algo, hashVal)] {
-				// Ignore if we've seen the node
-				if _, ok := foundNodes[n.Id]; ok {
-					continue
-				}
-
-				// Collect the node if hashes match
-				if n.HashesMatch(node.Hashes) {
-					foundNodes[n.Id] = n
-				}
-			}
-		}
+// GetNodesInVersionRange returns the nodes named name whose version
+// satisfies the version constraint, eg "< 3.0.7". Nodes without a version
+// are not returned. See Node.VersionInRange for how versions are compared.
+func (nl *NodeList) GetNodesInVersionRange(name, constraint string) ([]*Node, error) {
+	if _, err := parseVersionRange(constraint); err != nil {
+		return nil, fmt.Errorf("parsing version range: %w", err)
 	}
 
-	// Here, if we have exactly one node, then we have a match. If we have zero
-	// then we reindex and match on the purl. If more than one node matched on
-	// the hashes, we try to disabiguate by looking at the purl of the hash matches.
-	testPurl := node.Purl()
-	switch len(foundNodes) {
-	case 1:
-		// If there is a single match, our job is done.
-		for _, n := range foundNodes {
-			return n, nil
-		}
-	case 0:
-		// No matches by hash, try to match by purl
-		// TODO(puerco): Purls should be normalized to match correctly,
-		// even more: ensuring correct globing of qualifiers.
-		if testPurl == "" {
-			return nil, nil
-		}
-		pindex := nl.indexNodesByPurl()
-		if _, ok := pindex[testPurl]; !ok {
-			return nil, nil
+	ret := []*Node{}
+	for _, n := range nl.GetNodesByName(name) {
+		if n.Version == "" {
+			continue
 		}
-		// If there is more than one matching, its a tie. Error.
-		if len(pindex[testPurl]) == 1 {
-			return pindex[testPurl][0], nil
+		ok, err := n.VersionInRange(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("checking version of %s: %w", n.Id, err)
 		}
-		return nil, ErrorMoreThanOneMatch
-	default:
-		// Multiple hash matches, look to see if there is a single one where
-		// the purl matches to break the ambiguity:
-		if testPurl == "" {
-			return nil, ErrorMoreThanOneMatch
+		if ok {
+			ret = append(ret, n)
 		}
+	}
+	return ret, nil
+}
 
-		foundByPurl := []*Node{}
-		for _, n := range foundNodes {
-			if tp := n.Purl(); tp != "" && tp == testPurl {
-				foundByPurl = append(foundByPurl, n)
+// GetNodesBySupplier returns the nodes that have a supplier named name
+func (nl *NodeList) GetNodesBySupplier(name string) []*Node {
+	ret := []*Node{}
+	for i := range nl.Nodes {
+		for _, s := range nl.Nodes[i].Suppliers {
+			if s.Name == name {
+				ret = append(ret, nl.Nodes[i])
+				break
 			}
 		}
+	}
+	return ret
+}
 
-		if len(foundByPurl) == 1 {
-			return foundByPurl[0], nil
+// GetNodesByLicense returns the nodes whose declared or concluded license
+// expression references the license identifier id. Expressions are parsed,
+// so a node licensed under "GPL-2.0-only OR MIT" is returned when querying
+// for either license. As in the SPDX spec, IDs are matched case-insensitively.
+func (nl *NodeList) GetNodesByLicense(id string) []*Node {
+	ret := []*Node{}
+	for i := range nl.Nodes {
+		for _, l := range nl.Nodes[i].LicenseIDs() {
+			if strings.EqualFold(l, id) {
+				ret = append(ret, nl.Nodes[i])
+				break
+			}
 		}
-		return nil, ErrorMoreThanOneMatch
 	}
-	return nil, nil
+	return ret
 }
 
-// GetNodesByIdentifier returns nodes that match an identifier of type t and
-// value v, for example t = "purl" v = "pkg:deb/debian/libpam-modules@1.4.0-9+deb11u1?arch=i386"
-// Not that this only does "dumb" string matching no assumptions are made on the
-// identifier type.
-func (nl *NodeList) GetNodesByIdentifier(t, v string) []*Node {
+// RootNodes returns the root nodes of the NodeList in the order of its
+// RootElements. Root elements with no matching node are skipped, use
+// MissingRootElements to list them.
+func (nl *NodeList) RootNodes() []*Node {
+	index := nl.indexNodes()
 	ret := []*Node{}
-	idType := SoftwareIdentifierTypeFromString(t)
-	for i := range nl.Nodes {
-		if nl.Nodes[i].Identifiers == nil {
-			continue
+	for _, id := range nl.RootElements {
+		if n, ok := index[id]; ok {
+			ret = append(ret, n)
 		}
+	}
+	return ret
+}
 
-		if _, ok := nl.Nodes[i].Identifiers[int32(idType)]; ok && nl.Nodes[i].Identifiers[int32(idType)] == v {
-			ret = append(ret, nl.Nodes[i])
+// MissingRootElements returns the IDs in RootElements that do not match any
+// node in the NodeList
+func (nl *NodeList) MissingRootElements() []string {
+	index := nl.indexNodes()
+	ret := []string{}
+	for _, id := range nl.RootElements {
+		if _, ok := index[id]; !ok {
+			ret = append(ret, id)
 		}
 	}
 	return ret
 }
 
 // GetRootNodes returns a list of pointers of the root nodes of the document
+// in the order they appear in Nodes. Root elements not found are skipped,
+// see MissingRootElements.
+//
+// Deprecated: Use RootNodes, which returns the root nodes in the order of
+// the RootElements.
 func (nl *NodeList) GetRootNodes() []*Node {
 	ret := []*Node{}
 	index := rootElementsIndex{}
@@ -482,15 +1688,155 @@ func (nl *NodeList) GetRootNodes() []*Node {
 			}
 		}
 	}
-	// TODO(ehandling): What if not all nodes were found?
 	return ret
 }
 
-// Equal returns true if the NodeList nl is equal to nl2
+// EqualOptions control how NodeLists are compared by EqualWithOptions
+type EqualOptions struct {
+	// IgnoreFields lists the node fields, by their protobuf name (eg
+	// "release_date"), left out of the comparison. Unknown names and the
+	// node id are ignored, use NormalizeID to compare IDs loosely.
+	IgnoreFields []string
+
+	// NormalizeID is applied to the node IDs, edge endpoints and root
+	// elements of both NodeLists before comparing them
+	NormalizeID func(string) string
+
+	// IgnoreIDs matches the nodes by their identity instead of their IDs:
+	// their canonical purl or, when they have none, their hashes or their
+	// name and version. It takes precedence over NormalizeID. Edges and
+	// root elements are compared through the identities of the nodes.
+	IgnoreIDs bool
+}
+
+// DescribedElements returns the IDs of the nodes the NodeList describes:
+// the root elements found in its nodes. When none of the roots are found,
+// the nodes without incoming edges are returned or, if every node has one
+// (the graph is a cycle), all the nodes.
+func (nl *NodeList) DescribedElements() []string {
+	index := nl.indexNodes()
+	ret := []string{}
+	for _, id := range nl.RootElements {
+		if _, ok := index[id]; ok {
+			ret = append(ret, id)
+		}
+	}
+	if len(ret) > 0 || len(nl.Nodes) == 0 {
+		return ret
+	}
+
+	targets := map[string]struct{}{}
+	for _, e := range nl.Edges {
+		for _, to := range e.To {
+			targets[to] = struct{}{}
+		}
+	}
+	for _, n := range nl.Nodes {
+		if _, ok := targets[n.Id]; !ok {
+			ret = append(ret, n.Id)
+		}
+	}
+	if len(ret) > 0 {
+		return ret
+	}
+
+	for _, n := range nl.Nodes {
+		ret = append(ret, n.Id)
+	}
+	return ret
+}
+
+// Equal returns true if the NodeList nl is equal to nl2. The comparison is
+// not sensitive to ordering: nodes are compared by ID, edges by their origin,
+// type and (unordered) destinations and root elements as a set.
 func (nl *NodeList) Equal(nl2 *NodeList) bool {
+	return nl.EqualWithOptions(nl2, EqualOptions{})
+}
+
+// EqualWithOptions compares nl to nl2 like Equal but allows ignoring node
+// fields and normalizing IDs before the comparison. The NodeLists are not
+// modified.
+func (nl *NodeList) EqualWithOptions(nl2 *NodeList, opts EqualOptions) bool {
 	if nl2 == nil {
 		return false
 	}
+	if len(opts.IgnoreFields) == 0 && opts.NormalizeID == nil && !opts.IgnoreIDs {
+		return nl.equal(nl2)
+	}
+	return nl.normalize(opts).equal(nl2.normalize(opts))
+}
+
+// normalize returns a copy of the NodeList with the EqualOptions applied
+func (nl *NodeList) normalize(opts EqualOptions) *NodeList {
+	ret := nl.Copy()
+
+	normalizeID := opts.NormalizeID
+	if opts.IgnoreIDs {
+		// The identities are computed before clearing any ignored fields
+		identities := map[string]string{}
+		for _, n := range ret.Nodes {
+			identities[n.Id] = n.identityKey()
+		}
+		normalizeID = func(id string) string {
+			if key, ok := identities[id]; ok {
+				return key
+			}
+			return id
+		}
+	}
+
+	fields := []protoreflect.FieldDescriptor{}
+	nodeFields := (&Node{}).ProtoReflect().Descriptor().Fields()
+	for _, name := range opts.IgnoreFields {
+		if fd := nodeFields.ByName(protoreflect.Name(name)); fd != nil && name != "id" {
+			fields = append(fields, fd)
+		}
+	}
+
+	for _, n := range ret.Nodes {
+		for _, fd := range fields {
+			n.ProtoReflect().Clear(fd)
+		}
+		if normalizeID != nil {
+			n.Id = normalizeID(n.Id)
+		}
+	}
+
+	if normalizeID != nil {
+		for _, e := range ret.Edges {
+			e.From = normalizeID(e.From)
+			for i := range e.To {
+				e.To[i] = normalizeID(e.To[i])
+			}
+		}
+		for i := range ret.RootElements {
+			ret.RootElements[i] = normalizeID(ret.RootElements[i])
+		}
+	}
+	return ret
+}
+
+// identityKey returns a string identifying the node by its content instead
+// of its ID: its canonical purl, its sorted hashes or its name and version
+func (n *Node) identityKey() string {
+	if purl := n.Purl(); purl != "" {
+		return "purl:" + string(purl.Canonical())
+	}
+
+	if len(n.Hashes) > 0 {
+		hashes := []string{}
+		for algo, digest := range n.Hashes {
+			hashes = append(hashes, algo+":"+strings.ToLower(digest))
+		}
+		sort.Strings(hashes)
+		return "hashes:" + strings.Join(hashes, ",")
+	}
+
+	return fmt.Sprintf("name:%s@%s", n.Name, n.Version)
+}
+
+// equal performs the strict comparison of two NodeLists
+func (nl *NodeList) equal(nl2 *NodeList) bool {
 
 	// First, quick one: Compare the lengths of the internals:
 	if len(nl.Edges) != len(nl2.Edges) ||
@@ -499,50 +1845,57 @@ func (nl *NodeList) Equal(nl2 *NodeList) bool {
 		return false
 	}
 
-	// Compare the flattened rootElements list
-	r1 := nl.RootElements
-	r2 := nl2.RootElements
+	// Compare the flattened rootElements list. The lists are copied
+	// to avoid reordering the original data
+	r1 := append([]string{}, nl.RootElements...)
+	r2 := append([]string{}, nl2.RootElements...)
 	sort.Strings(r1)
 	sort.Strings(r2)
 	if !reflect.DeepEqual(r1, r2) {
 		return false
 	}
 
-	// Compare the flattenned edges
-	nlEdges := []string{}
-	for _, e := range nl.Edges {
-		nlEdges = append(nlEdges, e.flatString())
-	}
-	sort.Strings(nlEdges)
-
-	nl2Edges := []string{}
+	// Compare the edges. Each edge in nl must match a different
+	// edge with the same origin and type in nl2
+	nl2Edges := map[string][]*Edge{}
 	for _, e := range nl2.Edges {
-		nl2Edges = append(nl2Edges, e.flatString())
+		key := e.From + "+++" + e.Type.String()
+		nl2Edges[key] = append(nl2Edges[key], e)
 	}
-	sort.Strings(nl2Edges)
-
-	if !reflect.DeepEqual(nlEdges, nl2Edges) {
-		return false
+	for _, e := range nl.Edges {
+		key := e.From + "+++" + e.Type.String()
+		found := false
+		for i, e2 := range nl2Edges[key] {
+			if e.Equal(e2) {
+				nl2Edges[key] = append(nl2Edges[key][:i], nl2Edges[key][i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
 	}
 
 	// Compare the nodes
-	nlNodes := map[string]string{}
-	nl2Nodes := map[string]string{}
-	for _, n := range nl.Nodes {
-		nlNodes[n.Id] = n.Checksum()
+	nl2Nodes := nl2.indexNodes()
+	if len(nl.indexNodes()) != len(nl2Nodes) {
+		return false
 	}
-
-	for _, n := range nl2.Nodes {
-		nl2Nodes[n.Id] = n.Checksum()
+	for _, n := range nl.Nodes {
+		n2, ok := nl2Nodes[n.Id]
+		if !ok || !n.Equal(n2) {
+			return false
+		}
 	}
-
-	return cmp.Equal(nlNodes, nl2Nodes)
+	return true
 }
 
 // RelateNodeListAtID relates the top level nodes in nl2 to the node with ID
 // nodeID using a relationship of type edgeType. Returns an error if nodeID cannot
 // be found in the graph. This function assumes that nodes in nl and nl2 having
-// the same ID are equivalent and will be deduped.
+// the same ID are equivalent and will be deduped. The edges of nl2 are added
+// to nl to keep the relationships between its nodes.
 func (nl *NodeList) RelateNodeListAtID(nl2 *NodeList, nodeID string, edgeType Edge_Type) error {
 	// Check the node exists
 	nlIndex := nl.indexNodes()
@@ -579,21 +1932,26 @@ func (nl *NodeList) RelateNodeListAtID(nl2 *NodeList, nodeID string, edgeType Ed
 		nl.AddNode(n)
 	}
 
+	// Keep the internal structure of the related nodelist
+	for _, e := range nl2.Edges {
+		nl.AddEdge(e)
+	}
+
 	return nil
 }
 
 // GetNodesByPurlType returns a nodelist containing all nodes that match
-// a purl (package url) type. An empty purlType returns a blank nodelist
+// a purl (package url) type. Types are matched case-insensitively. An empty
+// purlType returns a blank nodelist
 func (nl *NodeList) GetNodesByPurlType(purlType string) *NodeList {
 	ret := &NodeList{}
-	if nl == nil {
+	if nl == nil || purlType == "" {
 		return ret
 	}
 
+	purlType = strings.ToLower(purlType)
 	for _, n := range nl.Nodes {
-		// I think the SPDX libraries have a bug where an extra slash is added when parsing purls
-		if strings.HasPrefix(string(n.Purl()), fmt.Sprintf("pkg:%s/", purlType)) ||
-			strings.HasPrefix(string(n.Purl()), fmt.Sprintf("pkg:/%s/", purlType)) {
+		if n.Purl().Type() == purlType {
 			ret.Nodes = append(ret.Nodes, n)
 		}
 	}
@@ -611,6 +1969,187 @@ func (nl *NodeList) GetNodesByPurlType(purlType string) *NodeList {
 	return ret
 }
 
+// RetainEdgeTypes returns a copy of the NodeList keeping only the edges of
+// the listed types. All nodes are kept, to drop the ones left disconnected
+// from the root elements call PruneUnreachable on the returned NodeList.
+func (nl *NodeList) RetainEdgeTypes(types ...Edge_Type) *NodeList {
+	ret := nl.Copy()
+	if ret == nil {
+		return &NodeList{}
+	}
+
+	keep := map[Edge_Type]struct{}{}
+	for _, t := range types {
+		keep[t] = struct{}{}
+	}
+
+	edges := []*Edge{}
+	for _, e := range ret.Edges {
+		if _, ok := keep[e.Type]; ok {
+			edges = append(edges, e)
+		}
+	}
+	ret.Edges = edges
+	return ret
+}
+
+// ExcludeEdgeTypes returns a copy of the NodeList without the edges of the
+// given types and without the nodes only reachable from the root elements
+// through them. The graph is walked following the direction of each edge
+// type (see Edge_Type.PointsToDependent) so a node reachable through the
+// removed edges is kept only if the remaining edges still lead to it.
+// Nodes that were not reachable in the first place are not touched. When
+// the NodeList has no root elements, the nodes left with no edges are
+// removed instead. Root elements are always kept.
+func (nl *NodeList) ExcludeEdgeTypes(types ...Edge_Type) *NodeList {
+	ret := nl.Copy()
+	if ret == nil {
+		return &NodeList{}
+	}
+
+	exclude := map[Edge_Type]struct{}{}
+	for _, t := range types {
+		exclude[t] = struct{}{}
+	}
+
+	edges := []*Edge{}
+	for _, e := range ret.Edges {
+		if _, ok := exclude[e.Type]; !ok {
+			edges = append(edges, e)
+		}
+	}
+	if len(edges) == len(ret.Edges) {
+		return ret
+	}
+
+	before := reachableAlong(ret.Edges, ret.RootElements)
+	after := reachableAlong(edges, ret.RootElements)
+	ret.Edges = edges
+
+	remove := []string{}
+	for _, n := range ret.Nodes {
+		_, wasReachable := before[n.Id]
+		if _, ok := after[n.Id]; wasReachable && !ok {
+			remove = append(remove, n.Id)
+		}
+	}
+	if len(remove) > 0 {
+		ret.RemoveNodes(remove)
+	}
+	return ret
+}
+
+// reachableAlong returns the IDs of the nodes reachable from the nodes in
+// ids (included) through edges, walking each edge from the element to its
+// parts and dependencies. If ids is empty, it returns all the nodes with
+// edges.
+func reachableAlong(edges []*Edge, ids []string) map[string]struct{} {
+	ret := map[string]struct{}{}
+	if len(ids) == 0 {
+		for _, e := range edges {
+			ret[e.From] = struct{}{}
+			for _, id := range e.To {
+				ret[id] = struct{}{}
+			}
+		}
+		return ret
+	}
+
+	next := map[string][]string{}
+	for _, e := range edges {
+		for _, id := range e.To {
+			if e.Type.PointsToDependent() {
+				next[id] = append(next[id], e.From)
+			} else {
+				next[e.From] = append(next[e.From], id)
+			}
+		}
+	}
+
+	queue := append([]string{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := ret[id]; ok {
+			continue
+		}
+		ret[id] = struct{}{}
+		queue = append(queue, next[id]...)
+	}
+	return ret
+}
+
+// Subgraph returns the subgraph induced by the nodes in ids: a new nodelist
+// with those nodes and only the edges that connect nodes within the set.
+// Unlike a descendants query, the graph is not expanded transitively. The
+// root elements of the subgraph are the original roots found in the set.
+// IDs not found in the nodelist are ignored.
+func (nl *NodeList) Subgraph(ids []string) *NodeList {
+	ret := &NodeList{
+		Nodes:        []*Node{},
+		Edges:        []*Edge{},
+		RootElements: []string{},
+	}
+	if nl == nil {
+		return ret
+	}
+
+	idDict := map[string]struct{}{}
+	for _, id := range ids {
+		idDict[id] = struct{}{}
+	}
+
+	for _, n := range nl.Nodes {
+		if _, ok := idDict[n.Id]; ok {
+			ret.Nodes = append(ret.Nodes, n)
+		}
+	}
+
+	index := ret.indexNodes()
+	for _, e := range nl.Edges {
+		if _, ok := index[e.From]; !ok {
+			continue
+		}
+		newEdge := &Edge{Type: e.Type, From: e.From, To: []string{}, Label: e.Label}
+		for _, to := range e.To {
+			if _, ok := index[to]; ok {
+				newEdge.To = append(newEdge.To, to)
+			}
+		}
+		if len(newEdge.To) > 0 {
+			ret.Edges = append(ret.Edges, newEdge)
+		}
+	}
+
+	for _, id := range nl.RootElements {
+		if _, ok := index[id]; ok {
+			ret.RootElements = append(ret.RootElements, id)
+		}
+	}
+
+	return ret
+}
+
+// SubgraphByName returns a new nodelist with the nodes named name and
+// everything reachable from them. The matched nodes become the root
+// elements of the returned nodelist.
+func (nl *NodeList) SubgraphByName(name string) *NodeList {
+	roots := []string{}
+	for _, n := range nl.GetNodesByName(name) {
+		roots = append(roots, n.Id)
+	}
+
+	reachable := nl.reachableNodes(roots...)
+	ids := make([]string, 0, len(reachable))
+	for id := range reachable {
+		ids = append(ids, id)
+	}
+
+	ret := nl.Subgraph(ids)
+	ret.RootElements = roots
+	return ret
+}
+
 // reconnectOrphanNodes cleans the nodelist graph structure by reconnecting all
 // orphaned nodes to the top of the nodelist
 func (nl *NodeList) reconnectOrphanNodes() {
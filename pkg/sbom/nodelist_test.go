@@ -2,9 +2,14 @@ package sbom
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestCleanEdges(t *testing.T) {
@@ -93,12 +98,78 @@ func TestCleanEdges(t *testing.T) {
 				RootElements: []string{"node1"},
 			},
 		},
+		"Destinations added in reverse order are sorted": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1"}, {Id: "node2"}, {Id: "node3"}, {Id: "node4"},
+				},
+				Edges: []*Edge{
+					{Type: Edge_dependsOn, From: "node1", To: []string{"node4"}},
+					{Type: Edge_dependsOn, From: "node1", To: []string{"node3", "node2"}},
+				},
+				RootElements: []string{"node1"},
+			},
+			expected: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1"}, {Id: "node2"}, {Id: "node3"}, {Id: "node4"},
+				},
+				Edges: []*Edge{
+					{Type: Edge_dependsOn, From: "node1", To: []string{"node2", "node3", "node4"}},
+				},
+				RootElements: []string{"node1"},
+			},
+		},
 	} {
 		tc.sut.cleanEdges()
 		require.True(t, tc.sut.Equal(tc.expected), m)
+		// The destinations must be in the expected order, not just equal
+		require.Len(t, tc.sut.Edges, len(tc.expected.Edges), m)
+		for i := range tc.expected.Edges {
+			require.Equal(t, tc.expected.Edges[i].To, tc.sut.Edges[i].To, m)
+		}
 	}
 }
 
+func TestAddEdgeChecked(t *testing.T) {
+	newNodeList := func() *NodeList {
+		return &NodeList{Nodes: []*Node{{Id: "node1", Name: "node1"}}}
+	}
+
+	// Strict mode fails on dangling references and leaves the nodelist as is
+	nl := newNodeList()
+	err := nl.AddEdgeChecked(&Edge{Type: Edge_dependsOn, From: "node1", To: []string{"node2", "node3"}}, false)
+	require.ErrorIs(t, err, ErrDanglingEdge)
+	require.Contains(t, err.Error(), "node2, node3")
+	require.Len(t, nl.Nodes, 1)
+	require.Empty(t, nl.Edges)
+
+	require.ErrorIs(t, nl.AddEdgeChecked(&Edge{Type: Edge_dependsOn, From: "node0", To: []string{"node1"}}, false), ErrDanglingEdge)
+	require.Error(t, nl.AddEdgeChecked(nil, true))
+
+	// Edges between known nodes are added
+	nl.AddNode(&Node{Id: "node2"})
+	require.NoError(t, nl.AddEdgeChecked(&Edge{Type: Edge_dependsOn, From: "node1", To: []string{"node2"}}, false))
+	require.Len(t, nl.Edges, 1)
+
+	// Missing nodes are created as placeholders
+	nl = newNodeList()
+	require.NoError(t, nl.AddEdgeChecked(&Edge{Type: Edge_contains, From: "node0", To: []string{"node1", "node2"}}, true))
+	require.Len(t, nl.Edges, 1)
+	require.Len(t, nl.Nodes, 3)
+	for _, id := range []string{"node0", "node2"} {
+		n := nl.GetNodeByID(id)
+		require.NotNil(t, n, id)
+		require.Equal(t, Node_UNKNOWN, n.Type, id)
+	}
+	require.Equal(t, Node_PACKAGE, nl.GetNodeByID("node1").Type)
+
+	// The placeholder is filled when the node arrives
+	nl.AddNodes(&Node{Id: "node2", Name: "node2", Type: Node_FILE})
+	require.Len(t, nl.Nodes, 3)
+	require.Equal(t, Node_FILE, nl.GetNodeByID("node2").Type)
+	require.Equal(t, "node2", nl.GetNodeByID("node2").Name)
+}
+
 func TestRemoveNodes(t *testing.T) {
 	for _, tc := range []struct {
 		sut      *NodeList
@@ -197,6 +268,38 @@ func TestAdd(t *testing.T) {
 				},
 			},
 		},
+		// Edges with different labels are not merged
+		{
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "test1"},
+					{Id: "test2"},
+					{Id: "test3"},
+				},
+				Edges: []*Edge{
+					{From: "test1", Type: Edge_other, Label: "patches", To: []string{"test2"}},
+				},
+			},
+			prepare: func(n *NodeList) {
+				n.Add(&NodeList{
+					Edges: []*Edge{
+						{From: "test1", Type: Edge_other, Label: "signs", To: []string{"test3"}},
+						{From: "test1", Type: Edge_other, Label: "patches", To: []string{"test3"}},
+					},
+				})
+			},
+			expect: &NodeList{
+				Nodes: []*Node{
+					{Id: "test1"},
+					{Id: "test2"},
+					{Id: "test3"},
+				},
+				Edges: []*Edge{
+					{From: "test1", Type: Edge_other, Label: "patches", To: []string{"test2", "test3"}},
+					{From: "test1", Type: Edge_other, Label: "signs", To: []string{"test3"}},
+				},
+			},
+		},
 	} {
 		tc.prepare(tc.sut)
 		require.Equal(t, tc.sut, tc.expect)
@@ -407,6 +510,308 @@ func TestNodeListUnion(t *testing.T) {
 		newNodeList := tc.sut.Union(tc.isec)
 		require.True(t, tc.expect.Equal(newNodeList), title)
 	}
+
+	// Identical edges in both lists collapse into a single edge
+	nl1 := &NodeList{
+		Nodes: []*Node{{Id: "node1"}, {Id: "node2"}, {Id: "node3"}},
+		Edges: []*Edge{{Type: Edge_contains, From: "node1", To: []string{"node2"}}},
+	}
+	nl2 := &NodeList{
+		Nodes: []*Node{{Id: "node1"}, {Id: "node2"}, {Id: "node3"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "node1", To: []string{"node2"}},
+			{Type: Edge_contains, From: "node1", To: []string{"node3"}},
+		},
+	}
+	union := nl1.Union(nl2)
+	require.Len(t, union.Edges, 1)
+	require.Equal(t, Edge_contains, union.Edges[0].Type)
+	require.ElementsMatch(t, []string{"node2", "node3"}, union.Edges[0].To)
+
+	// The original lists are not modified
+	require.Equal(t, []string{"node2"}, nl1.Edges[0].To)
+	require.Len(t, nl2.Edges, 2)
+}
+
+func TestUnionWithOptions(t *testing.T) {
+	nl1 := &NodeList{
+		Nodes: []*Node{{Id: "a"}, {Id: "b"}, {Id: "c"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "a", To: []string{"b", "c"}},
+		},
+		RootElements: []string{"a"},
+	}
+	nl2 := &NodeList{
+		Nodes: []*Node{{Id: "a"}, {Id: "b"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "a", To: []string{"b"}},
+			{Type: Edge_describes, From: "a", To: []string{"b"}},
+		},
+		RootElements: []string{"a"},
+	}
+
+	// contains is stronger than dependsOn, describes is not ranked
+	precedence := func(t Edge_Type) int {
+		switch t {
+		case Edge_contains:
+			return 2
+		case Edge_dependsOn:
+			return 1
+		}
+		return 0
+	}
+
+	edges := func(nl *NodeList) []string {
+		ret := []string{}
+		for _, e := range nl.Edges {
+			ret = append(ret, e.flatString())
+		}
+		sort.Strings(ret)
+		return ret
+	}
+
+	// Without options both relationships are kept
+	require.Equal(t, []string{"a:contains:b", "a:dependsOn:b+c", "a:describes:b"}, edges(nl1.Union(nl2)))
+
+	res := nl1.UnionWithOptions(nl2, MergeOptions{EdgePrecedence: precedence})
+	require.Equal(t, []string{"a:contains:b", "a:dependsOn:c", "a:describes:b"}, edges(res))
+	require.Equal(t, []string{"b", "c"}, nl1.Edges[0].To)
+
+	nl1.AddWithOptions(nl2, MergeOptions{EdgePrecedence: precedence})
+	require.Equal(t, []string{"a:contains:b", "a:dependsOn:c", "a:describes:b"}, edges(nl1))
+	require.Equal(t, []string{"b"}, nl2.Edges[0].To)
+}
+
+func TestMergeSourceTag(t *testing.T) {
+	newLists := func() (*NodeList, *NodeList) {
+		return &NodeList{
+			Nodes:        []*Node{{Id: "a", Name: "a"}, {Id: "b", Name: "b"}},
+			Edges:        []*Edge{{Type: Edge_dependsOn, From: "a", To: []string{"b"}}},
+			RootElements: []string{"a"},
+		}, &NodeList{
+			Nodes:        []*Node{{Id: "b", Name: "b"}, {Id: "c", Name: "c"}},
+			Edges:        []*Edge{{Type: Edge_dependsOn, From: "b", To: []string{"c"}}},
+			RootElements: []string{"b"},
+		}
+	}
+	opts := MergeOptions{SourceTag: "scanner-2"}
+
+	check := func(t *testing.T, nl *NodeList) {
+		t.Helper()
+		require.Equal(t, "scanner-2", nl.GetNodeByID("c").Properties[MergeSourceProperty])
+		for _, id := range []string{"a", "b"} {
+			_, ok := nl.GetNodeByID(id).Properties[MergeSourceProperty]
+			require.False(t, ok, id)
+		}
+	}
+
+	nl1, nl2 := newLists()
+	check(t, nl1.UnionWithOptions(nl2, opts))
+	require.Nil(t, nl2.Nodes[1].Properties)
+
+	nl1, nl2 = newLists()
+	report, err := nl1.AddWithOptions(nl2, opts)
+	require.NoError(t, err)
+	check(t, nl1)
+	require.Nil(t, nl2.Nodes[1].Properties)
+	require.Equal(t, []string{"c"}, report.Added)
+	require.Equal(t, []string{"b"}, report.Collided)
+	require.Empty(t, report.Differed)
+	require.Len(t, nl1.Edges, 2)
+}
+
+func TestMergeHashConflicts(t *testing.T) {
+	newLists := func() (*NodeList, *NodeList) {
+		return &NodeList{
+			Nodes: []*Node{
+				{Id: "lib", Name: "lib", Hashes: map[string]string{"SHA256": "aaaa", "SHA1": "1111"}},
+				{Id: "app", Name: "app", Hashes: map[string]string{"SHA256": "cccc"}},
+			},
+		}, &NodeList{
+			Nodes: []*Node{
+				{Id: "lib", Name: "lib", Hashes: map[string]string{"sha256": "bbbb", "SHA1": "1111"}},
+				{Id: "app", Name: "app", Hashes: map[string]string{"SHA256": "CCCC", "SHA512": "dddd"}},
+			},
+		}
+	}
+	expected := []HashConflict{{ID: "lib", Algorithm: HashAlgorithm_SHA256, Existing: "aaaa", Incoming: "bbbb"}}
+
+	nl1, nl2 := newLists()
+	require.Equal(t, expected, nl1.HashConflicts(nl2))
+
+	// By default the conflict is recorded in the report
+	report, err := nl1.AddWithOptions(nl2, MergeOptions{})
+	require.NoError(t, err)
+	require.Equal(t, expected, report.HashConflicts)
+
+	// Or the merge fails without modifying the nodelist
+	nl1, nl2 = newLists()
+	_, err = nl1.AddWithOptions(nl2, MergeOptions{RejectHashConflicts: true})
+	require.ErrorIs(t, err, ErrHashConflict)
+	require.Len(t, nl1.GetNodeByID("app").Hashes, 1)
+
+	// Without conflicts the merge goes through
+	nl2.Nodes = nl2.Nodes[1:]
+	_, err = nl1.AddWithOptions(nl2, MergeOptions{RejectHashConflicts: true})
+	require.NoError(t, err)
+}
+
+func TestRepairEdges(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "a"}, {Id: "b"}, {Id: "c"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "a", To: []string{"b", "missing"}},
+			{Type: Edge_contains, From: "ghost", To: []string{"a"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"c", "b"}},
+			{Type: Edge_contains, From: "b", To: []string{"gone"}},
+		},
+	}
+
+	report := nl.RepairEdges()
+	require.False(t, report.Empty())
+	require.Len(t, report.DroppedEdges, 1)
+	require.Equal(t, "ghost", report.DroppedEdges[0].From)
+	require.Equal(t, []BrokenEdge{
+		{From: "a", Type: Edge_dependsOn, To: "missing"},
+		{From: "b", Type: Edge_contains, To: "gone"},
+	}, report.DroppedTargets)
+	require.Equal(t, 1, report.MergedEdges)
+	require.Equal(t, 1, report.DuplicateTargets)
+
+	require.Len(t, nl.Edges, 1)
+	require.Equal(t, []string{"b", "c"}, nl.Edges[0].To)
+
+	require.True(t, nl.RepairEdges().Empty())
+}
+
+func TestIndexByPurlCanonicalKeys(t *testing.T) {
+	purlNode := func(id, purl string) *Node {
+		return &Node{Id: id, Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): purl}}
+	}
+	nl := &NodeList{Nodes: []*Node{
+		purlNode("a", "pkg:deb/debian/curl@7.74.0?arch=amd64&distro=bullseye"),
+		purlNode("b", "pkg:deb/debian/curl@7.74.0?distro=bullseye&arch=amd64"),
+		purlNode("c", "pkg:deb/debian/curl@7.74.0?arch=arm64&distro=bullseye"),
+		{Id: "d"},
+	}}
+
+	index := nl.IndexByPurl()
+	require.Len(t, index, 2)
+	same := index["pkg:deb/debian/curl@7.74.0?arch=amd64&distro=bullseye"]
+	require.Len(t, same, 2)
+	require.Same(t, nl.Nodes[0], same[0])
+	require.Same(t, nl.Nodes[1], same[1])
+	require.Len(t, index["pkg:deb/debian/curl@7.74.0?arch=arm64&distro=bullseye"], 1)
+}
+
+func TestIndexByHashSharedDigest(t *testing.T) {
+	digest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	nl := &NodeList{Nodes: []*Node{
+		{Id: "a", Hashes: map[string]string{"SHA256": digest, "SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}},
+		{Id: "b"},
+		{Id: "c", Hashes: map[string]string{"SHA256": strings.ToUpper(digest)}},
+	}}
+
+	index := nl.IndexByHash()
+	require.Len(t, index, 2)
+	shared := index["SHA256:"+digest]
+	require.Len(t, shared, 2)
+	require.Same(t, nl.Nodes[0], shared[0])
+	require.Same(t, nl.Nodes[2], shared[1])
+	require.Len(t, index["SHA1:da39a3ee5e6b4b0d3255bfef95601890afd80709"], 1)
+
+	// Algorithm names are normalized
+	nl.Nodes[1].Hashes = map[string]string{"sha-256": digest}
+	require.Len(t, nl.IndexByHash()["SHA256:"+digest], 3)
+}
+
+func TestGetMatchingNodeWithOptions(t *testing.T) {
+	sha1 := "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"
+	sha256 := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	purl := func(p string) map[int32]string {
+		return map[int32]string{int32(SoftwareIdentifierType_PURL): p}
+	}
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "node1", Hashes: map[string]string{"sha1": sha1}},
+			{Id: "node2", Hashes: map[string]string{"sha1": sha1}},
+			{Id: "node3", Hashes: map[string]string{"sha1": sha1}, Identifiers: purl("pkg:npm/other@1.0.0")},
+			{Id: "node4", Hashes: map[string]string{"sha1": sha1, "sha-256": sha256}},
+			{
+				Id: "node5", Hashes: map[string]string{"sha1": sha1},
+				ExternalReferences: []*ExternalReference{
+					{ReferenceType: ExternalReference_DISTRIBUTION, Url: "https://example.com/lib.tar.gz"},
+				},
+			},
+		},
+	}
+	opts := MatchOptions{
+		StrongHashAlgorithms: []HashAlgorithm{HashAlgorithm_SHA256, HashAlgorithm_SHA384, HashAlgorithm_SHA512},
+	}
+
+	// Shared weak hashes are an error when all algorithms are trusted
+	test := &Node{Hashes: map[string]string{"sha1": sha1}, Identifiers: purl("pkg:npm/lib@1.0.0")}
+	_, err := nl.GetMatchingNodeWithOptions(test, MatchOptions{})
+	require.ErrorIs(t, err, ErrorMoreThanOneMatch)
+
+	// ... but only weak matches when sha1 is not trusted. node3 is not a
+	// candidate as its purl differs.
+	res, err := nl.GetMatchingNodeWithOptions(test, opts)
+	require.NoError(t, err)
+	require.Nil(t, res.Node)
+	ids := []string{}
+	for _, n := range res.WeakMatches {
+		ids = append(ids, n.Id)
+	}
+	require.Equal(t, []string{"node1", "node2", "node4", "node5"}, ids)
+
+	// A strong hash gives a confident match
+	res, err = nl.GetMatchingNodeWithOptions(&Node{Hashes: map[string]string{"sha1": sha1, "sha-256": sha256}}, opts)
+	require.NoError(t, err)
+	require.NotNil(t, res.Node)
+	require.Equal(t, "node4", res.Node.Id)
+	require.Empty(t, res.WeakMatches)
+
+	// Identifiers still match confidently
+	res, err = nl.GetMatchingNodeWithOptions(&Node{Hashes: map[string]string{"sha1": sha1}, Identifiers: purl("pkg:npm/other@1.0.0")}, opts)
+	require.NoError(t, err)
+	require.Equal(t, "node3", res.Node.Id)
+
+	// ... and so do the distribution external references
+	res, err = nl.GetMatchingNodeWithOptions(&Node{
+		Hashes: map[string]string{"sha1": sha1},
+		ExternalReferences: []*ExternalReference{
+			{ReferenceType: ExternalReference_DISTRIBUTION, Url: "https://example.com/lib.tar.gz"},
+		},
+	}, opts)
+	require.NoError(t, err)
+	require.NotNil(t, res.Node)
+	require.Equal(t, "node5", res.Node.Id)
+}
+
+func TestMakeRoot(t *testing.T) {
+	nl := &NodeList{
+		Nodes:        []*Node{{Id: "a"}, {Id: "b"}, {Id: "c"}},
+		RootElements: []string{"c", "a", "c"},
+	}
+
+	require.True(t, nl.IsRoot("a"))
+	require.False(t, nl.IsRoot("b"))
+
+	require.NoError(t, nl.MakeRoot("b"))
+	require.Equal(t, []string{"a", "b", "c"}, nl.RootElements)
+	require.NoError(t, nl.MakeRoot("b"))
+	require.Equal(t, []string{"a", "b", "c"}, nl.RootElements)
+	require.Error(t, nl.MakeRoot("missing"))
+
+	nl.UnmakeRoot("c")
+	require.Equal(t, []string{"a", "b"}, nl.RootElements)
+	require.False(t, nl.IsRoot("c"))
+	require.NotNil(t, nl.GetNodeByID("c"))
+
+	// Removing a node that is not a root is a noop
+	nl.UnmakeRoot("c")
+	require.Equal(t, []string{"a", "b"}, nl.RootElements)
 }
 
 func TestGetNodesByName(t *testing.T) {
@@ -487,6 +892,188 @@ func TestGetNodeByID(t *testing.T) {
 	}
 }
 
+func TestRelateNodeListAtID(t *testing.T) {
+	nl := &NodeList{
+		Nodes:        []*Node{{Id: "app"}},
+		RootElements: []string{"app"},
+	}
+	nl2 := &NodeList{
+		Nodes: []*Node{{Id: "lib"}, {Id: "libdep"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "lib", To: []string{"libdep"}},
+		},
+		RootElements: []string{"lib"},
+	}
+
+	require.Error(t, nl.RelateNodeListAtID(nl2, "missing", Edge_contains))
+	require.NoError(t, nl.RelateNodeListAtID(nl2, "app", Edge_contains))
+	require.Len(t, nl.Nodes, 3)
+	require.Equal(t, []string{"app"}, nl.RootElements)
+	require.Equal(t, []string{"lib"}, nl.GetEdgeByType("app", Edge_contains).To)
+
+	// The edges of the related nodelist are kept
+	e := nl.GetEdgeByType("lib", Edge_dependsOn)
+	require.NotNil(t, e)
+	require.Equal(t, []string{"libdep"}, e.To)
+}
+
+func TestPruneUnreachable(t *testing.T) {
+	// root -> a -> b -> c, then the a -> b link is removed
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "root"}, {Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "root", To: []string{"a", "d"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			{Type: Edge_dependsOn, From: "b", To: []string{"c"}},
+		},
+		RootElements: []string{"root"},
+	}
+	require.Empty(t, nl.PruneUnreachableDryRun())
+
+	nl.Edges[1].To = []string{}
+	require.Equal(t, []string{"b", "c"}, nl.PruneUnreachableDryRun())
+	require.Len(t, nl.Nodes, 5)
+
+	// Only b is an orphan, c is still the target of an edge
+	orphans := nl.Orphans()
+	require.Len(t, orphans, 1)
+	require.Equal(t, "b", orphans[0].Id)
+
+	nl.PruneUnreachable()
+	ids := []string{}
+	for _, n := range nl.Nodes {
+		ids = append(ids, n.Id)
+	}
+	require.Equal(t, []string{"root", "a", "d"}, ids)
+	require.Len(t, nl.Edges, 1)
+	require.Equal(t, "root", nl.Edges[0].From)
+
+	// Without roots nothing is pruned
+	nl.RootElements = []string{}
+	require.Empty(t, nl.PruneUnreachableDryRun())
+	nl.Prune()
+	require.Len(t, nl.Nodes, 3)
+}
+
+func TestGetNodesReleasedBefore(t *testing.T) {
+	date := func(s string) *timestamppb.Timestamp {
+		d, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return timestamppb.New(d)
+	}
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "node1", ReleaseDate: date("2020-01-01T00:00:00Z")},
+			{Id: "node2", ReleaseDate: date("2023-06-15T12:00:00Z")},
+			{Id: "node3"},
+			{Id: "node4", ReleaseDate: date("2021-12-31T23:59:59Z")},
+		},
+	}
+	for _, tc := range []struct {
+		before   string
+		expected []string
+	}{
+		{"2019-01-01T00:00:00Z", []string{}},
+		{"2020-01-01T00:00:00Z", []string{}},
+		{"2022-01-01T00:00:00Z", []string{"node1", "node4"}},
+		{"2030-01-01T00:00:00Z", []string{"node1", "node2", "node4"}},
+	} {
+		ids := []string{}
+		for _, n := range nl.GetNodesReleasedBefore(date(tc.before).AsTime()) {
+			ids = append(ids, n.Id)
+		}
+		require.Equal(t, tc.expected, ids, tc.before)
+	}
+}
+
+func TestGetNodesBySupplier(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "node1", Suppliers: []*Person{{Name: "Chainguard", IsOrg: true}}},
+			{Id: "node2", Suppliers: []*Person{{Name: "Debian"}, {Name: "Chainguard"}}},
+			{Id: "node3", Originators: []*Person{{Name: "Chainguard"}}},
+			{Id: "node4"},
+		},
+	}
+	for _, tc := range []struct {
+		name     string
+		expected []string
+	}{
+		{"Chainguard", []string{"node1", "node2"}},
+		{"Debian", []string{"node2"}},
+		{"Nobody", []string{}},
+	} {
+		ids := []string{}
+		for _, n := range nl.GetNodesBySupplier(tc.name) {
+			ids = append(ids, n.Id)
+		}
+		require.Equal(t, tc.expected, ids, tc.name)
+	}
+}
+
+func TestGetNodesByPurlType(t *testing.T) {
+	purl := func(p string) map[int32]string {
+		return map[int32]string{int32(SoftwareIdentifierType_PURL): p}
+	}
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "node1", Identifiers: purl("pkg:npm/%40angular/core@16.1.0")},
+			{Id: "node2", Identifiers: purl("pkg:deb/debian/openssl@3.0.9-1")},
+			{Id: "node3", Identifiers: purl("pkg:/deb/debian/curl@7.88.1-10")},
+			{Id: "node4", Identifiers: purl("pkg:apk/wolfi/busybox@1.36.1-r0")},
+			{Id: "node5", Identifiers: purl("pkg:NPM/left-pad@1.3.0")},
+			{Id: "node6"},
+		},
+	}
+	for _, tc := range []struct {
+		purlType string
+		expected []string
+	}{
+		{"npm", []string{"node1", "node5"}},
+		{"DEB", []string{"node2", "node3"}},
+		{"apk", []string{"node4"}},
+		{"rpm", []string{}},
+		{"", []string{}},
+	} {
+		ids := []string{}
+		for _, n := range nl.GetNodesByPurlType(tc.purlType).Nodes {
+			ids = append(ids, n.Id)
+		}
+		require.Equal(t, tc.expected, ids, tc.purlType)
+	}
+}
+
+func TestGetNodesByLicense(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "node1", Licenses: []string{"GPL-2.0-only OR MIT"}},
+			{Id: "node2", Licenses: []string{"Apache-2.0"}, LicenseConcluded: "MIT"},
+			{Id: "node3", LicenseConcluded: "GPL-2.0-or-later WITH Classpath-exception-2.0"},
+			{Id: "node4", Licenses: []string{"NOASSERTION"}},
+			{Id: "node5", Licenses: []string{"(BSD-3-Clause AND ISC) OR Apache-2.0"}},
+		},
+	}
+	for _, tc := range []struct {
+		id       string
+		expected []string
+	}{
+		{"MIT", []string{"node1", "node2"}},
+		{"mit", []string{"node1", "node2"}},
+		{"GPL-2.0-only", []string{"node1"}},
+		{"GPL-2.0-or-later", []string{"node3"}},
+		{"Classpath-exception-2.0", []string{}},
+		{"Apache-2.0", []string{"node2", "node5"}},
+		{"ISC", []string{"node5"}},
+		{"NOASSERTION", []string{}},
+	} {
+		ids := []string{}
+		for _, n := range nl.GetNodesByLicense(tc.id) {
+			ids = append(ids, n.Id)
+		}
+		require.Equal(t, tc.expected, ids, tc.id)
+	}
+}
+
 func TestGetNodesByIdentifier(t *testing.T) {
 	for _, tc := range []struct {
 		sut      *NodeList
@@ -633,10 +1220,80 @@ func TestEqual(t *testing.T) {
 				sut2.Nodes[1].FileName = "package.tar"
 			},
 		},
+		"edge destinations in different order": {
+			sut1:     getTestNodeList(),
+			sut2:     getTestNodeList(),
+			shouldEq: true,
+			prepare: func(sut1 *NodeList, sut2 *NodeList) {
+				sut1.Edges[0].To = []string{"a", "b", "c"}
+				sut2.Edges[0].To = []string{"c", "a", "b"}
+				sut1.Nodes = append(sut1.Nodes, &Node{Id: "a"}, &Node{Id: "b"}, &Node{Id: "c"})
+				sut2.Nodes = append(sut2.Nodes, &Node{Id: "c"}, &Node{Id: "b"}, &Node{Id: "a"})
+				sut1.RootElements = []string{"b", "a"}
+				sut2.RootElements = []string{"b", "a"}
+			},
+		},
+		"modify a node hash": {
+			sut1:     getTestNodeList(),
+			sut2:     getTestNodeList(),
+			shouldEq: false,
+			prepare: func(sut1 *NodeList, sut2 *NodeList) {
+				sut1.Nodes[0].Hashes = map[string]string{"SHA1": "aaa", "SHA256": "bbb"}
+				sut2.Nodes[0].Hashes = map[string]string{"SHA1": "aaa", "SHA256": "ccc"}
+			},
+		},
 	} {
 		tc.prepare(tc.sut1, tc.sut2)
+		roots := append([]string{}, tc.sut2.RootElements...)
 		res := tc.sut1.Equal(tc.sut2)
 		require.Equal(t, tc.shouldEq, res, msg)
+
+		// Comparing must not modify the nodelists
+		require.Equal(t, roots, tc.sut2.RootElements, msg)
+	}
+}
+
+func TestEqualShuffled(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "a", Name: "a"}, {Id: "b", Name: "b"}, {Id: "c", Name: "c"},
+			{Id: "d", Name: "d"}, {Id: "e", Name: "e"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "a", To: []string{"b", "c"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"d"}},
+			{Type: Edge_dependsOn, From: "b", To: []string{"d", "e"}},
+			{Type: Edge_contains, From: "c", To: []string{"e"}},
+		},
+		RootElements: []string{"a", "e"},
+	}
+
+	rnd := rand.New(rand.NewSource(1)) //nolint:gosec
+	for i := 0; i < 20; i++ {
+		nl2 := &NodeList{}
+		for _, n := range nl.Nodes {
+			nl2.Nodes = append(nl2.Nodes, n.Copy())
+		}
+		for _, e := range nl.Edges {
+			e2 := e.Copy()
+			e2.To = append([]string{}, e.To...)
+			rnd.Shuffle(len(e2.To), func(i, j int) { e2.To[i], e2.To[j] = e2.To[j], e2.To[i] })
+			nl2.Edges = append(nl2.Edges, e2)
+		}
+		nl2.RootElements = append(nl2.RootElements, nl.RootElements...)
+
+		rnd.Shuffle(len(nl2.Nodes), func(i, j int) { nl2.Nodes[i], nl2.Nodes[j] = nl2.Nodes[j], nl2.Nodes[i] })
+		rnd.Shuffle(len(nl2.Edges), func(i, j int) { nl2.Edges[i], nl2.Edges[j] = nl2.Edges[j], nl2.Edges[i] })
+		rnd.Shuffle(len(nl2.RootElements), func(i, j int) {
+			nl2.RootElements[i], nl2.RootElements[j] = nl2.RootElements[j], nl2.RootElements[i]
+		})
+
+		require.True(t, nl.Equal(nl2))
+		require.True(t, nl2.Equal(nl))
+
+		// Changing a single node breaks the equality
+		nl2.Nodes[0].Name = "changed"
+		require.False(t, nl.Equal(nl2))
 	}
 }
 
@@ -711,6 +1368,34 @@ func TestIndexByHash(t *testing.T) {
 	}
 }
 
+func TestGetNodesByHash(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "nginx-amd64", Hashes: map[string]string{
+				"SHA1":   "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1",
+				"SHA256": "e3fc9093ffd6eb531055f8f3bde275e7e9e8ab1884589c195d5f78d0a9b3d2b3",
+			}},
+			{Id: "nginx-arm64", Hashes: map[string]string{
+				"sha256": "E3FC9093FFD6EB531055F8F3BDE275E7E9E8AB1884589C195D5F78D0A9B3D2B3",
+			}},
+			{Id: "other", Hashes: map[string]string{
+				"SHA256": "c71d239df91726fc519c6eb72d318ec65820627232b2f796219e87dcf35d0ab4",
+			}},
+		},
+	}
+
+	for _, algo := range []string{"SHA256", "sha256", "SHA-256", "sha-256"} {
+		res := nl.GetNodesByHash(algo, "e3fc9093ffd6eb531055f8f3bde275e7e9e8ab1884589c195d5f78d0a9b3d2b3")
+		require.Len(t, res, 2, algo)
+		require.Equal(t, "nginx-amd64", res[0].Id)
+		require.Equal(t, "nginx-arm64", res[1].Id)
+	}
+
+	require.Len(t, nl.GetNodesByHash("sha1", "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"), 1)
+	require.Empty(t, nl.GetNodesByHash("sha512", "e3fc9093ffd6eb531055f8f3bde275e7e9e8ab1884589c195d5f78d0a9b3d2b3"))
+	require.Empty(t, nl.GetNodesByHash("sha256", ""))
+}
+
 func TestIndexByPurl(t *testing.T) {
 	for label, tc := range map[string]struct {
 		sut            *NodeList
@@ -774,6 +1459,23 @@ func TestIndexByPurl(t *testing.T) {
 			expectedLength: 1,
 			mustEqual:      true,
 		},
+		"2 nodes, equivalent purls": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{
+						Id: "nginx-arm64", Name: "nginx",
+						Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:apk/wolfi/glibc@2.38-r1?arch=arm64&distro=wolfi"},
+					},
+					{
+						Id: "nginx-amd64", Name: "nginx",
+						Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:apk/wolfi/glibc@2.38-r1?distro=wolfi&arch=arm64"},
+					},
+				},
+			},
+			expected:       purlIndex{},
+			expectedLength: 1,
+			mustEqual:      true,
+		},
 	} {
 		res := tc.sut.indexNodesByPurl()
 		require.Equal(t, tc.expectedLength, len(res), label)
@@ -781,18 +1483,75 @@ func TestIndexByPurl(t *testing.T) {
 	}
 }
 
-func TestGetMatchingNode(t *testing.T) {
+func TestIndexByCPE(t *testing.T) {
+	cpeID := int32(SoftwareIdentifierType_CPE23)
 	for label, tc := range map[string]struct {
-		sut         *NodeList
-		node        *Node
-		exptectedId string
-		shouldNil   bool
-		shouldError bool
+		sut            *NodeList
+		expectedLength int
 	}{
-		"single hash": {
+		"1 node, no cpe": {
 			sut: &NodeList{
-				Nodes: []*Node{
-					{Id: "node1", Hashes: map[string]string{"sha1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}},
+				Nodes: []*Node{{Id: "nginx-arm64", Name: "nginx"}},
+			},
+			expectedLength: 0,
+		},
+		"1 node, one cpe": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "nginx-arm64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:f5:nginx:1.25.1:*:*:*:*:*:*:*"}},
+				},
+			},
+			expectedLength: 1,
+		},
+		"2 nodes, two cpes": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "nginx-arm64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:f5:nginx:1.25.1:*:*:*:*:*:*:*"}},
+					{Id: "nginx-amd64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:f5:nginx:1.25.2:*:*:*:*:*:*:*"}},
+				},
+			},
+			expectedLength: 2,
+		},
+		"2 nodes, shared cpe": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "nginx-arm64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:f5:nginx:1.25.1:*:*:*:*:*:*:*"}},
+					{Id: "nginx-amd64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:f5:nginx:1.25.1:*:*:*:*:*:*:*"}},
+				},
+			},
+			expectedLength: 1,
+		},
+		"2 nodes, shared cpe after normalization": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "nginx-arm64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:F5:NGINX:1.25.1"}},
+					{Id: "nginx-amd64", Name: "nginx", Identifiers: map[int32]string{cpeID: "cpe:2.3:a:f5:nginx:1.25.1:*:*:*:*:*:*:*"}},
+				},
+			},
+			expectedLength: 1,
+		},
+	} {
+		res := tc.sut.indexNodesByCPE()
+		require.Equal(t, tc.expectedLength, len(res), label)
+		for cpe, nodes := range res {
+			require.Equal(t, normalizeCPE23(cpe), cpe, label)
+			require.Len(t, tc.sut.GetNodesByIdentifier("cpe23", cpe), len(nodes), label)
+		}
+	}
+}
+
+func TestGetMatchingNode(t *testing.T) {
+	for label, tc := range map[string]struct {
+		sut         *NodeList
+		node        *Node
+		exptectedId string
+		shouldNil   bool
+		shouldError bool
+	}{
+		"single hash": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1", Hashes: map[string]string{"sha1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}},
 					{Id: "node2", Hashes: map[string]string{"sha1": "4c219efaf4d39295971409f796301a89a304cee6"}},
 				},
 			},
@@ -909,6 +1668,56 @@ func TestGetMatchingNode(t *testing.T) {
 			},
 			exptectedId: "node2",
 		},
+		"match by cpe": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1", Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:4.0.1:*:*:*:*:*:*:*"}},
+					{Id: "node2", Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:5.0.0:*:*:*:*:*:*:*"}},
+				},
+			},
+			node:        &Node{Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:4.0.1:*:*:*:*:*:*:*"}},
+			exptectedId: "node1",
+		},
+		"cpe when purl does not match": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1", Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:4.0.1:*:*:*:*:*:*:*"}},
+				},
+			},
+			node: &Node{Identifiers: map[int32]string{
+				int32(SoftwareIdentifierType_PURL):  "pkg:apk/wolfi/bash@4.0.1",
+				int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:4.0.1:*:*:*:*:*:*:*",
+			}},
+			exptectedId: "node1",
+		},
+		"conflicting purl prevents cpe match": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1", Identifiers: map[int32]string{
+						int32(SoftwareIdentifierType_PURL):  "pkg:deb/debian/bash@4.0.1",
+						int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:4.0.1:*:*:*:*:*:*:*",
+					}},
+				},
+			},
+			node: &Node{Identifiers: map[int32]string{
+				int32(SoftwareIdentifierType_PURL):  "pkg:apk/wolfi/bash@4.0.1",
+				int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:gnu:bash:4.0.1:*:*:*:*:*:*:*",
+			}},
+			shouldNil: true,
+		},
+		"multiple hash matches disambiguated by cpe": {
+			sut: &NodeList{
+				Nodes: []*Node{
+					{Id: "node1", Hashes: map[string]string{"sha1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}, Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE22): "cpe:/a:gnu:bash:4.0.1"}},
+					{Id: "node2", Hashes: map[string]string{"sha1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}, Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE22): "cpe:/a:gnu:bash:5.0.0"}},
+				},
+			},
+			node: &Node{
+				Hashes:      map[string]string{"sha1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"},
+				Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE22): "cpe:/a:gnu:bash:5.0.0"},
+			},
+			exptectedId: "node2",
+		},
 		/* this one needs to be implemented
 		"rearranged purls should match": {
 			sut: &NodeList{
@@ -952,3 +1761,699 @@ func TestGetMatchingNode(t *testing.T) {
 		require.Equal(t, tc.exptectedId, res.Id, label)
 	}
 }
+
+func TestGetMatchingNodeByExternalReference(t *testing.T) {
+	distribution := func(url string) []*ExternalReference {
+		return []*ExternalReference{{ReferenceType: ExternalReference_DISTRIBUTION, Url: url}}
+	}
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "a", ExternalReferences: distribution("https://example.com/a.tar.gz")},
+			{
+				Id:                 "b",
+				ExternalReferences: distribution("https://example.com/b.tar.gz"),
+				Identifiers:        map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:generic/b@1.0"},
+			},
+			{Id: "c", ExternalReferences: distribution("https://example.com/c.tar.gz")},
+			{Id: "c2", ExternalReferences: distribution("https://example.com/c.tar.gz")},
+			// Older documents only have the free form type
+			{Id: "d", ExternalReferences: []*ExternalReference{{Type: "distribution", Url: "https://example.com/d.tar.gz"}}},
+			{Id: "e", ExternalReferences: []*ExternalReference{{ReferenceType: ExternalReference_VCS, Url: "https://example.com/e.git"}}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		test      *Node
+		expected  string
+		shouldErr bool
+	}{
+		{"distribution", &Node{ExternalReferences: distribution("https://example.com/a.tar.gz")}, "a", false},
+		{"legacy type", &Node{ExternalReferences: distribution("https://example.com/d.tar.gz")}, "d", false},
+		{
+			"conflicting purl",
+			&Node{
+				ExternalReferences: distribution("https://example.com/b.tar.gz"),
+				Identifiers:        map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:generic/b@2.0"},
+			},
+			"", false,
+		},
+		{"ambiguous", &Node{ExternalReferences: distribution("https://example.com/c.tar.gz")}, "", true},
+		{
+			"vcs is not used to match",
+			&Node{ExternalReferences: []*ExternalReference{{ReferenceType: ExternalReference_VCS, Url: "https://example.com/e.git"}}},
+			"", false,
+		},
+	} {
+		n, err := nl.GetMatchingNode(tc.test)
+		if tc.shouldErr {
+			require.ErrorIs(t, err, ErrorMoreThanOneMatch, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		if tc.expected == "" {
+			require.Nil(t, n, tc.name)
+			continue
+		}
+		require.NotNil(t, n, tc.name)
+		require.Equal(t, tc.expected, n.Id, tc.name)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "c"}, {Id: "a"}, {Id: "d"}, {Id: "b"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "b", To: []string{"d", "c"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"d", "b"}},
+			{Type: Edge_contains, From: "a", To: []string{"c"}},
+		},
+		RootElements: []string{"b", "a"},
+	}
+	original := &NodeList{}
+	original.Add(nl)
+
+	// order returns the node IDs and edges of the nodelist as strings
+	order := func(nl *NodeList) ([]string, []string) {
+		nodes := []string{}
+		for _, n := range nl.Nodes {
+			nodes = append(nodes, n.Id)
+		}
+		edges := []string{}
+		for _, e := range nl.Edges {
+			edges = append(edges, fmt.Sprintf("%s %s %v", e.From, e.Type, e.To))
+		}
+		return nodes, edges
+	}
+
+	for i := 0; i < 2; i++ {
+		nl.Canonicalize()
+		require.True(t, original.Equal(nl))
+
+		nodes, edges := order(nl)
+		require.Equal(t, []string{"a", "b", "c", "d"}, nodes)
+		require.Equal(t, []string{
+			"a contains [c]",
+			"a dependsOn [b d]",
+			"b dependsOn [c d]",
+		}, edges)
+		require.Equal(t, []string{"a", "b"}, nl.RootElements)
+	}
+}
+
+func TestNodeListSort(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "b"}, {Id: "a"}},
+		Edges: []*Edge{
+			{Type: Edge_other, From: "a", To: []string{"b"}, Label: "uses"},
+			{Type: Edge_other, From: "a", To: []string{"b"}, Label: "patches"},
+			{Type: Edge_dependsOn, From: "b", To: []string{"a"}},
+		},
+		RootElements: []string{"b", "a"},
+	}
+
+	// order returns the node IDs and edges of the nodelist as strings
+	order := func(nl *NodeList) ([]string, []string) {
+		nodes := []string{}
+		for _, n := range nl.Nodes {
+			nodes = append(nodes, n.Id)
+		}
+		edges := []string{}
+		for _, e := range nl.Edges {
+			edges = append(edges, e.flatString())
+		}
+		return nodes, edges
+	}
+
+	nl.Sort()
+	nodes, edges := order(nl)
+	nl.Sort()
+
+	require.Equal(t, "a", nl.Nodes[0].Id)
+	require.Equal(t, "patches", nl.Edges[0].Label)
+	require.Equal(t, "uses", nl.Edges[1].Label)
+	require.Equal(t, "b", nl.Edges[2].From)
+	// Sort does not touch the root elements
+	require.Equal(t, []string{"b", "a"}, nl.RootElements)
+
+	// Sorting again does not change the order
+	nodes2, edges2 := order(nl)
+	require.Equal(t, nodes, nodes2)
+	require.Equal(t, edges, edges2)
+}
+
+func TestRenameNode(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "node1"}, {Id: "node2"}, {Id: "node3"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "node1", To: []string{"node2"}},
+			{Type: Edge_dependsOn, From: "node2", To: []string{"node3"}},
+		},
+		RootElements: []string{"node1"},
+	}
+
+	require.Error(t, nl.RenameNode("node4", "node5"))
+	require.Error(t, nl.RenameNode("node1", "node2"))
+	require.NoError(t, nl.RenameNode("node2", "renamed"))
+	require.True(t, nl.Equal(&NodeList{
+		Nodes: []*Node{{Id: "node1"}, {Id: "renamed"}, {Id: "node3"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "node1", To: []string{"renamed"}},
+			{Type: Edge_dependsOn, From: "renamed", To: []string{"node3"}},
+		},
+		RootElements: []string{"node1"},
+	}))
+}
+
+func TestReIDByContent(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "root", Name: "app", Version: "1.0.0"},
+			{Id: "bash-1", Name: "bash", Version: "4.0.1", Hashes: map[string]string{"SHA1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}},
+			{Id: "bash-2", Name: "bash", Version: "4.0.1", Hashes: map[string]string{"SHA1": "0b13c24e584ef7075f3d4fd3a9f8872c9fffa1b1"}, Description: "A shell"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "root", To: []string{"bash-1", "bash-2"}},
+		},
+		RootElements: []string{"root"},
+	}
+
+	require.NoError(t, nl.ReIDByContent())
+	require.Len(t, nl.Nodes, 2)
+	rootID := nl.Nodes[0].ContentID()
+	bashID := nl.Nodes[1].ContentID()
+	require.Equal(t, rootID, nl.Nodes[0].Id)
+	require.Equal(t, bashID, nl.Nodes[1].Id)
+	require.Equal(t, "A shell", nl.Nodes[1].Description)
+	require.Equal(t, []string{rootID}, nl.RootElements)
+	require.Len(t, nl.Edges, 1)
+	require.Equal(t, []string{bashID}, nl.Edges[0].To)
+
+	// Running it again must not change anything
+	require.NoError(t, nl.ReIDByContent())
+	require.Equal(t, rootID, nl.Nodes[0].Id)
+	require.Equal(t, bashID, nl.Nodes[1].Id)
+
+	// A node taking the content ID of another is renamed too
+	nl.Nodes[1].Version = "5.0"
+	nl.AddNode(&Node{Id: nl.Nodes[1].ContentID(), Name: "other"})
+	require.NoError(t, nl.ReIDByContent())
+	require.Len(t, nl.Nodes, 3)
+	for _, n := range nl.Nodes {
+		require.Equal(t, n.ContentID(), n.Id)
+	}
+	require.Len(t, nl.Edges, 1)
+	require.Equal(t, []string{nl.Nodes[1].Id}, nl.Edges[0].To)
+
+	// Duplicate IDs with different content cannot be rewired
+	nl = &NodeList{Nodes: []*Node{{Id: "a", Name: "a"}, {Id: "a", Name: "b"}}}
+	require.Error(t, nl.ReIDByContent())
+	require.Equal(t, "a", nl.Nodes[0].Id)
+}
+
+func TestSubgraph(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "root"}, {Id: "a"}, {Id: "b"}, {Id: "c"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "root", To: []string{"a", "b"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"b", "c"}},
+			{Type: Edge_dependsOn, From: "c", To: []string{"b"}},
+		},
+		RootElements: []string{"root"},
+	}
+
+	sub := nl.Subgraph([]string{"a", "b", "missing"})
+	ids := []string{}
+	for _, n := range sub.Nodes {
+		ids = append(ids, n.Id)
+	}
+	require.Equal(t, []string{"a", "b"}, ids)
+	require.Len(t, sub.RootElements, 0)
+
+	// Only the a -> b edge is inside the set, edges crossing the boundary are dropped
+	require.Len(t, sub.Edges, 1)
+	require.Equal(t, "a", sub.Edges[0].From)
+	require.Equal(t, []string{"b"}, sub.Edges[0].To)
+
+	// The original nodelist is not modified
+	require.Len(t, nl.Edges[1].To, 2)
+
+	sub = nl.Subgraph([]string{"root", "a"})
+	require.Equal(t, []string{"root"}, sub.RootElements)
+	require.Len(t, sub.Edges, 1)
+	require.Equal(t, []string{"a"}, sub.Edges[0].To)
+}
+
+func TestSubgraphByName(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "root", Name: "app"}, {Id: "a", Name: "log4j"}, {Id: "b", Name: "commons"},
+			{Id: "c", Name: "other"}, {Id: "d", Name: "log4j"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "root", To: []string{"a", "c", "d"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"b"}},
+			{Type: Edge_dependsOn, From: "c", To: []string{"b"}},
+		},
+		RootElements: []string{"root"},
+	}
+
+	sub := nl.SubgraphByName("log4j")
+	ids := []string{}
+	for _, n := range sub.Nodes {
+		ids = append(ids, n.Id)
+	}
+	require.Equal(t, []string{"a", "b", "d"}, ids)
+	require.Equal(t, []string{"a", "d"}, sub.RootElements)
+	require.Len(t, sub.Edges, 1)
+	require.Equal(t, "a", sub.Edges[0].From)
+
+	sub = nl.SubgraphByName("missing")
+	require.Empty(t, sub.Nodes)
+	require.Empty(t, sub.RootElements)
+}
+
+func TestMergeNodes(t *testing.T) {
+	newNodeList := func() *NodeList {
+		return &NodeList{
+			Nodes: []*Node{
+				{Id: "app", Name: "app"},
+				{
+					Id: "curl1", Name: "curl", Version: "8.1.1",
+					Hashes:      map[string]string{"SHA256": "aaa"},
+					Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:apk/wolfi/curl@8.1.1"},
+				},
+				{
+					Id: "curl2", Name: "curl", Copyright: "Daniel",
+					Hashes:      map[string]string{"SHA1": "bbb"},
+					Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:haxx:curl:8.1.1:*:*:*:*:*:*:*"},
+				},
+				{Id: "openssl", Name: "openssl"},
+			},
+			Edges: []*Edge{
+				{Type: Edge_dependsOn, From: "app", To: []string{"curl1", "curl2"}},
+				{Type: Edge_dependsOn, From: "curl2", To: []string{"openssl", "curl1"}},
+			},
+			RootElements: []string{"app", "curl2"},
+		}
+	}
+
+	nl := newNodeList()
+	require.Error(t, nl.MergeNodes("curl1", "nonexistent"))
+	require.Len(t, nl.Nodes, 4)
+
+	require.NoError(t, nl.MergeNodes("curl1", "curl2"))
+	require.Len(t, nl.Nodes, 3)
+	require.Nil(t, nl.GetNodeByID("curl2"))
+
+	curl := nl.GetNodeByID("curl1")
+	require.Equal(t, "Daniel", curl.Copyright)
+	require.Equal(t, map[string]string{"SHA256": "aaa", "SHA1": "bbb"}, curl.Hashes)
+	require.Len(t, curl.Identifiers, 2)
+
+	// Edges and roots are redirected and edges between the merged nodes dropped
+	require.Equal(t, []string{"app", "curl1"}, nl.RootElements)
+	require.Equal(t, []string{"curl1"}, nl.GetEdgeByType("app", Edge_dependsOn).To)
+	require.Equal(t, []string{"openssl"}, nl.GetEdgeByType("curl1", Edge_dependsOn).To)
+
+	// Edges that only pointed to merged nodes are removed
+	nl = newNodeList()
+	nl.Edges = append(nl.Edges, &Edge{Type: Edge_contains, From: "curl1", To: []string{"curl2"}})
+	require.NoError(t, nl.MergeNodes("curl1", "curl2"))
+	require.Len(t, nl.Edges, 2)
+	require.Nil(t, nl.GetEdgeByType("curl1", Edge_contains))
+	for _, e := range nl.Edges {
+		require.NotEmpty(t, e.To)
+	}
+
+	// Conflicts keep the kept node's values and are reported
+	nl = newNodeList()
+	nl.Nodes[2].Version = "8.0.0"
+	nl.Nodes[2].Hashes["SHA256"] = "ccc"
+	err := nl.MergeNodes("curl1", "curl2")
+	conflictErr := &MergeConflictError{}
+	require.ErrorAs(t, err, &conflictErr)
+	require.Equal(t, []string{"version", "hashes[SHA256]"}, conflictErr.Conflicts)
+	require.Equal(t, "8.1.1", nl.GetNodeByID("curl1").Version)
+	require.Equal(t, "aaa", nl.GetNodeByID("curl1").Hashes["SHA256"])
+	require.Len(t, nl.Nodes, 3)
+}
+
+func TestAddWithReport(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app"},
+			{Id: "curl", Name: "curl", Version: "8.1.1"},
+		},
+		RootElements: []string{"app"},
+	}
+	nl2 := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app"},
+			{Id: "curl", Name: "curl", Version: "8.1.1", Copyright: "Daniel"},
+			{Id: "openssl", Name: "openssl"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "curl", To: []string{"openssl"}},
+		},
+	}
+
+	_, err := nl.AddWithReport(nil)
+	require.Error(t, err)
+
+	report, err := nl.AddWithReport(nl2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"openssl"}, report.Added)
+	require.Equal(t, []string{"app", "curl"}, report.Collided)
+	require.Equal(t, []string{"curl"}, report.Differed)
+
+	// The existing node is augmented with the incoming data
+	require.Len(t, nl.Nodes, 3)
+	require.Equal(t, "Daniel", nl.GetNodeByID("curl").Copyright)
+	require.Len(t, nl.Edges, 1)
+}
+
+func TestEqualWithOptions(t *testing.T) {
+	nl1 := &NodeList{
+		Nodes: []*Node{
+			{Id: "protobom-auto-1", Name: "app", ReleaseDate: timestamppb.New(time.Unix(100, 0))},
+			{Id: "protobom-auto-2", Name: "lib"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "protobom-auto-1", To: []string{"protobom-auto-2"}},
+		},
+		RootElements: []string{"protobom-auto-1"},
+	}
+	nl2 := &NodeList{
+		Nodes: []*Node{
+			{Id: "SPDXRef-1", Name: "app", ReleaseDate: timestamppb.New(time.Unix(200, 0))},
+			{Id: "SPDXRef-2", Name: "lib"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "SPDXRef-1", To: []string{"SPDXRef-2"}},
+		},
+		RootElements: []string{"SPDXRef-1"},
+	}
+	normalize := func(id string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(id, "protobom-auto-"), "SPDXRef-")
+	}
+
+	require.False(t, nl1.Equal(nl2))
+	require.False(t, nl1.EqualWithOptions(nl2, EqualOptions{NormalizeID: normalize}))
+	require.False(t, nl1.EqualWithOptions(nl2, EqualOptions{IgnoreFields: []string{"release_date"}}))
+	require.True(t, nl1.EqualWithOptions(nl2, EqualOptions{
+		IgnoreFields: []string{"release_date"},
+		NormalizeID:  normalize,
+	}))
+
+	// The compared NodeLists are not modified
+	require.Equal(t, "protobom-auto-1", nl1.Nodes[0].Id)
+	require.NotNil(t, nl1.Nodes[0].ReleaseDate)
+	require.Equal(t, "SPDXRef-1", nl2.Edges[0].From)
+}
+
+func TestEqualWithOptionsIgnoreIDs(t *testing.T) {
+	build := func(prefix, libVersion string) *NodeList {
+		return &NodeList{
+			Nodes: []*Node{
+				{
+					Id: prefix + "1", Name: "app", Version: "1.0",
+					Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:golang/example.com/app@1.0"},
+				},
+				{
+					Id: prefix + "2", Name: "lib", Version: libVersion,
+					Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:golang/example.com/lib@" + libVersion},
+				},
+				{Id: prefix + "3", Name: "data.txt", Type: Node_FILE, Hashes: map[string]string{"SHA256": "abcd"}},
+				{Id: prefix + "4", Name: "notes", Version: "2"},
+			},
+			Edges: []*Edge{
+				{Type: Edge_dependsOn, From: prefix + "1", To: []string{prefix + "2", prefix + "4"}},
+				{Type: Edge_contains, From: prefix + "1", To: []string{prefix + "3"}},
+			},
+			RootElements: []string{prefix + "1"},
+		}
+	}
+
+	opts := EqualOptions{IgnoreIDs: true}
+	nl1 := build("protobom-auto-", "1.2.0")
+	require.False(t, nl1.Equal(build("SPDXRef-", "1.2.0")))
+	require.True(t, nl1.EqualWithOptions(build("SPDXRef-", "1.2.0"), opts))
+
+	// A version change is still detected
+	require.False(t, nl1.EqualWithOptions(build("SPDXRef-", "1.3.0"), opts))
+
+	// Edge order does not matter
+	nl2 := build("SPDXRef-", "1.2.0")
+	nl2.Edges[0], nl2.Edges[1] = nl2.Edges[1], nl2.Edges[0]
+	nl2.Edges[1].To = []string{"SPDXRef-4", "SPDXRef-2"}
+	require.True(t, nl1.EqualWithOptions(nl2, opts))
+
+	// Relationships are compared through the node identities
+	nl2.Edges[1].To = []string{"SPDXRef-4"}
+	require.False(t, nl1.EqualWithOptions(nl2, opts))
+
+	require.Equal(t, "protobom-auto-1", nl1.Nodes[0].Id)
+}
+
+func TestDiff(t *testing.T) {
+	nl1 := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app", Hashes: map[string]string{"SHA256": "aaa", "BLAKE3": "bbb"}},
+			{Id: "lib", Name: "lib", Version: "1.0"},
+			{Id: "gone", Name: "gone"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib", "gone"}},
+		},
+		RootElements: []string{"app"},
+	}
+	nl2 := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app", Hashes: map[string]string{"SHA256": "aaa"}, Comment: "new"},
+			{Id: "lib", Name: "lib", Version: "2.0"},
+			{Id: "new", Name: "new"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib", "new"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	require.True(t, nl1.Diff(nl1).Empty())
+
+	d := nl1.Diff(nl2)
+	require.False(t, d.Empty())
+	require.Equal(t, []string{"new"}, d.NodesAdded)
+	require.Equal(t, []string{"gone"}, d.NodesRemoved)
+	require.Len(t, d.NodesChanged, 2)
+	require.Equal(t, NodeDiff{ID: "app", Added: []string{"comment"}, Removed: []string{"hashes[BLAKE3]"}, Changed: []string{}}, d.NodesChanged[0])
+	require.Equal(t, NodeDiff{ID: "lib", Added: []string{}, Removed: []string{}, Changed: []string{"version"}}, d.NodesChanged[1])
+	require.Len(t, d.EdgesAdded, 1)
+	require.Equal(t, []string{"new"}, d.EdgesAdded[0].To)
+	require.Len(t, d.EdgesRemoved, 1)
+	require.Equal(t, []string{"gone"}, d.EdgesRemoved[0].To)
+	require.Empty(t, d.RootsAdded)
+	require.Empty(t, d.RootsRemoved)
+}
+
+func TestStats(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:generic/app@1.0"}},
+			{Id: "lib", Hashes: map[string]string{"SHA256": "aaa"}, Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:example:lib:1.0:*:*:*:*:*:*:*"}},
+			{Id: "file", Type: Node_FILE},
+			{Id: "orphan"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib"}},
+			{Type: Edge_contains, From: "app", To: []string{"file", "lib"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	stats := nl.Stats()
+	require.Equal(t, 4, stats.TotalNodes)
+	require.Equal(t, map[Node_NodeType]int{Node_PACKAGE: 3, Node_FILE: 1}, stats.Nodes)
+	require.Equal(t, 3, stats.TotalEdges)
+	require.Equal(t, map[Edge_Type]int{Edge_dependsOn: 1, Edge_contains: 2}, stats.Edges)
+	require.Equal(t, 1, stats.Roots)
+	require.Equal(t, 1, stats.Orphans)
+	require.Equal(t, 1, stats.WithPurl)
+	require.Equal(t, 1, stats.WithCPE)
+	require.Equal(t, 1, stats.WithHashes)
+}
+
+func TestAddNodesAndEdges(t *testing.T) {
+	nl := &NodeList{}
+	nl.AddNodes(
+		&Node{Id: "app", Name: "app"},
+		&Node{Id: "lib", Name: "lib"},
+	)
+	require.Len(t, nl.Nodes, 2)
+
+	// Adding an existing ID merges the data into the existing node
+	nl.AddNodes(&Node{Id: "lib", Name: "other", Version: "1.0"}, &Node{Id: "tool"})
+	require.Len(t, nl.Nodes, 3)
+	require.Equal(t, "lib", nl.GetNodeByID("lib").Name)
+	require.Equal(t, "1.0", nl.GetNodeByID("lib").Version)
+
+	// Re-adding the same node is a noop
+	nl.AddNodes(&Node{Id: "app", Name: "app"})
+	require.Len(t, nl.Nodes, 3)
+
+	nl.AddEdges(
+		&Edge{Type: Edge_dependsOn, From: "app", To: []string{"lib"}},
+		&Edge{Type: Edge_dependsOn, From: "app", To: []string{"lib", "tool"}},
+		&Edge{Type: Edge_dependsOn, From: "app", To: []string{"missing"}},
+	)
+	require.Len(t, nl.Edges, 1)
+	to := append([]string{}, nl.Edges[0].To...)
+	sort.Strings(to)
+	require.Equal(t, []string{"lib", "tool"}, to)
+}
+
+func TestRetainEdgeTypes(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "app"}, {Id: "lib"}, {Id: "docs"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib"}},
+			{Type: Edge_documentation, From: "app", To: []string{"docs"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	deps := nl.RetainEdgeTypes(Edge_dependsOn)
+	require.Len(t, deps.Edges, 1)
+	require.Equal(t, Edge_dependsOn, deps.Edges[0].Type)
+	require.Len(t, deps.Nodes, 3)
+
+	// The original NodeList is not modified
+	require.Len(t, nl.Edges, 2)
+
+	// Nodes left disconnected can be pruned from the copy
+	deps.PruneUnreachable()
+	require.Len(t, deps.Nodes, 2)
+	require.Nil(t, deps.GetNodeByID("docs"))
+	require.Len(t, nl.Nodes, 3)
+}
+
+func TestExcludeEdgeTypes(t *testing.T) {
+	// jest is a dev dependency of app, its transitive dependency is only
+	// linked through it. shared is also a runtime dependency of lib.
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "app"}, {Id: "lib"}, {Id: "jest"}, {Id: "jest-dep"}, {Id: "shared"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib"}},
+			{Type: Edge_dependsOn, From: "lib", To: []string{"shared"}},
+			{Type: Edge_devDependency, From: "jest", To: []string{"app"}},
+			{Type: Edge_devDependency, From: "shared", To: []string{"app"}},
+			{Type: Edge_dependsOn, From: "jest", To: []string{"jest-dep"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	res := nl.ExcludeEdgeTypes(Edge_devDependency)
+	require.Equal(t, []string{"app", "lib", "shared"}, nodeIDs(res.Nodes))
+	require.Len(t, res.Edges, 2)
+	for _, e := range res.Edges {
+		require.Equal(t, Edge_dependsOn, e.Type)
+	}
+	require.Equal(t, []string{"app"}, res.RootElements)
+
+	// The original NodeList is not modified
+	require.Len(t, nl.Nodes, 5)
+	require.Len(t, nl.Edges, 5)
+
+	// Without roots, only the nodes left with no edges are removed
+	nl.RootElements = nil
+	res = nl.ExcludeEdgeTypes(Edge_devDependency, Edge_dependsOn)
+	require.Empty(t, res.Nodes)
+	res = nl.ExcludeEdgeTypes(Edge_devDependency)
+	require.Len(t, res.Nodes, 5)
+
+	// Excluding types not in the graph returns an equal copy
+	require.True(t, nl.Equal(nl.ExcludeEdgeTypes(Edge_testDependency)))
+
+	// A dev dependency sharing a runtime dependency with the app is
+	// removed, the shared dependency is kept
+	nl = &NodeList{
+		Nodes: []*Node{{Id: "app"}, {Id: "lodash"}, {Id: "jest"}, {Id: "unrelated"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lodash"}},
+			{Type: Edge_dependsOn, From: "jest", To: []string{"lodash"}},
+			{Type: Edge_devDependency, From: "jest", To: []string{"app"}},
+		},
+		RootElements: []string{"app"},
+	}
+	res = nl.ExcludeEdgeTypes(Edge_devDependency)
+	require.Equal(t, []string{"app", "lodash", "unrelated"}, nodeIDs(res.Nodes))
+	require.Len(t, res.Edges, 1)
+	require.Equal(t, "app", res.Edges[0].From)
+}
+
+func TestReplaceNode(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "app"}, {Id: "lib", Name: "lib"}},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"lib"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	require.Error(t, nl.ReplaceNode("missing", &Node{Id: "missing"}))
+	require.Error(t, nl.ReplaceNode("lib", nil))
+	require.Error(t, nl.ReplaceNode("lib", &Node{Id: "app"}))
+
+	require.NoError(t, nl.ReplaceNode("lib", &Node{
+		Id: "lib", Name: "lib", Licenses: []string{"MIT"},
+		Hashes: map[string]string{"SHA256": "aaa"},
+	}))
+	require.Len(t, nl.Nodes, 2)
+	lib := nl.GetNodeByID("lib")
+	require.Equal(t, []string{"MIT"}, lib.Licenses)
+	require.Equal(t, "aaa", lib.Hashes["SHA256"])
+	require.Equal(t, []string{"lib"}, nl.GetEdgeByType("app", Edge_dependsOn).To)
+	require.Equal(t, []string{"app"}, nl.RootElements)
+
+	// Replacing with a node with a different ID rewires the graph
+	require.NoError(t, nl.ReplaceNode("app", &Node{Id: "application", Name: "app"}))
+	require.Nil(t, nl.GetNodeByID("app"))
+	require.Equal(t, "app", nl.GetNodeByID("application").Name)
+	require.Equal(t, []string{"lib"}, nl.GetEdgeByType("application", Edge_dependsOn).To)
+	require.Equal(t, []string{"application"}, nl.RootElements)
+
+	require.NoError(t, nl.ReplaceNode("lib", &Node{Id: "pkg:npm/lib@1.0.0", Name: "lib"}))
+	require.Equal(t, []string{"pkg:npm/lib@1.0.0"}, nl.GetEdgeByType("application", Edge_dependsOn).To)
+}
+
+func TestDuplicateIDs(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "a", Name: "first a"},
+			{Id: "b"},
+			{Id: "a", Name: "second a"},
+			{Id: "a-2"},
+			{Id: "a", Name: "third a"},
+			{Id: "b"},
+		},
+		Edges:        []*Edge{{Type: Edge_dependsOn, From: "a", To: []string{"b"}}},
+		RootElements: []string{"a"},
+	}
+	require.Equal(t, []string{"a", "b"}, nl.DuplicateIDs())
+
+	renamed := nl.DisambiguateIDs()
+	require.Equal(t, map[string]string{"a-3": "a", "a-4": "a", "b-2": "b"}, renamed)
+	require.Equal(t, []string{"a", "b", "a-3", "a-2", "a-4", "b-2"}, nodeIDs(nl.Nodes))
+	require.Len(t, nl.DuplicateIDs(), 0)
+
+	// References stay with the first node
+	require.Equal(t, "first a", nl.GetNodeByID("a").Name)
+	require.Equal(t, "second a", nl.GetNodeByID("a-3").Name)
+	require.Equal(t, []string{"b"}, nl.Edges[0].To)
+	require.Equal(t, []string{"a"}, nl.RootElements)
+}
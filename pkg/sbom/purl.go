@@ -0,0 +1,176 @@
+package sbom
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// purlComponents splits the package url into its type, namespace and name.
+// This is not a complete purl parser, it only extracts the components needed
+// to query the nodes.
+func (purl PackageURL) purlComponents() (purlType, namespace, name string) {
+	s := string(purl)
+	if !strings.HasPrefix(s, "pkg:") {
+		return "", "", ""
+	}
+
+	// Some tools (and the SPDX libraries) add a slash after the scheme
+	s = strings.TrimLeft(strings.TrimPrefix(s, "pkg:"), "/")
+
+	// Trim the subpath, qualifiers and version
+	if i := strings.Index(s, "#"); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "?"); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "@"); i != -1 && i > strings.LastIndex(s, "/") {
+		s = s[:i]
+	}
+
+	parts := strings.Split(strings.Trim(s, "/"), "/")
+	purlType = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		name = unescapePurlComponent(parts[len(parts)-1])
+	}
+	if len(parts) > 2 {
+		ns := []string{}
+		for _, p := range parts[1 : len(parts)-1] {
+			ns = append(ns, unescapePurlComponent(p))
+		}
+		namespace = strings.Join(ns, "/")
+	}
+	return purlType, namespace, name
+}
+
+func unescapePurlComponent(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// Type returns the package type of the purl, for example "npm" or "deb"
+func (purl PackageURL) Type() string {
+	t, _, _ := purl.purlComponents()
+	return t
+}
+
+// Namespace returns the namespace of the purl, for example the organization
+// of a GitHub package or the distribution of a deb
+func (purl PackageURL) Namespace() string {
+	_, ns, _ := purl.purlComponents()
+	return ns
+}
+
+// Name returns the name of the package in the purl
+func (purl PackageURL) Name() string {
+	_, _, name := purl.purlComponents()
+	return name
+}
+
+// Version returns the version of the package in the purl
+func (purl PackageURL) Version() string {
+	s := string(purl)
+	if !strings.HasPrefix(s, "pkg:") {
+		return ""
+	}
+	if i := strings.Index(s, "#"); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "?"); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "@"); i != -1 && i > strings.LastIndex(s, "/") {
+		return unescapePurlComponent(s[i+1:])
+	}
+	return ""
+}
+
+// Canonical returns the purl in its canonical form so that equivalent
+// purls compare equal: the type and qualifier keys are lowercased,
+// qualifiers are sorted by key dropping the empty ones, the subpath is
+// cleaned and all components are percent-encoded consistently. Strings
+// that are not purls are returned unchanged.
+func (purl PackageURL) Canonical() PackageURL {
+	s := string(purl)
+	if !strings.HasPrefix(s, "pkg:") {
+		return purl
+	}
+	s = strings.TrimLeft(strings.TrimPrefix(s, "pkg:"), "/")
+
+	subpath := ""
+	if i := strings.Index(s, "#"); i != -1 {
+		s, subpath = s[:i], s[i+1:]
+	}
+	qualifiers := ""
+	if i := strings.Index(s, "?"); i != -1 {
+		s, qualifiers = s[:i], s[i+1:]
+	}
+	version := ""
+	if i := strings.LastIndex(s, "@"); i != -1 && i > strings.LastIndex(s, "/") {
+		s, version = s[:i], s[i+1:]
+	}
+
+	parts := strings.Split(strings.Trim(s, "/"), "/")
+	ret := "pkg:" + strings.ToLower(parts[0])
+	for _, p := range parts[1:] {
+		ret += "/" + escapePurlComponent(unescapePurlComponent(p), "")
+	}
+
+	if version != "" {
+		ret += "@" + escapePurlComponent(unescapePurlComponent(version), ":")
+	}
+
+	if q := canonicalQualifiers(qualifiers); q != "" {
+		ret += "?" + q
+	}
+
+	segments := []string{}
+	for _, seg := range strings.Split(subpath, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			continue
+		}
+		segments = append(segments, escapePurlComponent(unescapePurlComponent(seg), ""))
+	}
+	if len(segments) > 0 {
+		ret += "#" + strings.Join(segments, "/")
+	}
+
+	return PackageURL(ret)
+}
+
+// canonicalQualifiers sorts and normalizes a purl qualifier string
+func canonicalQualifiers(qualifiers string) string {
+	pairs := []string{}
+	for _, q := range strings.Split(qualifiers, "&") {
+		k, v, _ := strings.Cut(q, "=")
+		k = strings.ToLower(k)
+		v = unescapePurlComponent(v)
+		if k == "" || v == "" {
+			continue
+		}
+		pairs = append(pairs, k+"="+escapePurlComponent(v, ":/"))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// escapePurlComponent percent-encodes all characters except the unreserved
+// ones and those in allowed
+func escapePurlComponent(s, allowed string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9'),
+			strings.IndexByte(".-_~", c) != -1, strings.IndexByte(allowed, c) != -1:
+			sb.WriteByte(c)
+		default:
+			sb.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+	return sb.String()
+}
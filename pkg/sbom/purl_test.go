@@ -0,0 +1,47 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurlComponents(t *testing.T) {
+	for _, tc := range []struct {
+		purl                        PackageURL
+		purlType, ns, name, version string
+	}{
+		{"pkg:apk/wolfi/curl@8.1.1-r0?arch=x86_64", "apk", "wolfi", "curl", "8.1.1-r0"},
+		{"pkg:/deb/debian/libc6@2.31?arch=amd64", "deb", "debian", "libc6", "2.31"},
+		{"pkg:npm/%40angular/core@16.0.0", "npm", "@angular", "core", "16.0.0"},
+		{"pkg:golang/github.com/sirupsen/logrus@v1.9.3#hooks", "golang", "github.com/sirupsen", "logrus", "v1.9.3"},
+		{"pkg:generic/openssl", "generic", "", "openssl", ""},
+		{"pkg:oci/curl@sha256:47fed?repository_url=cgr.dev/chainguard", "oci", "", "curl", "sha256:47fed"},
+		{"pkg:oci/curl@sha256%3A47fed?repository_url=cgr.dev/chainguard", "oci", "", "curl", "sha256:47fed"},
+		{"https://example.com", "", "", "", ""},
+		{"", "", "", "", ""},
+	} {
+		require.Equal(t, tc.purlType, tc.purl.Type(), string(tc.purl))
+		require.Equal(t, tc.ns, tc.purl.Namespace(), string(tc.purl))
+		require.Equal(t, tc.name, tc.purl.Name(), string(tc.purl))
+		require.Equal(t, tc.version, tc.purl.Version(), string(tc.purl))
+	}
+}
+
+func TestPurlCanonical(t *testing.T) {
+	for _, tc := range []struct {
+		purl     PackageURL
+		expected PackageURL
+	}{
+		{"pkg:npm/%40angular/core@16.0.0", "pkg:npm/%40angular/core@16.0.0"},
+		{"pkg:npm/@angular/core@16.0.0", "pkg:npm/%40angular/core@16.0.0"},
+		{"pkg:/DEB/debian/curl@7.74.0?distro=bullseye&arch=amd64", "pkg:deb/debian/curl@7.74.0?arch=amd64&distro=bullseye"},
+		{"pkg:deb/debian/curl@7.74.0?ARCH=amd64&distro=", "pkg:deb/debian/curl@7.74.0?arch=amd64"},
+		{"pkg:oci/curl@sha256%3A47fed?repository_url=cgr.dev%2Fchainguard", "pkg:oci/curl@sha256:47fed?repository_url=cgr.dev/chainguard"},
+		{"pkg:golang/github.com/sirupsen/logrus@v1.9.3#/hooks/./syslog/", "pkg:golang/github.com/sirupsen/logrus@v1.9.3#hooks/syslog"},
+		{"pkg:generic/open%20ssl", "pkg:generic/open%20ssl"},
+		{"https://example.com", "https://example.com"},
+	} {
+		require.Equal(t, tc.expected, tc.purl.Canonical(), string(tc.purl))
+	}
+}
@@ -0,0 +1,48 @@
+package sbom
+
+// NodeListStats summarizes the contents of a NodeList
+type NodeListStats struct {
+	Nodes      map[Node_NodeType]int // Number of nodes by type
+	Edges      map[Edge_Type]int     // Number of relationships by type, one per destination
+	Roots      int                   // Number of root elements
+	Orphans    int                   // Nodes that are not roots or the destination of an edge
+	WithPurl   int                   // Nodes with a package url
+	WithCPE    int                   // Nodes with a CPE (2.2 or 2.3)
+	WithHashes int                   // Nodes with at least one hash
+	TotalNodes int
+	TotalEdges int
+}
+
+// Stats returns a summary of the NodeList contents
+func (nl *NodeList) Stats() NodeListStats {
+	stats := NodeListStats{
+		Nodes: map[Node_NodeType]int{},
+		Edges: map[Edge_Type]int{},
+	}
+	if nl == nil {
+		return stats
+	}
+
+	stats.Roots = len(nl.RootElements)
+	stats.Orphans = len(nl.Orphans())
+
+	for _, e := range nl.Edges {
+		stats.Edges[e.Type] += len(e.To)
+		stats.TotalEdges += len(e.To)
+	}
+
+	for _, n := range nl.Nodes {
+		stats.Nodes[n.Type]++
+		stats.TotalNodes++
+		if n.Purl() != "" {
+			stats.WithPurl++
+		}
+		if n.Identifiers[int32(SoftwareIdentifierType_CPE22)] != "" || n.Identifiers[int32(SoftwareIdentifierType_CPE23)] != "" {
+			stats.WithCPE++
+		}
+		if len(n.Hashes) > 0 {
+			stats.WithHashes++
+		}
+	}
+	return stats
+}
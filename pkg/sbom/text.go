@@ -0,0 +1,119 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// TextRenderOptions control the output of NodeList.RenderText
+type TextRenderOptions struct {
+	// MaxDepth is the number of levels printed below the root elements,
+	// zero prints the whole graph
+	MaxDepth int
+
+	// EdgeTypes are the relationships followed to find the children of a
+	// node. All types are followed if empty.
+	EdgeTypes []Edge_Type
+
+	// NoHeader omits the column names line
+	NoHeader bool
+}
+
+// RenderText writes the NodeList to w as an indented tree starting at its
+// root elements, with columns for the name, version and primary identifier
+// of each node. Nodes are expanded only the first time they are printed,
+// like in DependencyTree: later occurrences of a node with children in
+// shared subtrees are printed with a "(ref)" mark and a node that points
+// back to one of its ancestors with a "(cycle)" mark. An error is returned
+// if a root element is not in the NodeList.
+func (nl *NodeList) RenderText(w io.Writer, opts TextRenderOptions) error {
+	typeIndex := map[Edge_Type]struct{}{}
+	for _, t := range opts.EdgeTypes {
+		typeIndex[t] = struct{}{}
+	}
+
+	nodeIndex := nl.indexNodes()
+	children := map[string][]string{}
+	for _, e := range nl.Edges {
+		if _, ok := typeIndex[e.Type]; len(typeIndex) > 0 && !ok {
+			continue
+		}
+		children[e.From] = append(children[e.From], e.To...)
+	}
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	if !opts.NoHeader {
+		fmt.Fprintln(tw, "NAME\tVERSION\tIDENTIFIER")
+	}
+
+	path := map[string]struct{}{}
+	expanded := map[string]struct{}{}
+	var render func(n *Node, depth int)
+	render = func(n *Node, depth int) {
+		name := n.Name
+		if name == "" {
+			name = n.Id
+		}
+		_, cycle := path[n.Id]
+		_, ref := expanded[n.Id]
+		switch {
+		case cycle:
+			name += " (cycle)"
+		case ref && len(children[n.Id]) > 0:
+			name += " (ref)"
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\n", strings.Repeat("  ", depth), name, n.Version, n.primaryIdentifier())
+
+		if cycle || ref || (opts.MaxDepth > 0 && depth >= opts.MaxDepth) {
+			return
+		}
+		expanded[n.Id] = struct{}{}
+		path[n.Id] = struct{}{}
+		for _, id := range children[n.Id] {
+			if child, ok := nodeIndex[id]; ok {
+				render(child, depth+1)
+			}
+		}
+		delete(path, n.Id)
+	}
+
+	for _, id := range nl.RootElements {
+		n, ok := nodeIndex[id]
+		if !ok {
+			return fmt.Errorf("root element %s not found in nodes", id)
+		}
+		render(n, 0)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("formatting text output: %w", err)
+	}
+
+	// Trim the padding left after the last column of nodes with no identifier
+	var out strings.Builder
+	for _, line := range strings.SplitAfter(sb.String(), "\n") {
+		if strings.HasSuffix(line, "\n") {
+			out.WriteString(strings.TrimRight(line, " \n") + "\n")
+		}
+	}
+	if _, err := io.WriteString(w, out.String()); err != nil {
+		return fmt.Errorf("writing text output: %w", err)
+	}
+	return nil
+}
+
+// primaryIdentifier returns the identifier shown for the node: its purl,
+// or its CPE if it has no purl
+func (n *Node) primaryIdentifier() string {
+	for _, t := range []SoftwareIdentifierType{
+		SoftwareIdentifierType_PURL, SoftwareIdentifierType_CPE23, SoftwareIdentifierType_CPE22,
+	} {
+		if id := n.Identifiers[int32(t)]; id != "" {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package sbom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderText(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{
+				Id: "app", Name: "app", Version: "1.0",
+				Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:generic/app@1.0"},
+			},
+			{
+				Id: "lib", Name: "lib", Version: "2.3",
+				Identifiers: map[int32]string{int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:acme:lib:2.3:*:*:*:*:*:*:*"},
+			},
+			{Id: "deep"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "app", To: []string{"lib"}},
+			{Type: Edge_dependsOn, From: "lib", To: []string{"deep"}},
+			{Type: Edge_dependsOn, From: "deep", To: []string{"app"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     TextRenderOptions
+		expected string
+	}{
+		{
+			name: "full tree",
+			expected: "NAME               VERSION  IDENTIFIER\n" +
+				"app                1.0      pkg:generic/app@1.0\n" +
+				"  lib              2.3      cpe:2.3:a:acme:lib:2.3:*:*:*:*:*:*:*\n" +
+				"    deep\n" +
+				"      app (cycle)  1.0      pkg:generic/app@1.0\n",
+		},
+		{
+			name: "max depth",
+			opts: TextRenderOptions{MaxDepth: 1, NoHeader: true},
+			expected: "app    1.0  pkg:generic/app@1.0\n" +
+				"  lib  2.3  cpe:2.3:a:acme:lib:2.3:*:*:*:*:*:*:*\n",
+		},
+		{
+			name:     "edge types",
+			opts:     TextRenderOptions{EdgeTypes: []Edge_Type{Edge_dependsOn}, NoHeader: true},
+			expected: "app  1.0  pkg:generic/app@1.0\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var sb strings.Builder
+			require.NoError(t, nl.RenderText(&sb, tc.opts))
+			require.Equal(t, tc.expected, sb.String())
+		})
+	}
+
+	nl.RootElements = []string{"missing"}
+	require.Error(t, nl.RenderText(&strings.Builder{}, TextRenderOptions{}))
+}
+
+func TestRenderTextDiamond(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app"},
+			{Id: "a", Name: "a"},
+			{Id: "b", Name: "b"},
+			{Id: "shared", Name: "shared"},
+			{Id: "leaf", Name: "leaf"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_dependsOn, From: "app", To: []string{"a", "b"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"shared"}},
+			{Type: Edge_dependsOn, From: "b", To: []string{"shared"}},
+			{Type: Edge_dependsOn, From: "shared", To: []string{"leaf"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	// The shared subtree is only expanded the first time it is printed
+	var sb strings.Builder
+	require.NoError(t, nl.RenderText(&sb, TextRenderOptions{NoHeader: true}))
+	require.Equal(t, "app\n"+
+		"  a\n"+
+		"    shared\n"+
+		"      leaf\n"+
+		"  b\n"+
+		"    shared (ref)\n", sb.String())
+
+	// Nodes cut by the depth limit are expanded where they appear again
+	sb.Reset()
+	nl.Edges[0].To = []string{"a", "shared"}
+	require.NoError(t, nl.RenderText(&sb, TextRenderOptions{NoHeader: true, MaxDepth: 2}))
+	require.Equal(t, "app\n"+
+		"  a\n"+
+		"    shared\n"+
+		"  shared\n"+
+		"    leaf\n", sb.String())
+}
@@ -0,0 +1,124 @@
+package sbom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ctxCheckInterval is the number of steps traversals take between checks
+// of their context
+const ctxCheckInterval = 64
+
+// ErrCycle is returned when sorting a graph with cycles
+var ErrCycle = errors.New("graph has cycles")
+
+// Descendants returns the nodes reachable from node id following edges of
+// the given types (all types if none are specified), in breadth first order.
+// The node itself is not included.
+func (nl *NodeList) Descendants(id string, types ...Edge_Type) []*Node {
+	// The background context is never canceled, so there is no error
+	ret, _ := nl.DescendantsCtx(context.Background(), id, types...)
+	return ret
+}
+
+// DescendantsCtx works as Descendants but aborts the traversal and returns
+// the context error when ctx is canceled.
+func (nl *NodeList) DescendantsCtx(ctx context.Context, id string, types ...Edge_Type) ([]*Node, error) {
+	typeIndex := map[Edge_Type]struct{}{}
+	for _, t := range types {
+		typeIndex[t] = struct{}{}
+	}
+
+	nodeIndex := nl.indexNodes()
+	children := map[string][]string{}
+	for _, e := range nl.Edges {
+		if _, ok := typeIndex[e.Type]; len(typeIndex) > 0 && !ok {
+			continue
+		}
+		children[e.From] = append(children[e.From], e.To...)
+	}
+
+	ret := []*Node{}
+	seen := map[string]struct{}{id: {}}
+	queue := []string{id}
+	for i := 0; len(queue) > 0; i++ {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		current := queue[0]
+		queue = queue[1:]
+		for _, to := range children[current] {
+			if _, ok := seen[to]; ok {
+				continue
+			}
+			seen[to] = struct{}{}
+			queue = append(queue, to)
+			if n, ok := nodeIndex[to]; ok {
+				ret = append(ret, n)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// TopoSort returns the nodes sorted so that every node comes before the
+// nodes its edges point to. Nodes with no order between them keep their
+// order in the NodeList. If the graph has cycles ErrCycle is returned.
+func (nl *NodeList) TopoSort() ([]*Node, error) {
+	return nl.TopoSortCtx(context.Background())
+}
+
+// TopoSortCtx works as TopoSort but aborts and returns the context error
+// when ctx is canceled.
+func (nl *NodeList) TopoSortCtx(ctx context.Context) ([]*Node, error) {
+	nodeIndex := nl.indexNodes()
+	inDegree := map[string]int{}
+	children := map[string][]string{}
+	for _, e := range nl.Edges {
+		if _, ok := nodeIndex[e.From]; !ok {
+			continue
+		}
+		for _, to := range e.To {
+			if _, ok := nodeIndex[to]; !ok {
+				continue
+			}
+			children[e.From] = append(children[e.From], to)
+			inDegree[to]++
+		}
+	}
+
+	queue := []string{}
+	for _, n := range nl.Nodes {
+		if inDegree[n.Id] == 0 {
+			queue = append(queue, n.Id)
+		}
+	}
+
+	ret := []*Node{}
+	for i := 0; len(queue) > 0; i++ {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		id := queue[0]
+		queue = queue[1:]
+		ret = append(ret, nodeIndex[id])
+		for _, to := range children[id] {
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	if len(ret) != len(nodeIndex) {
+		return nil, fmt.Errorf("%d nodes could not be sorted: %w", len(nodeIndex)-len(ret), ErrCycle)
+	}
+	return ret, nil
+}
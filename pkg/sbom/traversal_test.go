@@ -0,0 +1,95 @@
+package sbom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countdownContext is a context that is canceled after its Err method has
+// been called a number of times
+type countdownContext struct {
+	context.Context
+	calls int
+}
+
+func (c *countdownContext) Err() error {
+	if c.calls == 0 {
+		return context.Canceled
+	}
+	c.calls--
+	return nil
+}
+
+func chainNodeList(n int) *NodeList {
+	nl := &NodeList{}
+	for i := 0; i < n; i++ {
+		nl.Nodes = append(nl.Nodes, &Node{Id: fmt.Sprintf("node%d", i)})
+		if i > 0 {
+			nl.Edges = append(nl.Edges, &Edge{
+				Type: Edge_dependsOn, From: fmt.Sprintf("node%d", i-1), To: []string{fmt.Sprintf("node%d", i)},
+			})
+		}
+	}
+	return nl
+}
+
+func nodeIDs(nodes []*Node) []string {
+	ret := []string{}
+	for _, n := range nodes {
+		ret = append(ret, n.Id)
+	}
+	return ret
+}
+
+func TestDescendants(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "root"}, {Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "root", To: []string{"a", "b"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"c"}},
+			{Type: Edge_dependsOn, From: "b", To: []string{"c", "root"}},
+			{Type: Edge_buildTool, From: "c", To: []string{"d"}},
+		},
+	}
+	require.Equal(t, []string{"a", "b", "c", "d"}, nodeIDs(nl.Descendants("root")))
+	require.Equal(t, []string{"c", "root", "d", "a"}, nodeIDs(nl.Descendants("b")))
+	require.Equal(t, []string{"a", "b"}, nodeIDs(nl.Descendants("root", Edge_contains)))
+	require.Empty(t, nl.Descendants("d"))
+}
+
+func TestTopoSort(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{{Id: "c"}, {Id: "b"}, {Id: "a"}, {Id: "root"}},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "root", To: []string{"a", "b"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"c"}},
+			{Type: Edge_dependsOn, From: "b", To: []string{"c"}},
+		},
+	}
+	sorted, err := nl.TopoSort()
+	require.NoError(t, err)
+	require.Equal(t, []string{"root", "a", "b", "c"}, nodeIDs(sorted))
+
+	nl.Edges = append(nl.Edges, &Edge{Type: Edge_dependsOn, From: "c", To: []string{"root"}})
+	_, err = nl.TopoSort()
+	require.True(t, errors.Is(err, ErrCycle))
+}
+
+func TestTraversalCanceled(t *testing.T) {
+	nl := chainNodeList(1000)
+
+	// The context is canceled after the traversal has started
+	_, err := nl.DescendantsCtx(&countdownContext{Context: context.Background(), calls: 3}, "node0")
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = nl.TopoSortCtx(&countdownContext{Context: context.Background(), calls: 3})
+	require.ErrorIs(t, err, context.Canceled)
+
+	descendants, err := nl.DescendantsCtx(context.Background(), "node0")
+	require.NoError(t, err)
+	require.Len(t, descendants, 999)
+}
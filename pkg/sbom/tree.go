@@ -0,0 +1,71 @@
+package sbom
+
+import "fmt"
+
+// TreeNode is a node in the nested representation of a NodeList returned
+// by DependencyTree
+type TreeNode struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+
+	// Relationship is the type of the edge from the parent node, empty in
+	// the tree roots
+	Relationship string `json:"relationship,omitempty"`
+
+	// Ref is true when the node is already expanded in another branch of
+	// the tree. Its children are not listed again.
+	Ref bool `json:"ref,omitempty"`
+
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// DependencyTree returns the NodeList as a nested structure rooted at its
+// root elements, following the contains and dependsOn edges. Each node is
+// expanded only the first time it is found (depth first), later
+// occurrences in shared subtrees or cycles are marked as references. An
+// error is returned if a root element is not in the NodeList.
+func (nl *NodeList) DependencyTree() ([]*TreeNode, error) {
+	nodeIndex := nl.indexNodes()
+	children := map[string][]*Edge{}
+	for _, e := range nl.Edges {
+		if e.Type == Edge_contains || e.Type == Edge_dependsOn {
+			children[e.From] = append(children[e.From], e)
+		}
+	}
+
+	expanded := map[string]struct{}{}
+	var expand func(n *Node, relationship string) *TreeNode
+	expand = func(n *Node, relationship string) *TreeNode {
+		tn := &TreeNode{
+			ID:           n.Id,
+			Name:         n.Name,
+			Version:      n.Version,
+			Relationship: relationship,
+		}
+		if _, ok := expanded[n.Id]; ok {
+			tn.Ref = true
+			return tn
+		}
+		expanded[n.Id] = struct{}{}
+
+		for _, e := range children[n.Id] {
+			for _, to := range e.To {
+				if child, ok := nodeIndex[to]; ok {
+					tn.Children = append(tn.Children, expand(child, e.Type.String()))
+				}
+			}
+		}
+		return tn
+	}
+
+	ret := []*TreeNode{}
+	for _, id := range nl.RootElements {
+		n, ok := nodeIndex[id]
+		if !ok {
+			return nil, fmt.Errorf("root element %s not found in nodes", id)
+		}
+		ret = append(ret, expand(n, ""))
+	}
+	return ret, nil
+}
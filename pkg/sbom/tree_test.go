@@ -0,0 +1,50 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyTree(t *testing.T) {
+	// app contains a and b, which both depend on shared. shared depends
+	// back on app to form a cycle.
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "app", Name: "app"}, {Id: "a", Name: "a", Version: "1.0"},
+			{Id: "b", Name: "b"}, {Id: "shared", Name: "shared"}, {Id: "docs"},
+		},
+		Edges: []*Edge{
+			{Type: Edge_contains, From: "app", To: []string{"a", "b"}},
+			{Type: Edge_dependsOn, From: "a", To: []string{"shared"}},
+			{Type: Edge_dependsOn, From: "b", To: []string{"shared"}},
+			{Type: Edge_dependsOn, From: "shared", To: []string{"app"}},
+			{Type: Edge_documentation, From: "app", To: []string{"docs"}},
+		},
+		RootElements: []string{"app"},
+	}
+
+	tree, err := nl.DependencyTree()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{
+  "id": "app", "name": "app",
+  "children": [
+    {"id": "a", "name": "a", "version": "1.0", "relationship": "contains", "children": [
+      {"id": "shared", "name": "shared", "relationship": "dependsOn", "children": [
+        {"id": "app", "name": "app", "relationship": "dependsOn", "ref": true}
+      ]}
+    ]},
+    {"id": "b", "name": "b", "relationship": "contains", "children": [
+      {"id": "shared", "name": "shared", "relationship": "dependsOn", "ref": true}
+    ]}
+  ]
+}]`, string(data))
+
+	nl.RootElements = append(nl.RootElements, "missing")
+	_, err = nl.DependencyTree()
+	require.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package sbom
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// hashLengths is the length in hex characters of the digests of the hash
+// algorithms with a fixed size output
+var hashLengths = map[HashAlgorithm]int{
+	HashAlgorithm_MD2:         32,
+	HashAlgorithm_MD4:         32,
+	HashAlgorithm_MD5:         32,
+	HashAlgorithm_ADLER32:     8,
+	HashAlgorithm_SHA1:        40,
+	HashAlgorithm_SHA224:      56,
+	HashAlgorithm_SHA256:      64,
+	HashAlgorithm_SHA384:      96,
+	HashAlgorithm_SHA512:      128,
+	HashAlgorithm_SHA3_256:    64,
+	HashAlgorithm_SHA3_384:    96,
+	HashAlgorithm_SHA3_512:    128,
+	HashAlgorithm_BLAKE2B_256: 64,
+	HashAlgorithm_BLAKE2B_384: 96,
+	HashAlgorithm_BLAKE2B_512: 128,
+}
+
+var purlTypeRegex = regexp.MustCompile(`^[a-zA-Z.+-][a-zA-Z0-9.+-]*$`)
+
+// Validate checks the node fields and returns a list of all the problems
+// found: an empty ID, an unknown type, malformed purl or CPE identifiers,
+// hashes of unknown algorithms or with invalid digests and invalid dates.
+// A valid node returns an empty list.
+func (n *Node) Validate() []error {
+	errs := []error{}
+	if n.Id == "" {
+		errs = append(errs, errors.New("node has no ID"))
+	}
+
+	if _, ok := Node_NodeType_name[int32(n.Type)]; !ok {
+		errs = append(errs, fmt.Errorf("unknown node type %d", n.Type))
+	}
+
+	// Sort the map keys to return the errors in a stable order
+	idTypes := []int32{}
+	for t := range n.Identifiers {
+		idTypes = append(idTypes, t)
+	}
+	sort.Slice(idTypes, func(i, j int) bool { return idTypes[i] < idTypes[j] })
+	for _, t := range idTypes {
+		if err := validateIdentifier(SoftwareIdentifierType(t), n.Identifiers[t]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	algos := []string{}
+	for algo := range n.Hashes {
+		algos = append(algos, algo)
+	}
+	sort.Strings(algos)
+	for _, algo := range algos {
+		if err := validateHash(algo, n.Hashes[algo]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, d := range []struct {
+		name string
+		ts   *timestamppb.Timestamp
+	}{
+		{"release date", n.ReleaseDate},
+		{"build date", n.BuildDate},
+		{"valid until date", n.ValidUntilDate},
+	} {
+		if d.ts == nil {
+			continue
+		}
+		if err := d.ts.CheckValid(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", d.name, err))
+		}
+	}
+
+	return errs
+}
+
+// validateIdentifier checks the syntax of purl and CPE identifiers
+func validateIdentifier(t SoftwareIdentifierType, value string) error {
+	switch t {
+	case SoftwareIdentifierType_PURL:
+		purl := PackageURL(value)
+		if !strings.HasPrefix(value, "pkg:") {
+			return fmt.Errorf("purl %q does not start with pkg:", value)
+		}
+		if !purlTypeRegex.MatchString(purl.Type()) {
+			return fmt.Errorf("purl %q has an invalid type", value)
+		}
+		if purl.Name() == "" {
+			return fmt.Errorf("purl %q has no name", value)
+		}
+	case SoftwareIdentifierType_CPE23:
+		if !strings.HasPrefix(value, "cpe:2.3:") {
+			return fmt.Errorf("CPE %q does not start with cpe:2.3:", value)
+		}
+		parts := splitCPE23(value)
+		if len(parts) != 13 {
+			return fmt.Errorf("CPE %q has %d components, expected 13", value, len(parts))
+		}
+		if !strings.Contains("aho*-", parts[2]) || len(parts[2]) != 1 {
+			return fmt.Errorf("CPE %q has an invalid part %q", value, parts[2])
+		}
+	case SoftwareIdentifierType_CPE22:
+		if !strings.HasPrefix(value, "cpe:/") {
+			return fmt.Errorf("CPE %q does not start with cpe:/", value)
+		}
+	}
+	return nil
+}
+
+// splitCPE23 splits a CPE 2.3 formatted string in its components,
+// skipping the escaped colons
+func splitCPE23(cpe string) []string {
+	parts := []string{}
+	current := ""
+	for i := 0; i < len(cpe); i++ {
+		switch {
+		case cpe[i] == '\\' && i+1 < len(cpe):
+			current += cpe[i : i+2]
+			i++
+		case cpe[i] == ':':
+			parts = append(parts, current)
+			current = ""
+		default:
+			current += string(cpe[i])
+		}
+	}
+	return append(parts, current)
+}
+
+// validateHash checks the algorithm of a hash is known and its digest is
+// hex encoded and of the right length
+func validateHash(algo, digest string) error {
+	algoVal, ok := HashAlgorithm_value[algo]
+	if !ok || HashAlgorithm(algoVal) == HashAlgorithm_UNKNOWN {
+		return fmt.Errorf("unknown hash algorithm %q", algo)
+	}
+
+	if _, err := hex.DecodeString(digest); err != nil || digest == "" {
+		return fmt.Errorf("%s digest %q is not a hex string", algo, digest)
+	}
+
+	if l, ok := hashLengths[HashAlgorithm(algoVal)]; ok && len(digest) != l {
+		return fmt.Errorf("%s digest has %d characters, expected %d", algo, len(digest), l)
+	}
+	return nil
+}
+
+// Validate checks all the nodes in the NodeList and returns the problems
+// found, see Node.Validate for the list of checks.
+func (nl *NodeList) Validate() []error {
+	errs := []error{}
+	for _, n := range nl.Nodes {
+		for _, err := range n.Validate() {
+			errs = append(errs, fmt.Errorf("node %q: %w", n.Id, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNodeValidate(t *testing.T) {
+	validNode := func() *Node {
+		return &Node{
+			Id:   "node",
+			Type: Node_PACKAGE,
+			Identifiers: map[int32]string{
+				int32(SoftwareIdentifierType_PURL):  "pkg:npm/%40angular/core@16.0.0",
+				int32(SoftwareIdentifierType_CPE23): `cpe:2.3:a:angular:core\:js:16.0.0:*:*:*:*:*:*:*`,
+			},
+			Hashes: map[string]string{
+				"SHA1":   "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				"SHA256": "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855",
+			},
+			ReleaseDate: timestamppb.Now(),
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		mutate   func(*Node)
+		messages []string
+	}{
+		{"valid node", func(*Node) {}, []string{}},
+		{
+			"malformed purl",
+			func(n *Node) { n.Identifiers[int32(SoftwareIdentifierType_PURL)] = "npm/lodash@4.17.20" },
+			[]string{`purl "npm/lodash@4.17.20" does not start with pkg:`},
+		},
+		{
+			"purl without name",
+			func(n *Node) { n.Identifiers[int32(SoftwareIdentifierType_PURL)] = "pkg:npm" },
+			[]string{`purl "pkg:npm" has no name`},
+		},
+		{
+			"short CPE",
+			func(n *Node) { n.Identifiers[int32(SoftwareIdentifierType_CPE23)] = "cpe:2.3:a:angular:core" },
+			[]string{`CPE "cpe:2.3:a:angular:core" has 5 components, expected 13`},
+		},
+		{
+			"wrong length hash",
+			func(n *Node) { n.Hashes["SHA1"] = "da39a3ee5e6b4b0d3255" },
+			[]string{"SHA1 digest has 20 characters, expected 40"},
+		},
+		{
+			"non hex hash and unknown algorithm",
+			func(n *Node) {
+				n.Hashes["SHA256"] = "not-a-hash"
+				n.Hashes["CRC32"] = "cbf43926"
+			},
+			[]string{`unknown hash algorithm "CRC32"`, `SHA256 digest "not-a-hash" is not a hex string`},
+		},
+		{
+			"no id, bad type and date",
+			func(n *Node) {
+				n.Id = ""
+				n.Type = Node_NodeType(5)
+				n.BuildDate = &timestamppb.Timestamp{Seconds: -62135596801}
+			},
+			[]string{"node has no ID", "unknown node type 5", "invalid build date"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			n := validNode()
+			tc.mutate(n)
+			errs := n.Validate()
+			require.Len(t, errs, len(tc.messages), "%v", errs)
+			for i, msg := range tc.messages {
+				require.Contains(t, errs[i].Error(), msg)
+			}
+		})
+	}
+}
+
+func TestNodeListValidate(t *testing.T) {
+	nl := &NodeList{Nodes: []*Node{
+		{Id: "good"},
+		{Id: "bad", Hashes: map[string]string{"MD5": "0"}},
+	}}
+	errs := nl.Validate()
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), `node "bad"`)
+}
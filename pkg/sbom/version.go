@@ -0,0 +1,510 @@
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionComparator compares two versions returning a negative number if
+// v1 < v2, zero if they are equal and a positive number if v1 > v2
+type versionComparator func(v1, v2 string) int
+
+// versionComparators maps purl types to their ecosystem comparison rules.
+// Types not listed here are compared with compareSemver, which reads the
+// package revisions of distribution versions (eg -r1) as pre-releases.
+var versionComparators = map[string]versionComparator{
+	"alpm": compareRPM, // pacman's vercmp is derived from rpm's
+	"apk":  compareAPK,
+	"deb":  compareDebian,
+	"rpm":  compareRPM,
+}
+
+// comparatorForPurlType returns the version comparator for the purl type
+func comparatorForPurlType(purlType string) versionComparator {
+	if c, ok := versionComparators[purlType]; ok {
+		return c
+	}
+	return compareSemver
+}
+
+// versionConstraint is a single comparison in a version range, eg "< 3.0.7"
+type versionConstraint struct {
+	op      string
+	version string
+}
+
+var versionConstraintRegex = regexp.MustCompile(`(<=|>=|!=|==|<|>|=)?\s*([^\s,<>=!|]+)`)
+
+// parseVersionRange parses a version range. Comparisons separated by commas
+// or spaces must all hold while groups separated by || are alternatives,
+// eg ">= 1.0, < 1.4 || >= 2.0". A version without an operator must match
+// exactly.
+func parseVersionRange(constraint string) ([][]versionConstraint, error) {
+	ret := [][]versionConstraint{}
+	for _, group := range strings.Split(constraint, "||") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			return nil, fmt.Errorf("empty version constraint in %q", constraint)
+		}
+
+		constraints := []versionConstraint{}
+		matched := ""
+		for _, m := range versionConstraintRegex.FindAllStringSubmatch(group, -1) {
+			op := m[1]
+			if op == "" || op == "==" {
+				op = "="
+			}
+			constraints = append(constraints, versionConstraint{op: op, version: m[2]})
+			matched += m[0]
+		}
+
+		// Anything left out of the matches is not a valid constraint
+		if strings.NewReplacer(" ", "", ",", "").Replace(group) != strings.ReplaceAll(matched, " ", "") {
+			return nil, fmt.Errorf("invalid version constraint %q", group)
+		}
+		ret = append(ret, constraints)
+	}
+	return ret, nil
+}
+
+// VersionInRange returns true if the node version satisfies the version
+// constraint (see parseVersionRange for the syntax). Versions are compared
+// using the rules of the ecosystem of the node's purl type: apk, alpm, deb
+// and rpm packages follow their package manager ordering and everything else
+// is compared as semver.
+func (n *Node) VersionInRange(constraint string) (bool, error) {
+	if n.Version == "" {
+		return false, errors.New("node has no version")
+	}
+
+	groups, err := parseVersionRange(constraint)
+	if err != nil {
+		return false, fmt.Errorf("parsing version range: %w", err)
+	}
+
+	compare := comparatorForPurlType(n.Purl().Type())
+	for _, group := range groups {
+		if versionSatisfies(compare, n.Version, group) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// versionSatisfies returns true if version satisfies all the constraints
+func versionSatisfies(compare versionComparator, version string, constraints []versionConstraint) bool {
+	for _, c := range constraints {
+		res := compare(version, c.version)
+		var ok bool
+		switch c.op {
+		case "<":
+			ok = res < 0
+		case "<=":
+			ok = res <= 0
+		case ">":
+			ok = res > 0
+		case ">=":
+			ok = res >= 0
+		case "!=":
+			ok = res != 0
+		default:
+			ok = res == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareInts returns the sign of a - b
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareSemver compares two semantic versions. Versions are compared
+// leniently: a leading "v" is ignored, missing components count as zero
+// and non numeric components are compared as strings.
+func compareSemver(v1, v2 string) int {
+	split := func(v string) (release, pre string) {
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+		v, _, _ = strings.Cut(v, "+")
+		release, pre, _ = strings.Cut(v, "-")
+		return release, pre
+	}
+
+	r1, p1 := split(v1)
+	r2, p2 := split(v2)
+	if res := compareDotted(r1, r2); res != 0 {
+		return res
+	}
+
+	// A version without pre-release has precedence
+	switch {
+	case p1 == p2:
+		return 0
+	case p1 == "":
+		return 1
+	case p2 == "":
+		return -1
+	}
+	return compareDotted(p1, p2)
+}
+
+// compareDotted compares two dot separated lists of identifiers. Numeric
+// identifiers are compared numerically and have lower precedence than
+// alphanumeric ones.
+func compareDotted(v1, v2 string) int {
+	f1, f2 := strings.Split(v1, "."), strings.Split(v2, ".")
+	for i := 0; i < len(f1) || i < len(f2); i++ {
+		c1, c2 := "0", "0"
+		if i < len(f1) {
+			c1 = f1[i]
+		}
+		if i < len(f2) {
+			c2 = f2[i]
+		}
+
+		n1, err1 := strconv.Atoi(c1)
+		n2, err2 := strconv.Atoi(c2)
+		switch {
+		case err1 == nil && err2 == nil:
+			if res := compareInts(n1, n2); res != 0 {
+				return res
+			}
+		case err1 == nil:
+			return -1
+		case err2 == nil:
+			return 1
+		default:
+			if res := strings.Compare(c1, c2); res != 0 {
+				return res
+			}
+		}
+	}
+	return 0
+}
+
+// splitEpoch splits the epoch from a deb or rpm version
+func splitEpoch(v string) (int, string) {
+	if e, rest, ok := strings.Cut(v, ":"); ok {
+		if epoch, err := strconv.Atoi(e); err == nil {
+			return epoch, rest
+		}
+	}
+	return 0, v
+}
+
+// compareDebian compares two versions following the dpkg rules
+// Ref: https://www.debian.org/doc/debian-policy/ch-controlfields.html#version
+func compareDebian(v1, v2 string) int {
+	e1, rest1 := splitEpoch(v1)
+	e2, rest2 := splitEpoch(v2)
+	if res := compareInts(e1, e2); res != 0 {
+		return res
+	}
+
+	split := func(v string) (upstream, revision string) {
+		if i := strings.LastIndex(v, "-"); i != -1 {
+			return v[:i], v[i+1:]
+		}
+		return v, ""
+	}
+	u1, r1 := split(rest1)
+	u2, r2 := split(rest2)
+	if res := compareDebianPart(u1, u2); res != 0 {
+		return res
+	}
+	return compareDebianPart(r1, r2)
+}
+
+// debianOrder returns the weight of a character in a version: tildes sort
+// before anything, even the end of the string, and letters before symbols
+func debianOrder(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+// compareDebianPart compares an upstream version or debian revision
+func compareDebianPart(a, b string) int {
+	isDigit := func(s string, i int) bool { return i < len(s) && s[i] >= '0' && s[i] <= '9' }
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		// Compare the non digit prefixes
+		for (i < len(a) && !isDigit(a, i)) || (j < len(b) && !isDigit(b, j)) {
+			ac, bc := 0, 0
+			if i < len(a) {
+				ac = debianOrder(a[i])
+			}
+			if j < len(b) {
+				bc = debianOrder(b[j])
+			}
+			if ac != bc {
+				return compareInts(ac, bc)
+			}
+			i++
+			j++
+		}
+
+		// Then the numeric parts
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for isDigit(a, i) && isDigit(b, j) {
+			if firstDiff == 0 {
+				firstDiff = compareInts(int(a[i]), int(b[j]))
+			}
+			i++
+			j++
+		}
+		if isDigit(a, i) {
+			return 1
+		}
+		if isDigit(b, j) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+// compareRPM compares two [epoch:]version[-release] strings following the
+// rpm rules. The release is only compared when both versions have one.
+func compareRPM(v1, v2 string) int {
+	e1, rest1 := splitEpoch(v1)
+	e2, rest2 := splitEpoch(v2)
+	if res := compareInts(e1, e2); res != 0 {
+		return res
+	}
+
+	ver1, rel1, hasRel1 := strings.Cut(rest1, "-")
+	ver2, rel2, hasRel2 := strings.Cut(rest2, "-")
+	if res := rpmvercmp(ver1, ver2); res != 0 || !hasRel1 || !hasRel2 {
+		return res
+	}
+	return rpmvercmp(rel1, rel2)
+}
+
+// rpmvercmp is a port of the rpm segment comparison algorithm
+// Ref: https://github.com/rpm-software-management/rpm/blob/master/rpmio/rpmvercmp.c
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	isAlnum := func(c byte) bool {
+		return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+
+	for len(a) > 0 || len(b) > 0 {
+		for len(a) > 0 && !isAlnum(a[0]) && a[0] != '~' && a[0] != '^' {
+			a = a[1:]
+		}
+		for len(b) > 0 && !isAlnum(b[0]) && b[0] != '~' && b[0] != '^' {
+			b = b[1:]
+		}
+
+		// Tildes sort before everything
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// Carets sort after the end of the string but before anything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		// Take the next segment of the same kind from both strings
+		numeric := isDigit(a[0])
+		segment := func(s string) (string, string) {
+			i := 0
+			for i < len(s) && isAlnum(s[i]) && isDigit(s[i]) == numeric {
+				i++
+			}
+			return s[:i], s[i:]
+		}
+		var s1, s2 string
+		s1, a = segment(a)
+		s2, b = segment(b)
+
+		// Numeric segments are newer than alpha ones
+		if s2 == "" {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			s1 = strings.TrimLeft(s1, "0")
+			s2 = strings.TrimLeft(s2, "0")
+			if res := compareInts(len(s1), len(s2)); res != 0 {
+				return res
+			}
+		}
+		if res := strings.Compare(s1, s2); res != 0 {
+			return res
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// apkSuffixes ranks the apk version suffixes. Pre-release suffixes sort
+// before a version with no suffix (rank 0) and the rest after it.
+var apkSuffixes = map[string]int{
+	"alpha": -4,
+	"beta":  -3,
+	"pre":   -2,
+	"rc":    -1,
+	"cvs":   1,
+	"svn":   2,
+	"git":   3,
+	"hg":    4,
+	"p":     5,
+}
+
+// apkVersion is a parsed apk version:
+// digits{.digits}[letter]{_suffix[digits]}[-rrevision]
+type apkVersion struct {
+	numbers  []int
+	letter   byte
+	suffixes [][2]int // rank and number of each suffix
+	revision int
+}
+
+var apkVersionRegex = regexp.MustCompile(`^(\d+(?:\.\d+)*)([a-z]?)((?:_[a-z]+\d*)*)(?:-r(\d+))?$`)
+var apkSuffixRegex = regexp.MustCompile(`_([a-z]+)(\d*)`)
+
+// parseAPKVersion parses an apk version string, returning false if it is
+// not a valid apk version
+func parseAPKVersion(v string) (*apkVersion, bool) {
+	m := apkVersionRegex.FindStringSubmatch(v)
+	if m == nil {
+		return nil, false
+	}
+
+	ret := &apkVersion{}
+	for _, f := range strings.Split(m[1], ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, false
+		}
+		ret.numbers = append(ret.numbers, n)
+	}
+	if m[2] != "" {
+		ret.letter = m[2][0]
+	}
+	for _, sm := range apkSuffixRegex.FindAllStringSubmatch(m[3], -1) {
+		rank, ok := apkSuffixes[sm[1]]
+		if !ok {
+			return nil, false
+		}
+		n := 0
+		if sm[2] != "" {
+			n, _ = strconv.Atoi(sm[2]) //nolint:errcheck // Only digits are matched
+		}
+		ret.suffixes = append(ret.suffixes, [2]int{rank, n})
+	}
+	if m[4] != "" {
+		ret.revision, _ = strconv.Atoi(m[4]) //nolint:errcheck // Only digits are matched
+	}
+	return ret, true
+}
+
+// compareAPK compares two Alpine package versions. The -rN package
+// revision sorts after the upstream version it is built from, so 3.0.7-r1
+// is newer than 3.0.7. Invalid versions are compared as semver.
+// Ref: https://gitlab.alpinelinux.org/alpine/apk-tools/-/blob/master/src/version.c
+func compareAPK(v1, v2 string) int {
+	a, ok1 := parseAPKVersion(v1)
+	b, ok2 := parseAPKVersion(v2)
+	if !ok1 || !ok2 {
+		return compareSemver(v1, v2)
+	}
+
+	for i := 0; i < len(a.numbers) && i < len(b.numbers); i++ {
+		if res := compareInts(a.numbers[i], b.numbers[i]); res != 0 {
+			return res
+		}
+	}
+	if res := compareInts(len(a.numbers), len(b.numbers)); res != 0 {
+		return res
+	}
+
+	if res := compareInts(int(a.letter), int(b.letter)); res != 0 {
+		return res
+	}
+
+	for i := 0; i < len(a.suffixes) || i < len(b.suffixes); i++ {
+		var s1, s2 [2]int
+		if i < len(a.suffixes) {
+			s1 = a.suffixes[i]
+		}
+		if i < len(b.suffixes) {
+			s2 = b.suffixes[i]
+		}
+		if res := compareInts(s1[0], s2[0]); res != 0 {
+			return res
+		}
+		if res := compareInts(s1[1], s2[1]); res != 0 {
+			return res
+		}
+	}
+
+	return compareInts(a.revision, b.revision)
+}
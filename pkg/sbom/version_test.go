@@ -0,0 +1,84 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionInRange(t *testing.T) {
+	for _, tc := range []struct {
+		purl       string
+		version    string
+		constraint string
+		expected   bool
+		shouldErr  bool
+	}{
+		{"pkg:generic/openssl@3.0.6", "3.0.6", "< 3.0.7", true, false},
+		{"pkg:generic/openssl@3.0.7", "3.0.7", "< 3.0.7", false, false},
+		{"", "v1.2.0", ">=1.0, <1.4", true, false},
+		{"", "1.10.0", "> 1.9", true, false},
+		{"", "2.0.0-rc.1", "< 2.0.0", true, false},
+		{"", "2.0.0-rc.2", "> 2.0.0-rc.1", true, false},
+		{"", "1.5.0", "< 1.4 || >= 2.0", false, false},
+		{"", "2.1.0", "< 1.4 || >= 2.0", true, false},
+		{"", "1.0.0", "1.0", true, false},
+		{"", "1.0.0", "!= 1.0.0", false, false},
+		// Debian: tildes sort before the release, epochs win
+		{"pkg:deb/debian/openssl@3.0.7~rc1-1", "3.0.7~rc1-1", "< 3.0.7-1", true, false},
+		{"pkg:deb/debian/openssl@1:1.0-1", "1:1.0-1", "> 2.0-1", true, false},
+		{"pkg:deb/debian/openssl@3.0.11-1~deb12u2", "3.0.11-1~deb12u2", "< 3.0.11-1", true, false},
+		{"pkg:deb/debian/openssl@3.0.11-1+deb12u2", "3.0.11-1+deb12u2", "> 3.0.11-1", true, false},
+		// RPM: alpha segments are older than numeric ones
+		{"pkg:rpm/fedora/openssl@3.0.7-1.fc38", "3.0.7-1.fc38", ">= 3.0.7", true, false},
+		{"pkg:rpm/fedora/openssl@3.0.a-1", "3.0.a-1", "< 3.0.1-1", true, false},
+		{"pkg:rpm/fedora/openssl@3.0.7~beta-1", "3.0.7~beta-1", "< 3.0.7-1", true, false},
+		{"pkg:rpm/fedora/openssl@3.0.7^git1-1", "3.0.7^git1-1", "> 3.0.7-1", true, false},
+		// apk: the -r revision sorts after the upstream version
+		{"pkg:apk/alpine/openssl@3.0.7-r1", "3.0.7-r1", "> 3.0.7", true, false},
+		{"pkg:apk/alpine/openssl@3.0.7-r1", "3.0.7-r1", "< 3.0.7-r10", true, false},
+		{"pkg:apk/alpine/openssl@3.0.7-r2", "3.0.7-r2", ">= 3.0.7-r1, < 3.0.8-r0", true, false},
+		{"pkg:apk/alpine/openssl@3.0.7-r0", "3.0.7-r0", "= 3.0.7", true, false},
+		{"pkg:apk/alpine/busybox@1.36.1_rc1-r0", "1.36.1_rc1-r0", "< 1.36.1-r0", true, false},
+		{"pkg:apk/alpine/busybox@1.36.1_p2-r0", "1.36.1_p2-r0", "> 1.36.1-r5", true, false},
+		{"pkg:apk/alpine/openssl@1.1.1t-r0", "1.1.1t-r0", "> 1.1.1s-r3", true, false},
+		{"pkg:apk/alpine/zlib@1.2.13-r1", "1.2.13-r1", "> 1.2.9-r1", true, false},
+		// alpm uses the rpm ordering
+		{"pkg:alpm/arch/openssl@3.0.7-2", "3.0.7-2", "> 3.0.7-1", true, false},
+		// Errors
+		{"", "", "< 1.0", false, true},
+		{"", "1.0", "", false, true},
+		{"", "1.0", "< 1.0 |", false, true},
+		{"", "1.0", "<> 1.0", false, true},
+	} {
+		n := &Node{Id: "test", Version: tc.version, Identifiers: map[int32]string{}}
+		if tc.purl != "" {
+			n.Identifiers[int32(SoftwareIdentifierType_PURL)] = tc.purl
+		}
+		res, err := n.VersionInRange(tc.constraint)
+		if tc.shouldErr {
+			require.Error(t, err, "%s %s", tc.version, tc.constraint)
+			continue
+		}
+		require.NoError(t, err, "%s %s", tc.version, tc.constraint)
+		require.Equal(t, tc.expected, res, "%s %s", tc.version, tc.constraint)
+	}
+}
+
+func TestGetNodesInVersionRange(t *testing.T) {
+	nl := &NodeList{
+		Nodes: []*Node{
+			{Id: "a", Name: "openssl", Version: "3.0.6"},
+			{Id: "b", Name: "openssl", Version: "3.0.10"},
+			{Id: "c", Name: "openssl"},
+			{Id: "d", Name: "libssl", Version: "1.0.0"},
+		},
+	}
+	res, err := nl.GetNodesInVersionRange("openssl", "< 3.0.7")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, "a", res[0].Id)
+
+	_, err = nl.GetNodesInVersionRange("openssl", "<")
+	require.Error(t, err)
+}
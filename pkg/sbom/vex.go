@@ -0,0 +1,255 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrUnmatchedVEXStatements is returned by ApplyVEX when some statements
+// of the VEX document did not match any node
+var ErrUnmatchedVEXStatements = errors.New("VEX statements did not match any node")
+
+// VEXReport describes the outcome of applying a VEX document
+type VEXReport struct {
+	// Applied maps the IDs of the annotated nodes to the vulnerabilities
+	// set on them
+	Applied map[string][]string
+
+	// Unmatched lists the products of the statements that did not match
+	// any node in the document
+	Unmatched []UnmatchedVEXProduct
+}
+
+// UnmatchedVEXProduct is a product of a VEX statement not found in the
+// document
+type UnmatchedVEXProduct struct {
+	Vulnerability string
+	Product       string
+}
+
+// openVEXDocument is the subset of an OpenVEX document read by ApplyVEX.
+// Ref: https://github.com/openvex/spec/blob/main/OPENVEX-SPEC.md
+type openVEXDocument struct {
+	Context    string             `json:"@context"`
+	Statements []openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	// Vulnerability is a string in OpenVEX 0.0.x and an object since 0.2.0
+	Vulnerability   json.RawMessage   `json:"vulnerability"`
+	Products        []json.RawMessage `json:"products"`
+	Status          string            `json:"status"`
+	Justification   string            `json:"justification"`
+	ImpactStatement string            `json:"impact_statement"`
+	ActionStatement string            `json:"action_statement"`
+	StatusNotes     string            `json:"status_notes"`
+}
+
+// openVEXComponent is a product or subcomponent of a statement. In OpenVEX
+// 0.0.x products are plain strings.
+type openVEXComponent struct {
+	ID            string              `json:"@id"`
+	Identifiers   map[string]string   `json:"identifiers"`
+	Subcomponents []*openVEXComponent `json:"subcomponents"`
+}
+
+// openVEXStatuses maps the OpenVEX statuses to vulnerability statuses
+var openVEXStatuses = map[string]Vulnerability_Status{
+	"not_affected":        Vulnerability_NOT_AFFECTED,
+	"affected":            Vulnerability_AFFECTED,
+	"fixed":               Vulnerability_FIXED,
+	"under_investigation": Vulnerability_UNDER_INVESTIGATION,
+}
+
+// ApplyVEX reads an OpenVEX document and records the status of its
+// statements in the vulnerabilities of the matching nodes. If some
+// statements do not match any node, the rest are still applied and an
+// error wrapping ErrUnmatchedVEXStatements is returned. Use
+// ApplyVEXWithReport to get the list of unmatched products.
+func (d *Document) ApplyVEX(vex io.Reader) error {
+	report, err := d.ApplyVEXWithReport(vex)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Unmatched) > 0 {
+		products := []string{}
+		for _, u := range report.Unmatched {
+			products = append(products, fmt.Sprintf("%s (%s)", u.Product, u.Vulnerability))
+		}
+		return fmt.Errorf("%w: %s", ErrUnmatchedVEXStatements, strings.Join(products, ", "))
+	}
+	return nil
+}
+
+// ApplyVEXWithReport works as ApplyVEX but returns a report of the nodes
+// annotated and the products that were not found instead of failing on
+// unmatched statements.
+//
+// Products are matched to nodes by their purl (compared in canonical form)
+// or CPE, read from the product @id or its identifiers. When a product lists
+// subcomponents, the statement applies to the subcomponents instead. A
+// vulnerability already recorded in a node is replaced by the statement.
+func (d *Document) ApplyVEXWithReport(vex io.Reader) (*VEXReport, error) {
+	if d.NodeList == nil {
+		return nil, errors.New("document has no nodelist")
+	}
+
+	doc := &openVEXDocument{}
+	if err := json.NewDecoder(vex).Decode(doc); err != nil {
+		return nil, fmt.Errorf("decoding OpenVEX document: %w", err)
+	}
+	if !strings.HasPrefix(doc.Context, "https://openvex.dev/ns") {
+		return nil, fmt.Errorf("document context %q is not OpenVEX", doc.Context)
+	}
+
+	report := &VEXReport{
+		Applied:   map[string][]string{},
+		Unmatched: []UnmatchedVEXProduct{},
+	}
+	purlIndex := d.NodeList.IndexByPurl()
+	cpeIndex := d.NodeList.indexNodesByCPE()
+
+	for i, s := range doc.Statements {
+		vuln, err := s.vulnerability()
+		if err != nil {
+			return nil, fmt.Errorf("reading statement #%d: %w", i, err)
+		}
+
+		for _, p := range s.Products {
+			product, err := parseOpenVEXComponent(p)
+			if err != nil {
+				return nil, fmt.Errorf("reading statement #%d products: %w", i, err)
+			}
+
+			targets := product.Subcomponents
+			if len(targets) == 0 {
+				targets = []*openVEXComponent{product}
+			}
+
+			for _, target := range targets {
+				nodes := target.matchNodes(purlIndex, cpeIndex)
+				if len(nodes) == 0 {
+					report.Unmatched = append(report.Unmatched, UnmatchedVEXProduct{
+						Vulnerability: vuln.Id,
+						Product:       target.ID,
+					})
+					continue
+				}
+				for _, n := range nodes {
+					n.setVulnerability(vuln)
+					report.Applied[n.Id] = append(report.Applied[n.Id], vuln.Id)
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// vulnerability returns the vulnerability described by the statement
+func (s *openVEXStatement) vulnerability() (*Vulnerability, error) {
+	status, ok := openVEXStatuses[s.Status]
+	if !ok {
+		return nil, fmt.Errorf("unknown VEX status %q", s.Status)
+	}
+
+	var name string
+	if err := json.Unmarshal(s.Vulnerability, &name); err != nil {
+		v := struct {
+			Name string `json:"name"`
+			ID   string `json:"@id"`
+		}{}
+		if err := json.Unmarshal(s.Vulnerability, &v); err != nil {
+			return nil, fmt.Errorf("invalid vulnerability: %w", err)
+		}
+		name = v.Name
+		if name == "" {
+			name = v.ID
+		}
+	}
+	if name == "" {
+		return nil, errors.New("statement has no vulnerability")
+	}
+
+	detail := s.ImpactStatement
+	if detail == "" {
+		detail = s.ActionStatement
+	}
+	if detail == "" {
+		detail = s.StatusNotes
+	}
+
+	return &Vulnerability{
+		Id:            name,
+		Status:        status,
+		Justification: s.Justification,
+		Detail:        detail,
+	}, nil
+}
+
+// parseOpenVEXComponent reads a product, which can be a string in older
+// versions of OpenVEX
+func parseOpenVEXComponent(data json.RawMessage) (*openVEXComponent, error) {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		return &openVEXComponent{ID: id}, nil
+	}
+
+	c := &openVEXComponent{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// matchNodes returns the nodes that match the component purl or CPE
+func (c *openVEXComponent) matchNodes(purlIndex map[string][]*Node, cpes cpeIndex) []*Node {
+	ids := []string{c.ID}
+	for _, t := range []string{"purl", "cpe23", "cpe22"} {
+		if id, ok := c.Identifiers[t]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	ret := []*Node{}
+	seen := map[*Node]struct{}{}
+	for _, id := range ids {
+		var found []*Node
+		switch {
+		case strings.HasPrefix(id, "pkg:"):
+			found = purlIndex[string(PackageURL(id).Canonical())]
+		case strings.HasPrefix(id, "cpe:2.3:"):
+			found = cpes[normalizeCPE23(id)]
+		case strings.HasPrefix(id, "cpe:/"):
+			if cpe, err := CPE22to23(id); err == nil {
+				found = cpes[normalizeCPE23(cpe)]
+			}
+		}
+		for _, n := range found {
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				ret = append(ret, n)
+			}
+		}
+	}
+	return ret
+}
+
+// setVulnerability records a copy of the vulnerability in the node,
+// replacing the entry with the same ID if there is one. Nodes do not share
+// the record so modifying one does not change the others.
+func (n *Node) setVulnerability(v *Vulnerability) {
+	v = proto.Clone(v).(*Vulnerability)
+	for i, existing := range n.Vulnerabilities {
+		if existing.Id == v.Id {
+			n.Vulnerabilities[i] = v
+			return
+		}
+	}
+	n.Vulnerabilities = append(n.Vulnerabilities, v)
+}
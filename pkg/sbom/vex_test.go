@@ -0,0 +1,184 @@
+package sbom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func vexTestDocument() *Document {
+	return &Document{
+		NodeList: &NodeList{
+			Nodes: []*Node{
+				{
+					Id:          "app",
+					Name:        "app",
+					Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:oci/app@sha256%3A1234"},
+				},
+				{
+					Id:          "logrus",
+					Name:        "logrus",
+					Identifiers: map[int32]string{int32(SoftwareIdentifierType_PURL): "pkg:golang/github.com/sirupsen/logrus@v1.9.0"},
+				},
+				{
+					Id:   "openssl",
+					Name: "openssl",
+					Identifiers: map[int32]string{
+						int32(SoftwareIdentifierType_CPE23): "cpe:2.3:a:openssl:openssl:3.0.7:*:*:*:*:*:*:*",
+					},
+					Vulnerabilities: []*Vulnerability{{Id: "CVE-2023-0286", Status: Vulnerability_UNDER_INVESTIGATION}},
+				},
+			},
+			RootElements: []string{"app"},
+		},
+	}
+}
+
+func TestApplyVEX(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		vex       string
+		expected  map[string]*Vulnerability
+		unmatched []UnmatchedVEXProduct
+		shouldErr bool
+	}{
+		{
+			name: "not_affected by purl",
+			vex: `{
+				"@context": "https://openvex.dev/ns/v0.2.0",
+				"statements": [{
+					"vulnerability": {"name": "CVE-2023-1234"},
+					"products": [{"@id": "pkg:golang/github.com/sirupsen/logrus@v1.9.0"}],
+					"status": "not_affected",
+					"justification": "vulnerable_code_not_in_execute_path",
+					"impact_statement": "The affected function is not used"
+				}]
+			}`,
+			expected: map[string]*Vulnerability{
+				"logrus": {
+					Id:            "CVE-2023-1234",
+					Status:        Vulnerability_NOT_AFFECTED,
+					Justification: "vulnerable_code_not_in_execute_path",
+					Detail:        "The affected function is not used",
+				},
+			},
+		},
+		{
+			name: "subcomponents by CPE replace existing status",
+			vex: `{
+				"@context": "https://openvex.dev/ns/v0.2.0",
+				"statements": [{
+					"vulnerability": {"name": "CVE-2023-0286"},
+					"products": [{
+						"@id": "pkg:oci/app@sha256:1234",
+						"subcomponents": [{"@id": "cpe:/a:openssl:openssl:3.0.7"}]
+					}],
+					"status": "fixed"
+				}]
+			}`,
+			expected: map[string]*Vulnerability{
+				"openssl": {Id: "CVE-2023-0286", Status: Vulnerability_FIXED},
+			},
+		},
+		{
+			name: "legacy string products and identifiers",
+			vex: `{
+				"@context": "https://openvex.dev/ns",
+				"statements": [{
+					"vulnerability": "CVE-2023-5678",
+					"products": [
+						"pkg:golang/github.com/sirupsen/logrus@v1.9.0",
+						{"@id": "custom-id", "identifiers": {"cpe23": "cpe:2.3:a:OpenSSL:openssl:3.0.7:*:*:*:*:*:*:*"}}
+					],
+					"status": "affected",
+					"action_statement": "Update to the next release"
+				}]
+			}`,
+			expected: map[string]*Vulnerability{
+				"logrus":  {Id: "CVE-2023-5678", Status: Vulnerability_AFFECTED, Detail: "Update to the next release"},
+				"openssl": {Id: "CVE-2023-5678", Status: Vulnerability_AFFECTED, Detail: "Update to the next release"},
+			},
+		},
+		{
+			name: "unmatched products are reported",
+			vex: `{
+				"@context": "https://openvex.dev/ns/v0.2.0",
+				"statements": [{
+					"vulnerability": {"name": "CVE-2023-1234"},
+					"products": [
+						{"@id": "pkg:golang/github.com/sirupsen/logrus@v1.9.0"},
+						{"@id": "pkg:npm/left-pad@1.3.0"}
+					],
+					"status": "under_investigation"
+				}]
+			}`,
+			expected: map[string]*Vulnerability{
+				"logrus": {Id: "CVE-2023-1234", Status: Vulnerability_UNDER_INVESTIGATION},
+			},
+			unmatched: []UnmatchedVEXProduct{{Vulnerability: "CVE-2023-1234", Product: "pkg:npm/left-pad@1.3.0"}},
+		},
+		{
+			name:      "not openvex",
+			vex:       `{"@context": "https://example.com", "statements": []}`,
+			shouldErr: true,
+		},
+		{
+			name: "unknown status",
+			vex: `{
+				"@context": "https://openvex.dev/ns/v0.2.0",
+				"statements": [{"vulnerability": {"name": "CVE-1"}, "products": [], "status": "maybe"}]
+			}`,
+			shouldErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := vexTestDocument()
+			report, err := doc.ApplyVEXWithReport(strings.NewReader(tc.vex))
+			if tc.shouldErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.unmatched == nil {
+				tc.unmatched = []UnmatchedVEXProduct{}
+			}
+			require.Equal(t, tc.unmatched, report.Unmatched)
+			require.Len(t, report.Applied, len(tc.expected))
+
+			for id, vuln := range tc.expected {
+				node := doc.NodeList.GetNodeByID(id)
+				require.NotNil(t, node)
+				var got *Vulnerability
+				for _, v := range node.Vulnerabilities {
+					if v.Id == vuln.Id {
+						require.Nil(t, got, "vulnerability %s recorded twice", v.Id)
+						got = v
+					}
+				}
+				require.NotNil(t, got)
+				require.Equal(t, vuln.Status, got.Status)
+				require.Equal(t, vuln.Justification, got.Justification)
+				require.Equal(t, vuln.Detail, got.Detail)
+			}
+
+			// Each node gets its own copy of the vulnerability
+			if len(tc.expected) > 1 {
+				vulns := map[*Vulnerability]struct{}{}
+				for id := range tc.expected {
+					for _, v := range doc.NodeList.GetNodeByID(id).Vulnerabilities {
+						_, shared := vulns[v]
+						require.False(t, shared, "vulnerability %s shared between nodes", v.Id)
+						vulns[v] = struct{}{}
+					}
+				}
+			}
+
+			// ApplyVEX fails when statements are left unmatched
+			err = vexTestDocument().ApplyVEX(strings.NewReader(tc.vex))
+			require.Equal(t, len(tc.unmatched) > 0, errors.Is(err, ErrUnmatchedVEXStatements))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package sbom
+
+import (
+	"fmt"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// ToCycloneDX returns the CycloneDX impact analysis state that corresponds
+// to the vulnerability status
+func (s Vulnerability_Status) ToCycloneDX() cdx.ImpactAnalysisState {
+	switch s {
+	case Vulnerability_AFFECTED:
+		return cdx.IASExploitable
+	case Vulnerability_NOT_AFFECTED:
+		return cdx.IASNotAffected
+	case Vulnerability_FIXED:
+		return cdx.IASResolved
+	case Vulnerability_UNDER_INVESTIGATION:
+		return cdx.IASInTriage
+	default:
+		return ""
+	}
+}
+
+// VulnerabilityStatusFromCycloneDX returns the vulnerability status that
+// corresponds to a CycloneDX impact analysis state
+func VulnerabilityStatusFromCycloneDX(state cdx.ImpactAnalysisState) Vulnerability_Status {
+	switch state {
+	case cdx.IASExploitable:
+		return Vulnerability_AFFECTED
+	case cdx.IASNotAffected, cdx.IASFalsePositive:
+		// TODO(degradation): False positives are recorded as not affected
+		return Vulnerability_NOT_AFFECTED
+	case cdx.IASResolved, cdx.IASResolvedWithPedigree:
+		return Vulnerability_FIXED
+	case cdx.IASInTriage:
+		return Vulnerability_UNDER_INVESTIGATION
+	default:
+		return Vulnerability_UNKNOWN
+	}
+}
+
+// flatString returns a deterministic string that can be used to hash the vulnerability
+func (v *Vulnerability) flatString() string {
+	ret := fmt.Sprintf("(i)%s(s)%s", v.Id, v.Status)
+	if v.Justification != "" {
+		ret += fmt.Sprintf("(j)%s", v.Justification)
+	}
+	if v.Detail != "" {
+		ret += fmt.Sprintf("(d)%s", v.Detail)
+	}
+	return ret
+}
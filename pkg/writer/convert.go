@@ -0,0 +1,43 @@
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+)
+
+// Convert reads a document in format from from the in reader and writes it
+// in format to to out. The document goes through the protobom model so the
+// conversion is as faithful as the model and the target format allow. The
+// known losses are:
+//
+//   - CycloneDX to SPDX: the serial number (SPDX documents get a new
+//     namespace), tool vendors, the URL, phone and contacts of authors and
+//     hashes of algorithms SPDX 2.3 does not support.
+//   - SPDX to CycloneDX: the document ID (it is not a valid serial number
+//     so a new urn:uuid is generated), the document name, the license list
+//     version, hashes of algorithms CycloneDX does not support (eg SHA224)
+//     and relationship types with no CycloneDX equivalent, only contains
+//     and dependsOn edges are written.
+//
+// Use RoundTripReport to inspect the fields a particular document loses.
+func Convert(in io.Reader, from, to formats.Format, out io.Writer) error {
+	if in == nil || out == nil {
+		return errors.New("conversion needs an input and an output stream")
+	}
+
+	doc, err := reader.DocumentFromReader(in, from)
+	if err != nil {
+		return fmt.Errorf("reading %s document: %w", from, err)
+	}
+
+	w := New()
+	w.Options.Format = to
+	if err := w.WriteStream(doc, nopWriteCloser{out}); err != nil {
+		return fmt.Errorf("writing %s document: %w", to, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+)
+
+func TestConvert(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		path string
+		from formats.Format
+		to   formats.Format
+	}{
+		{"cdx-to-spdx", "../formats/testdata/juice-shop-11.1.2.cdx.json", formats.CDX14JSON, formats.SPDX23JSON},
+		{"spdx-to-cdx", "../formats/testdata/nginx.spdx.json", formats.SPDX23JSON, formats.CDX14JSON},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := os.ReadFile(tc.path)
+			require.NoError(t, err)
+			orig, err := reader.DocumentFromReader(bytes.NewReader(data), tc.from)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			require.NoError(t, Convert(bytes.NewReader(data), tc.from, tc.to, &out))
+
+			converted, err := reader.DocumentFromReader(bytes.NewReader(out.Bytes()), tc.to)
+			require.NoError(t, err)
+			require.NotEmpty(t, converted.NodeList.Nodes)
+			for _, n := range orig.NodeList.RootNodes() {
+				require.NotEmpty(t, converted.NodeList.GetNodesByName(n.Name), n.Name)
+			}
+		})
+	}
+
+	// SPDX document IDs are not valid serial numbers, the CycloneDX output
+	// gets a new one and a valid version
+	data, err := os.ReadFile("../formats/testdata/nginx.spdx.json")
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, Convert(bytes.NewReader(data), formats.SPDX23JSON, formats.CDX14JSON, &out))
+	bom := struct {
+		SerialNumber string `json:"serialNumber"`
+		Version      int    `json:"version"`
+	}{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &bom))
+	require.Regexp(t, regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), bom.SerialNumber)
+	require.GreaterOrEqual(t, bom.Version, 1)
+
+	// Mismatched formats fail
+	require.Error(t, Convert(bytes.NewReader(data), formats.CDX14JSON, formats.SPDX23JSON, &bytes.Buffer{}))
+}
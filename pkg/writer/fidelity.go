@@ -0,0 +1,71 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+// FidelityReport describes the data lost when a document is written to a
+// format and read back
+type FidelityReport struct {
+	Format formats.Format
+
+	// Diff has the differences between the original document's NodeList
+	// and the one read back from the format
+	Diff *sbom.NodeListDiff
+
+	// LostFields counts the nodes that lost each field in the round trip
+	LostFields map[string]int
+}
+
+// Lossless returns true if the document survived the round trip unchanged
+func (r *FidelityReport) Lossless() bool {
+	return r.Diff.Empty()
+}
+
+// nopWriteCloser adds a noop Close method to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// RoundTripReport writes the document to format f, parses it back and
+// reports the differences between the original and the parsed document.
+// The document is not modified.
+func RoundTripReport(doc *sbom.Document, f formats.Format) (*FidelityReport, error) {
+	if doc == nil || doc.NodeList == nil {
+		return nil, errors.New("document has no nodelist")
+	}
+
+	w := New()
+	w.Options.Format = f
+
+	var buf bytes.Buffer
+	if err := w.WriteStream(doc, nopWriteCloser{&buf}); err != nil {
+		return nil, fmt.Errorf("writing document to %s: %w", f, err)
+	}
+
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s document: %w", f, err)
+	}
+
+	report := &FidelityReport{
+		Format:     f,
+		Diff:       doc.NodeList.Diff(doc2.NodeList),
+		LostFields: map[string]int{},
+	}
+	for _, d := range report.Diff.NodesChanged {
+		for _, field := range d.Removed {
+			report.LostFields[field]++
+		}
+	}
+	return report, nil
+}
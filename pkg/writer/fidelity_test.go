@@ -0,0 +1,35 @@
+package writer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+func TestRoundTripReport(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Nodes[0].Hashes = map[string]string{
+		sbom.HashAlgorithm_BLAKE3.String(): "8f3de7f4bb0c7d1a2b4cfa5ed70d97a2a6ea8f54cd2f6a2b6d2a6a5e7e5e0c31",
+		sbom.HashAlgorithm_SHA224.String(): "d14a028c2a3a2bc9476102bb288234c415a2b01f828ea62ac5b3e42f",
+	}
+
+	// SPDX 2.3 supports both algorithms
+	report, err := RoundTripReport(doc, formats.SPDX23JSON)
+	require.NoError(t, err)
+	require.Equal(t, formats.SPDX23JSON, report.Format)
+	require.Zero(t, report.LostFields["hashes[BLAKE3]"])
+	require.Zero(t, report.LostFields["hashes[SHA224]"])
+
+	// SHA224 has no CycloneDX equivalent and is lost
+	report, err = RoundTripReport(doc, formats.CDX14JSON)
+	require.NoError(t, err)
+	require.False(t, report.Lossless())
+	require.Equal(t, 1, report.LostFields["hashes[SHA224]"])
+	require.Zero(t, report.LostFields["hashes[BLAKE3]"])
+
+	// The original document is not modified
+	require.Len(t, doc.NodeList.Nodes[0].Hashes, 2)
+}
@@ -1,6 +1,7 @@
 package writer
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -13,7 +14,7 @@ import (
 
 type writerImplementation interface {
 	GetFormatSerializer(formats.Format) (Serializer, error)
-	SerializeSBOM(options.Options, Serializer, *sbom.Document, io.WriteCloser) error
+	SerializeSBOM(context.Context, options.Options, Serializer, *sbom.Document, io.Writer) error
 	OpenFile(string) (*os.File, error)
 }
 
@@ -27,27 +28,38 @@ func (di *defaultWriterImplementation) GetFormatSerializer(formatOpt formats.For
 	case formats.SPDX23JSON:
 		logrus.Infof("Serializing to %s", formats.SPDX23JSON)
 		return &SerializerSPDX23{}, nil
+	case formats.PROTOBOMJSON:
+		logrus.Infof("Serializing to %s", formats.PROTOBOMJSON)
+		return &SerializerProtobomJSON{}, nil
+	case formats.PROTOBOMNDJSON:
+		logrus.Infof("Serializing to %s", formats.PROTOBOMNDJSON)
+		return &SerializerNDJSON{}, nil
+	case formats.PROTOBOMINVENTORYNDJSON:
+		logrus.Infof("Serializing to %s", formats.PROTOBOMINVENTORYNDJSON)
+		return &SerializerNDJSONInventory{}, nil
 	default:
 		return nil, fmt.Errorf("no serializer supports rendering to %s", formatOpt)
 	}
 }
 
 // SerializeSBOM takes an SBOM in protobuf and a serializer and uses it to render
-// the document into the serializer format.
-func (di *defaultWriterImplementation) SerializeSBOM(opts options.Options, serializer Serializer, bom *sbom.Document, wr io.WriteCloser) error {
-	nativeDoc, err := serializer.Serialize(opts, bom)
+// the document into the serializer format. Writes to wr fail once ctx is
+// canceled so rendering is aborted even in serializers that encode the
+// document in one go.
+func (di *defaultWriterImplementation) SerializeSBOM(ctx context.Context, opts options.Options, serializer Serializer, bom *sbom.Document, wr io.Writer) error {
+	nativeDoc, err := serializeContext(ctx, serializer, opts, bom)
 	if err != nil {
 		return fmt.Errorf("serializing SBOM to native format: %w", err)
 	}
-	if err := serializer.Render(opts, nativeDoc, wr); err != nil {
+	if err := renderContext(ctx, serializer, opts, nativeDoc, &ctxWriter{ctx: ctx, w: wr}); err != nil {
 		return fmt.Errorf("writing rendered document to string: %w", err)
 	}
 	return nil
 }
 
-// OpenFile opens the file at path and returns it
+// OpenFile creates or truncates the file at path and returns it
 func (di *defaultWriterImplementation) OpenFile(path string) (*os.File, error) {
-	f, err := os.Open(path)
+	f, err := os.Create(path)
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
 	}
@@ -1,12 +1,78 @@
 package options
 
 import (
+	cdx "github.com/CycloneDX/cyclonedx-go"
+
 	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+// CDXRootScheme is a function that controls how the root elements of a
+// document are expressed in CycloneDX, which only allows a single component
+// in metadata.component. It receives the root nodes and the document being
+// written and returns the component to use as metadata.component plus any
+// dependencies required to link the rest of the roots to it.
+//
+// The writer package provides the built-in FlatRootScheme and
+// VirtualRootScheme implementations, the latter can be configured with
+// NewVirtualRootScheme.
+type CDXRootScheme func(roots []*sbom.Node, bom *sbom.Document) (*cdx.Component, []cdx.Dependency, error)
+
+// ProgressFunc receives the progress of a serialization
+type ProgressFunc func(done, total int)
+
+// DefaultProgressInterval is the number of nodes serialized between
+// progress reports when the options do not set one
+const DefaultProgressInterval = 1000
+
+// Compression is the compression algorithm applied to the written documents
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
 )
 
 type Options struct {
-	Format formats.Format `yaml:"format,omitempty" json:"format,omitempty"`
-	Indent int            `yaml:"indent,omitempty" json:"indent,omitempty"`
+	Format         formats.Format `yaml:"format,omitempty" json:"format,omitempty"`
+	Indent         int            `yaml:"indent,omitempty" json:"indent,omitempty"`
+	CDXRootScheme  CDXRootScheme  `yaml:"-" json:"-"` // When nil, the flat scheme is used
+	Compression    Compression    `yaml:"compression,omitempty" json:"compression,omitempty"`
+	SPDXNamespace  string         `yaml:"spdxNamespace,omitempty" json:"spdxNamespace,omitempty"` // Must be an absolute URI
+	DocumentName   string         `yaml:"documentName,omitempty" json:"documentName,omitempty"`   // Overrides the name in the metadata
+	SortComponents bool           `yaml:"sortComponents,omitempty" json:"sortComponents,omitempty"`
+
+	// SPDXNamespaceBase is the base URI used to generate the SPDX namespace
+	// as base/name-suffix. Ignored when SPDXNamespace is set.
+	SPDXNamespaceBase string `yaml:"spdxNamespaceBase,omitempty" json:"spdxNamespaceBase,omitempty"`
+
+	// SPDXNamespaceSuffix replaces the random UUID at the end of generated
+	// namespaces, set it to get reproducible documents
+	SPDXNamespaceSuffix string `yaml:"spdxNamespaceSuffix,omitempty" json:"spdxNamespaceSuffix,omitempty"`
+
+	// Progress is called by the serializers every ProgressInterval nodes
+	// and once all the nodes are serialized with the number of nodes done
+	// and the total in the document. Nil disables progress reporting.
+	Progress         ProgressFunc `yaml:"-" json:"-"`
+	ProgressInterval int          `yaml:"progressInterval,omitempty" json:"progressInterval,omitempty"` // DefaultProgressInterval when not set
+
+	// ExcludeEdgeTypes are the relationships left out of the written
+	// documents, along with the nodes only linked to the graph by them.
+	// The document being written is not modified.
+	ExcludeEdgeTypes []sbom.Edge_Type `yaml:"excludeEdgeTypes,omitempty" json:"excludeEdgeTypes,omitempty"`
+
+	// PruneUnreachable leaves out of the written documents the nodes that
+	// cannot be reached from the root elements. The document being written
+	// is not modified.
+	PruneUnreachable bool `yaml:"pruneUnreachable,omitempty" json:"pruneUnreachable,omitempty"`
+}
+
+// DefaultScopeFilter are the relationships excluded by a scope filter with
+// no types: the dependencies not needed at runtime
+var DefaultScopeFilter = []sbom.Edge_Type{
+	sbom.Edge_devDependency,
+	sbom.Edge_testDependency,
+	sbom.Edge_optionalDependency,
 }
 
 var Default = Options{
@@ -0,0 +1,68 @@
+package writer
+
+import (
+	"context"
+	"io"
+
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+// ctxCheckInterval is the number of nodes serialized between checks of
+// the context
+const ctxCheckInterval = 64
+
+// progressCounter counts the nodes serialized, reporting them to the
+// progress function in the options and checking the context for
+// cancellation as the serializers advance.
+type progressCounter struct {
+	ctx      context.Context
+	fn       options.ProgressFunc
+	interval int
+	done     int
+	total    int
+}
+
+// newProgressCounter returns a counter for a document of total nodes
+func newProgressCounter(ctx context.Context, opts options.Options, total int) *progressCounter {
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = options.DefaultProgressInterval
+	}
+	return &progressCounter{ctx: ctx, fn: opts.Progress, interval: interval, total: total}
+}
+
+// step counts one more node, reporting every interval nodes and when the
+// last node is done. It returns the context error if it was canceled.
+func (p *progressCounter) step() error {
+	p.done++
+	if p.done%ctxCheckInterval == 0 {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if p.fn != nil && (p.done%p.interval == 0 || p.done == p.total) {
+		p.fn(p.done, p.total)
+	}
+	return nil
+}
+
+// finish reports all the nodes as done
+func (p *progressCounter) finish() {
+	p.done = p.total
+	if p.fn != nil {
+		p.fn(p.done, p.total)
+	}
+}
+
+// ctxWriter is a writer that fails once its context is canceled
+type ctxWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (cw *ctxWriter) Write(p []byte) (int, error) {
+	if err := cw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cw.w.Write(p)
+}
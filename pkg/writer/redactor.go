@@ -0,0 +1,168 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+// RedactRules define the nodes and fields removed by the Redactor
+type RedactRules struct {
+	// PurlNamespaces removes nodes whose package url namespace starts with
+	// any of the listed prefixes
+	PurlNamespaces []string
+
+	// PathGlobs removes file nodes whose name matches any of the globs.
+	// The patterns use the syntax of path.Match
+	PathGlobs []string
+
+	// Fields lists node fields to clear in the remaining nodes, named
+	// after the protobuf fields (eg "copyright", "suppliers")
+	Fields []string
+}
+
+// Redactor is a serializer wrapper that strips data from the document
+// before handing it to the wrapped serializer. The original document
+// is never modified.
+type Redactor struct {
+	inner Serializer
+	rules RedactRules
+}
+
+// NewRedactor returns a Redactor that removes the data matched by rules
+// before serializing with inner
+func NewRedactor(inner Serializer, rules RedactRules) *Redactor {
+	return &Redactor{
+		inner: inner,
+		rules: rules,
+	}
+}
+
+// Serialize redacts a copy of the document and serializes it with the
+// wrapped serializer
+func (r *Redactor) Serialize(opts options.Options, bom *sbom.Document) (interface{}, error) {
+	return r.SerializeContext(context.Background(), opts, bom)
+}
+
+// SerializeContext works as Serialize but passes ctx to the wrapped
+// serializer when it is a ContextSerializer
+func (r *Redactor) SerializeContext(ctx context.Context, opts options.Options, bom *sbom.Document) (interface{}, error) {
+	if bom == nil {
+		return nil, errors.New("document is nil")
+	}
+
+	redacted, err := r.redact(bom)
+	if err != nil {
+		return nil, fmt.Errorf("redacting document: %w", err)
+	}
+
+	return serializeContext(ctx, r.inner, opts, redacted)
+}
+
+// Render calls the wrapped serializer's Render
+func (r *Redactor) Render(opts options.Options, doc interface{}, wr io.Writer) error {
+	return r.inner.Render(opts, doc, wr)
+}
+
+// RenderContext works as Render but passes ctx to the wrapped serializer
+// when it is a ContextSerializer
+func (r *Redactor) RenderContext(ctx context.Context, opts options.Options, doc interface{}, wr io.Writer) error {
+	return renderContext(ctx, r.inner, opts, doc, wr)
+}
+
+// redact returns a copy of bom with the rules applied
+func (r *Redactor) redact(bom *sbom.Document) (*sbom.Document, error) {
+	for _, g := range r.rules.PathGlobs {
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("invalid path glob %q: %w", g, err)
+		}
+	}
+
+	fields := []protoreflect.FieldDescriptor{}
+	nodeFields := (&sbom.Node{}).ProtoReflect().Descriptor().Fields()
+	for _, name := range r.rules.Fields {
+		fd := nodeFields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			return nil, fmt.Errorf("unknown node field %q", name)
+		}
+		if name == "id" {
+			return nil, errors.New("node id cannot be redacted")
+		}
+		fields = append(fields, fd)
+	}
+
+	ret := &sbom.Document{
+		NodeList: bom.NodeList.Copy(),
+	}
+	if bom.Metadata != nil {
+		ret.Metadata = proto.Clone(bom.Metadata).(*sbom.Metadata)
+	}
+	if ret.NodeList == nil {
+		return ret, nil
+	}
+
+	removed := map[string]struct{}{}
+	ids := []string{}
+	for _, n := range ret.NodeList.Nodes {
+		if r.matches(n) {
+			removed[n.Id] = struct{}{}
+			ids = append(ids, n.Id)
+		}
+	}
+
+	if len(ids) > 0 {
+		ret.NodeList.RemoveNodes(ids)
+		roots := []string{}
+		for _, id := range ret.NodeList.RootElements {
+			if _, ok := removed[id]; !ok {
+				roots = append(roots, id)
+			}
+		}
+		ret.NodeList.RootElements = roots
+	}
+
+	for _, n := range ret.NodeList.Nodes {
+		m := n.ProtoReflect()
+		for _, fd := range fields {
+			m.Clear(fd)
+		}
+	}
+
+	return ret, nil
+}
+
+// matches returns true if the node is matched by any of the rules
+func (r *Redactor) matches(n *sbom.Node) bool {
+	if len(r.rules.PurlNamespaces) > 0 && n.Purl() != "" {
+		ns := n.Purl().Namespace()
+		for _, prefix := range r.rules.PurlNamespaces {
+			if strings.HasPrefix(ns, prefix) {
+				return true
+			}
+		}
+	}
+
+	if n.Type == sbom.Node_FILE {
+		name := n.FileName
+		if name == "" {
+			name = n.Name
+		}
+		for _, g := range r.rules.PathGlobs {
+			// Errors are checked before redacting the document
+			if ok, _ := path.Match(g, name); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package writer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+func TestRedactor(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Nodes[0].Copyright = "Copyright Example Corp"
+	doc.NodeList.Nodes[1].Identifiers = map[int32]string{
+		int32(sbom.SoftwareIdentifierType_PURL): "pkg:generic/internal/secret-tool@1.0",
+	}
+	doc.NodeList.Nodes[2].Identifiers = map[int32]string{
+		int32(sbom.SoftwareIdentifierType_PURL): "pkg:generic/internal/secret-lib@1.0",
+	}
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "root1", To: []string{"dep"}},
+		{Type: sbom.Edge_contains, From: "root2", To: []string{"dep"}},
+	}
+
+	r := NewRedactor(&SerializerProtobomJSON{}, RedactRules{
+		PurlNamespaces: []string{"internal"},
+		Fields:         []string{"copyright"},
+	})
+
+	opts := options.Options{Format: formats.PROTOBOMJSON}
+	out, err := r.Serialize(opts, doc)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(opts, out, &buf))
+	require.NotContains(t, buf.String(), "secret")
+	require.NotContains(t, buf.String(), "Example Corp")
+
+	redacted, ok := out.(*sbom.Document)
+	require.True(t, ok)
+	require.Len(t, redacted.NodeList.Nodes, 1)
+	require.Equal(t, "root1", redacted.NodeList.Nodes[0].Id)
+	require.Equal(t, []string{"root1"}, redacted.NodeList.RootElements)
+	require.Len(t, redacted.NodeList.Edges, 0)
+
+	// The original document is not modified
+	require.Len(t, doc.NodeList.Nodes, 3)
+	require.Len(t, doc.NodeList.Edges, 2)
+	require.Equal(t, "Copyright Example Corp", doc.NodeList.Nodes[0].Copyright)
+
+	// Unknown fields are an error
+	_, err = NewRedactor(&SerializerProtobomJSON{}, RedactRules{Fields: []string{"bogus"}}).Serialize(opts, doc)
+	require.ErrorContains(t, err, "bogus")
+}
@@ -1,6 +1,7 @@
 package writer
 
 import (
+	"context"
 	"io"
 
 	"github.com/bom-squad/protobom/pkg/sbom"
@@ -11,3 +12,31 @@ type Serializer interface {
 	Serialize(options.Options, *sbom.Document) (interface{}, error)
 	Render(options.Options, interface{}, io.Writer) error
 }
+
+// ContextSerializer is a Serializer that can be canceled. When the
+// serializer of a format implements it, WriteStreamContext calls the
+// context methods which should abort and return the context error when
+// ctx is canceled.
+type ContextSerializer interface {
+	Serializer
+	SerializeContext(context.Context, options.Options, *sbom.Document) (interface{}, error)
+	RenderContext(context.Context, options.Options, interface{}, io.Writer) error
+}
+
+// serializeContext calls the context aware Serialize of the serializer
+// when it has one
+func serializeContext(ctx context.Context, s Serializer, opts options.Options, bom *sbom.Document) (interface{}, error) {
+	if cs, ok := s.(ContextSerializer); ok {
+		return cs.SerializeContext(ctx, opts, bom)
+	}
+	return s.Serialize(opts, bom)
+}
+
+// renderContext calls the context aware Render of the serializer when it
+// has one
+func renderContext(ctx context.Context, s Serializer, opts options.Options, doc interface{}, wr io.Writer) error {
+	if cs, ok := s.(ContextSerializer); ok {
+		return cs.RenderContext(ctx, opts, doc, wr)
+	}
+	return s.Render(opts, doc, wr)
+}
@@ -1,21 +1,36 @@
 package writer
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"  //nolint:gosec // Used to aggregate existing digests
+	"crypto/sha1" //nolint:gosec // Used to aggregate existing digests
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
+	protospdx "github.com/bom-squad/protobom/pkg/formats/spdx"
 	"github.com/bom-squad/protobom/pkg/sbom"
 	"github.com/bom-squad/protobom/pkg/writer/options"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	stateKey state = "cyclonedx_serializer_state"
+
+	// virtualRootRef is the BOM reference of the component synthesized
+	// when writing documents with the virtual root scheme
+	virtualRootRef = "protobom-virtual-root"
 )
 
 type (
@@ -23,17 +38,28 @@ type (
 	SerializerCDX struct{}
 )
 
+// Serialize converts the protobom document to the native format
 func (s *SerializerCDX) Serialize(opts options.Options, bom *sbom.Document) (interface{}, error) {
-	// Load the context with the CDX value. We initialize a context here
-	// but we should get it as part of the method to capture cancelations
-	// from the CLI or REST API.
+	return s.SerializeContext(context.Background(), opts, bom)
+}
+
+// SerializeContext works as Serialize but aborts when ctx is canceled
+func (s *SerializerCDX) SerializeContext(ctx context.Context, opts options.Options, bom *sbom.Document) (interface{}, error) {
+	// Load the context with the CDX value
 	state := newSerializerCDXState()
-	ctx := context.WithValue(context.Background(), stateKey, state)
+	ctx = context.WithValue(ctx, stateKey, state)
+
+	bom, err := bomRefDocument(bom)
+	if err != nil {
+		return nil, fmt.Errorf("assigning bom-refs: %w", err)
+	}
 
 	doc := cdx.NewBOM()
-	doc.SerialNumber = bom.Metadata.Id
+	doc.SerialNumber = cdxSerialNumber(bom.Metadata.Id)
+	// CycloneDX versions start at 1
+	doc.Version = 1
 	ver, err := strconv.Atoi(bom.Metadata.Version)
-	if err == nil {
+	if err == nil && ver > 0 {
 		doc.Version = ver
 	}
 
@@ -41,17 +67,35 @@ func (s *SerializerCDX) Serialize(opts options.Options, bom *sbom.Document) (int
 		Component: &cdx.Component{},
 	}
 
+	if bom.Metadata.Date != nil {
+		metadata.Timestamp = bom.Metadata.Date.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+
+	if len(bom.Metadata.Tools) > 0 {
+		tools := []cdx.Tool{}
+		for _, t := range bom.Metadata.Tools {
+			tools = append(tools, cdx.Tool{
+				Vendor:  t.Vendor,
+				Name:    t.Name,
+				Version: t.Version,
+			})
+		}
+		metadata.Tools = &tools
+	}
+
+	metadata.Properties = propertiesToCDX(bom.Metadata.Properties)
+
 	doc.Metadata = &metadata
 	doc.Components = &[]cdx.Component{}
 	doc.Dependencies = &[]cdx.Dependency{}
 
-	rootComponent, err := s.root(ctx, bom)
+	rootComponent, rootDeps, err := s.root(ctx, opts, bom)
 	if err != nil {
 		return nil, fmt.Errorf("generating SBOM root component: %w", err)
 	}
 
 	doc.Metadata.Component = rootComponent
-	if err := s.componentsMaps(ctx, bom); err != nil {
+	if err := s.componentsMaps(ctx, bom, newProgressCounter(ctx, opts, len(bom.NodeList.Nodes))); err != nil {
 		return nil, err
 	}
 
@@ -59,15 +103,116 @@ func (s *SerializerCDX) Serialize(opts options.Options, bom *sbom.Document) (int
 	if err != nil {
 		return nil, err
 	}
+	deps = mergeDependencies(append(rootDeps, deps...))
 	doc.Dependencies = &deps
 
 	components := state.components()
+	if opts.SortComponents {
+		sortComponents(&components)
+		sortDependencies(&deps)
+	}
 	clearAutoRefs(&components)
 	doc.Components = &components
 
+	if vulns := s.vulnerabilities(bom); len(vulns) > 0 {
+		doc.Vulnerabilities = &vulns
+	}
+
 	return doc, nil
 }
 
+// cdxSerialNumber returns the document ID as a CycloneDX serial number. IDs
+// that are not UUIDs, like those of SPDX documents, are replaced by a new
+// random urn:uuid as CycloneDX requires it.
+func cdxSerialNumber(id string) string {
+	if u, err := uuid.Parse(id); err == nil {
+		return u.URN()
+	}
+	return uuid.New().URN()
+}
+
+// validBOMRef returns true if the string is a non-empty bom-ref made only
+// of characters allowed in URIs, which covers purls and the IDs generated by
+// protobom
+func validBOMRef(ref string) bool {
+	if ref == "" {
+		return false
+	}
+	for i := 0; i < len(ref); i++ {
+		if !bomRefChar(ref[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bomRefChar returns true for the unreserved and reserved URI characters
+// plus the percent sign
+func bomRefChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~:/?#[]@!$&'()*+,;=%", c) != -1
+}
+
+// sanitizeBOMRef percent-encodes the bytes of id not valid in a bom-ref
+func sanitizeBOMRef(id string) string {
+	if id == "" {
+		return "node"
+	}
+	var sb strings.Builder
+	for i := 0; i < len(id); i++ {
+		if bomRefChar(id[i]) {
+			sb.WriteByte(id[i])
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", id[i])
+		}
+	}
+	return sb.String()
+}
+
+// bomRefDocument returns a document where the nodes with IDs that are not
+// valid bom-refs are renamed to a sanitized, unique version of their ID.
+// Edges and root elements are rewritten so the dependency graph still
+// resolves. Valid IDs are kept as they are, if all IDs are valid the
+// original document is returned, otherwise it is not modified.
+func bomRefDocument(bom *sbom.Document) (*sbom.Document, error) {
+	if bom.NodeList == nil {
+		return bom, nil
+	}
+
+	used := map[string]struct{}{virtualRootRef: {}}
+	invalid := []string{}
+	for _, n := range bom.NodeList.Nodes {
+		if n.Id == virtualRootRef || !validBOMRef(n.Id) {
+			invalid = append(invalid, n.Id)
+			continue
+		}
+		used[n.Id] = struct{}{}
+	}
+	if len(invalid) == 0 {
+		return bom, nil
+	}
+
+	nl := bom.NodeList.Copy()
+	for _, id := range invalid {
+		base := sanitizeBOMRef(id)
+		ref := base
+		for i := 2; ; i++ {
+			if _, ok := used[ref]; !ok {
+				break
+			}
+			ref = fmt.Sprintf("%s-%d", base, i)
+		}
+		used[ref] = struct{}{}
+		if err := nl.RenameNode(id, ref); err != nil {
+			return nil, fmt.Errorf("renaming node %q: %w", id, err)
+		}
+	}
+	return &sbom.Document{Metadata: bom.Metadata, NodeList: nl}, nil
+}
+
 // clearAutoRefs
 // The last step of the CDX serialization recursively removes all autogenerated
 // refs added by the protobom reader. These are added on CycloneDX ingestion
@@ -87,13 +232,58 @@ func clearAutoRefs(comps *[]cdx.Component) {
 	}
 }
 
-func (s *SerializerCDX) componentsMaps(ctx context.Context, bom *sbom.Document) error {
+// componentSortKey returns the key used to sort components: their purl or
+// name@version when there is no purl
+func componentSortKey(c *cdx.Component) string {
+	if c.PackageURL != "" {
+		return c.PackageURL
+	}
+	return c.Name + "@" + c.Version
+}
+
+// sortComponents sorts a list of components and their subcomponents
+func sortComponents(comps *[]cdx.Component) {
+	sort.SliceStable(*comps, func(i, j int) bool {
+		ki, kj := componentSortKey(&(*comps)[i]), componentSortKey(&(*comps)[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return (*comps)[i].BOMRef < (*comps)[j].BOMRef
+	})
+	for i := range *comps {
+		if (*comps)[i].Components != nil {
+			sortComponents((*comps)[i].Components)
+		}
+	}
+}
+
+// sortDependencies sorts the dependencies by ref and their dependsOn lists.
+// The lists are copied before sorting as they may point to the edges of the
+// document being serialized.
+func sortDependencies(deps *[]cdx.Dependency) {
+	for i := range *deps {
+		if (*deps)[i].Dependencies == nil {
+			continue
+		}
+		refs := append([]string{}, *(*deps)[i].Dependencies...)
+		sort.Strings(refs)
+		(*deps)[i].Dependencies = &refs
+	}
+	sort.SliceStable(*deps, func(i, j int) bool {
+		return (*deps)[i].Ref < (*deps)[j].Ref
+	})
+}
+
+func (s *SerializerCDX) componentsMaps(ctx context.Context, bom *sbom.Document, progress *progressCounter) error {
 	state, err := getCDXState(ctx)
 	if err != nil {
 		return fmt.Errorf("reading state: %w", err)
 	}
 
 	for _, n := range bom.NodeList.Nodes {
+		if err := progress.step(); err != nil {
+			return err
+		}
 		comp := s.nodeToComponent(n)
 		if comp == nil {
 			// Error? Warn?
@@ -105,84 +295,203 @@ func (s *SerializerCDX) componentsMaps(ctx context.Context, bom *sbom.Document)
 	return nil
 }
 
-func (s *SerializerCDX) root(ctx context.Context, bom *sbom.Document) (*cdx.Component, error) {
-	var rootComp *cdx.Component
-	// First, assign the top level nodes
+// root returns the component to use as the document's metadata.component and
+// the dependencies needed to link any additional root elements to it. How
+// documents with more than one root are expressed is controlled by the
+// CDXRootScheme in the options.
+func (s *SerializerCDX) root(ctx context.Context, opts options.Options, bom *sbom.Document) (*cdx.Component, []cdx.Dependency, error) {
 	state, err := getCDXState(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("reading state: %w", err)
+		return nil, nil, fmt.Errorf("reading state: %w", err)
 	}
 
-	// 2DO Use GetRootNodes() https://github.com/bom-squad/protobom/pull/20
-	if bom.NodeList.RootElements != nil && len(bom.NodeList.RootElements) > 0 {
-		for _, id := range bom.NodeList.RootElements {
-			// Search for the node and add it
-			for _, n := range bom.NodeList.Nodes {
-				if n.Id == id {
-					rootComp = s.nodeToComponent(n)
-					state.addedDict[id] = struct{}{}
-				}
-			}
+	if bom.NodeList == nil {
+		return nil, nil, nil
+	}
 
-			// TODO(degradation): Here we would document other root level elements
-			// are not added to to document
-			if true { // temp workaround in favor of adding a lint tag
-				break
+	roots := bom.NodeList.RootNodes()
+	if len(roots) == 0 {
+		return nil, nil, nil
+	}
+
+	scheme := opts.CDXRootScheme
+	if scheme == nil {
+		scheme = FlatRootScheme
+	}
+
+	rootComp, deps, err := scheme(roots, bom)
+	if err != nil {
+		return nil, nil, fmt.Errorf("applying root scheme: %w", err)
+	}
+
+	// If the top level component is one of the nodes, it should not
+	// be listed again in the components list
+	if rootComp != nil {
+		state.addedDict[rootComp.BOMRef] = struct{}{}
+		state.rootRef = rootComp.BOMRef
+	}
+
+	return rootComp, deps, nil
+}
+
+// FlatRootScheme promotes the first root element to metadata.component. The
+// rest of the roots are written as top level components with an entry in
+// the dependencies list and no edge to the first one, so no components or
+// relationships are invented but consumers will not see the additional roots
+// as roots of the document.
+func FlatRootScheme(roots []*sbom.Node, _ *sbom.Document) (*cdx.Component, []cdx.Dependency, error) {
+	if len(roots) == 0 {
+		return nil, nil, nil
+	}
+
+	deps := []cdx.Dependency{}
+	for _, n := range roots[1:] {
+		deps = append(deps, cdx.Dependency{Ref: n.Id})
+	}
+
+	return (&SerializerCDX{}).nodeToComponent(roots[0]), deps, nil
+}
+
+// VirtualRootOptions configures the root component synthesized by the
+// virtual root scheme
+type VirtualRootOptions struct {
+	// AggregateHashes computes hashes for the virtual root from the hashes
+	// of the real roots. For each algorithm present in every root, the
+	// roots' hex digests are lowercased, sorted, joined with newlines and
+	// hashed with that same algorithm. Only MD5, SHA1, SHA256, SHA384 and
+	// SHA512 are aggregated.
+	AggregateHashes bool
+}
+
+// aggregateHashAlgorithms are the algorithms used to aggregate root hashes
+var aggregateHashAlgorithms = []struct {
+	algo sbom.HashAlgorithm
+	new  func() hash.Hash
+}{
+	{sbom.HashAlgorithm_MD5, md5.New},
+	{sbom.HashAlgorithm_SHA1, sha1.New},
+	{sbom.HashAlgorithm_SHA256, sha256.New},
+	{sbom.HashAlgorithm_SHA384, sha512.New384},
+	{sbom.HashAlgorithm_SHA512, sha512.New},
+}
+
+// VirtualRootScheme synthesizes a component to act as metadata.component and
+// lists all the root elements as its dependencies. The component is named
+// after the document, or "virtual root" if it has no name. The graph is kept
+// intact but the output contains a component that does not exist in the
+// original document. Documents with a single root are written as in
+// FlatRootScheme.
+func VirtualRootScheme(roots []*sbom.Node, bom *sbom.Document) (*cdx.Component, []cdx.Dependency, error) {
+	return NewVirtualRootScheme(VirtualRootOptions{})(roots, bom)
+}
+
+// NewVirtualRootScheme returns a VirtualRootScheme configured with opts
+func NewVirtualRootScheme(opts VirtualRootOptions) options.CDXRootScheme {
+	return func(roots []*sbom.Node, bom *sbom.Document) (*cdx.Component, []cdx.Dependency, error) {
+		if len(roots) < 2 {
+			return FlatRootScheme(roots, bom)
+		}
+
+		rootIDs := []string{}
+		for _, n := range roots {
+			rootIDs = append(rootIDs, n.Id)
+		}
+
+		rootComp := &cdx.Component{
+			BOMRef: virtualRootRef,
+			Type:   cdx.ComponentTypeApplication,
+			Name:   "virtual root",
+		}
+		if name := bom.GetMetadata().GetName(); name != "" {
+			rootComp.Name = name
+		}
+
+		if opts.AggregateHashes {
+			if hashes := aggregateRootHashes(roots); len(hashes) > 0 {
+				rootComp.Hashes = &hashes
 			}
 		}
+		return rootComp, []cdx.Dependency{{Ref: virtualRootRef, Dependencies: &rootIDs}}, nil
 	}
+}
+
+// aggregateRootHashes computes the virtual root hashes as described in
+// VirtualRootOptions.AggregateHashes
+func aggregateRootHashes(roots []*sbom.Node) []cdx.Hash {
+	ret := []cdx.Hash{}
+	for _, a := range aggregateHashAlgorithms {
+		digests := []string{}
+		for _, n := range roots {
+			if d, ok := n.Hashes[a.algo.String()]; ok && d != "" {
+				digests = append(digests, strings.ToLower(d))
+			}
+		}
 
-	return rootComp, nil
+		// Skip algorithms not present in every root
+		if len(digests) != len(roots) {
+			continue
+		}
+		sort.Strings(digests)
+
+		h := a.new()
+		h.Write([]byte(strings.Join(digests, "\n")))
+		ret = append(ret, cdx.Hash{
+			Algorithm: a.algo.ToCycloneDX(),
+			Value:     fmt.Sprintf("%x", h.Sum(nil)),
+		})
+	}
+	return ret
 }
 
+// dependencies returns the CycloneDX dependencies of the document and nests
+// the contained components in their parents.
+//
+// Every dependsOn edge is recorded in the dependency graph, including those
+// of nested components, and the nodes that only appear as targets get an
+// entry with no dependencies. Components contained by the metadata component
+// are listed at the top level of the document. A component contained by
+// more than one node is nested only in the first of them.
+//
 // NOTE dependencies function modifies the components dictionary
 func (s *SerializerCDX) dependencies(ctx context.Context, bom *sbom.Document) ([]cdx.Dependency, error) {
-	var dependencies []cdx.Dependency
+	dependencies := []cdx.Dependency{}
 	state, err := getCDXState(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("reading state: %w", err)
 	}
 
+	parents := map[string]string{}
+	children := map[string][]string{}
 	for _, e := range bom.NodeList.Edges {
-		if _, ok := state.addedDict[e.From]; ok {
-			continue
-		}
-
 		if _, ok := state.componentsDict[e.From]; !ok {
-			logrus.Info("serialize")
 			return nil, fmt.Errorf("unable to find component %s", e.From)
 		}
+		for _, targetID := range e.To {
+			if _, ok := state.componentsDict[targetID]; !ok {
+				return nil, fmt.Errorf("unable to locate node %s", targetID)
+			}
+		}
 
-		// In this example, we tree-ify all components related with a
-		// "contains" relationship. This is just an opinion for the demo
-		// and it is something we can parameterize
 		switch e.Type {
 		case sbom.Edge_contains:
-			// Make sure we have the target component
+			// The metadata component's children stay at the top level
+			if e.From == state.rootRef {
+				continue
+			}
 			for _, targetID := range e.To {
-				state.addedDict[targetID] = struct{}{}
-				if _, ok := state.componentsDict[targetID]; !ok {
-					return nil, fmt.Errorf("unable to locate node %s", targetID)
-				}
-
-				if state.componentsDict[e.From].Components == nil {
-					state.componentsDict[e.From].Components = &[]cdx.Component{}
+				if _, ok := parents[targetID]; ok || targetID == state.rootRef || nestsIn(parents, e.From, targetID) {
+					logrus.Warnf("component %s is already nested, not nesting it in %s", targetID, e.From)
+					continue
 				}
-				*state.componentsDict[e.From].Components = append(*state.componentsDict[e.From].Components, *state.componentsDict[targetID])
+				parents[targetID] = e.From
+				children[e.From] = append(children[e.From], targetID)
 			}
 
 		case sbom.Edge_dependsOn:
-			// Add to the dependency tree
+			targets := append([]string{}, e.To...)
+			dependencies = append(dependencies, cdx.Dependency{Ref: e.From, Dependencies: &targets})
 			for _, targetID := range e.To {
-				state.addedDict[targetID] = struct{}{}
-				if _, ok := state.componentsDict[targetID]; !ok {
-					return nil, fmt.Errorf("unable to locate node %s", targetID)
-				}
-
-				dependencies = append(dependencies, cdx.Dependency{
-					Ref:          e.From,
-					Dependencies: &e.To,
-				})
+				dependencies = append(dependencies, cdx.Dependency{Ref: targetID})
 			}
 
 		default:
@@ -194,7 +503,102 @@ func (s *SerializerCDX) dependencies(ctx context.Context, bom *sbom.Document) ([
 		}
 	}
 
-	return dependencies, nil
+	// Nest the components depth first so that children are complete when
+	// they are copied into their parents
+	var nest func(id string)
+	nest = func(id string) {
+		for _, childID := range children[id] {
+			nest(childID)
+			if state.componentsDict[id].Components == nil {
+				state.componentsDict[id].Components = &[]cdx.Component{}
+			}
+			*state.componentsDict[id].Components = append(*state.componentsDict[id].Components, *state.componentsDict[childID])
+			state.addedDict[childID] = struct{}{}
+		}
+		delete(children, id)
+	}
+	for _, n := range bom.NodeList.Nodes {
+		if _, ok := parents[n.Id]; !ok {
+			nest(n.Id)
+		}
+	}
+
+	return mergeDependencies(dependencies), nil
+}
+
+// nestsIn returns true if the component id is nested, directly or through
+// other components, in ancestor
+func nestsIn(parents map[string]string, id, ancestor string) bool {
+	for current, ok := id, true; ok; current, ok = parents[current] {
+		if current == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeDependencies combines the dependencies with the same ref into a
+// single entry as CycloneDX requires refs to be unique. Entries and their
+// dependsOn lists keep the order in which they were first found.
+func mergeDependencies(deps []cdx.Dependency) []cdx.Dependency {
+	ret := []cdx.Dependency{}
+	index := map[string]int{}
+	seen := map[string]map[string]struct{}{}
+	for _, d := range deps {
+		i, ok := index[d.Ref]
+		if !ok {
+			i = len(ret)
+			index[d.Ref] = i
+			seen[d.Ref] = map[string]struct{}{}
+			ret = append(ret, cdx.Dependency{Ref: d.Ref, Dependencies: &[]string{}})
+		}
+		if d.Dependencies == nil {
+			continue
+		}
+		for _, ref := range *d.Dependencies {
+			if _, ok := seen[d.Ref][ref]; ok {
+				continue
+			}
+			seen[d.Ref][ref] = struct{}{}
+			*ret[i].Dependencies = append(*ret[i].Dependencies, ref)
+		}
+	}
+	return ret
+}
+
+// vulnerabilities returns the CycloneDX vulnerabilities recorded in the
+// document nodes. Nodes sharing the same vulnerability analysis are listed
+// in the affects section of a single entry.
+func (s *SerializerCDX) vulnerabilities(bom *sbom.Document) []cdx.Vulnerability {
+	vulns := []cdx.Vulnerability{}
+	index := map[string]int{}
+	for _, n := range bom.NodeList.GetNodes() {
+		for _, v := range n.Vulnerabilities {
+			if v.Id == "" {
+				continue
+			}
+			key := strings.Join([]string{v.Id, v.Status.String(), v.Justification, v.Detail}, "+++")
+			if i, ok := index[key]; ok {
+				*vulns[i].Affects = append(*vulns[i].Affects, cdx.Affects{Ref: n.Id})
+				continue
+			}
+
+			vuln := cdx.Vulnerability{
+				ID:      v.Id,
+				Affects: &[]cdx.Affects{{Ref: n.Id}},
+			}
+			if v.Status != sbom.Vulnerability_UNKNOWN || v.Justification != "" || v.Detail != "" {
+				vuln.Analysis = &cdx.VulnerabilityAnalysis{
+					State:         v.Status.ToCycloneDX(),
+					Justification: cdx.ImpactAnalysisJustification(v.Justification),
+					Detail:        v.Detail,
+				}
+			}
+			index[key] = len(vulns)
+			vulns = append(vulns, vuln)
+		}
+	}
+	return vulns
 }
 
 // nodeToComponent converts a node in protobuf to a CycloneDX component
@@ -204,7 +608,7 @@ func (s *SerializerCDX) nodeToComponent(n *sbom.Node) *cdx.Component {
 	}
 	c := &cdx.Component{
 		BOMRef:      n.Id,
-		Type:        cdx.ComponentType(strings.ToLower(n.PrimaryPurpose)), // Fix to make it valid
+		Type:        sbom.PurposeToCDXComponentType(n.PrimaryPurpose),
 		Name:        n.Name,
 		Version:     n.Version,
 		Description: n.Description,
@@ -212,12 +616,23 @@ func (s *SerializerCDX) nodeToComponent(n *sbom.Node) *cdx.Component {
 
 	if n.Type == sbom.Node_FILE {
 		c.Type = "file"
+		// TODO(degradation): CycloneDX 1.4 cannot express source locations,
+		// occurrences in the 1.5 evidence could be used when supported
 	}
 
+	// TODO(degradation): CycloneDX 1.4 components have no release, build or
+	// valid until dates. They are lost when rendering to CDX.
+
 	if n.Licenses != nil && len(n.Licenses) > 0 {
 		var licenseChoices []cdx.LicenseChoice
 		var licenses cdx.Licenses
 		for _, l := range n.Licenses {
+			// Compound expressions are not valid license IDs
+			expr, err := protospdx.ParseLicenseExpression(l)
+			if err == nil && expr.Operator != "" {
+				licenseChoices = append(licenseChoices, cdx.LicenseChoice{Expression: l})
+				continue
+			}
 			licenseChoices = append(licenseChoices, cdx.LicenseChoice{
 				License: &cdx.License{
 					ID: l,
@@ -247,17 +662,70 @@ func (s *SerializerCDX) nodeToComponent(n *sbom.Node) *cdx.Component {
 		}
 	}
 
+	if n.UrlHome != "" {
+		c.ExternalReferences = &[]cdx.ExternalReference{
+			{Type: cdx.ERTypeWebsite, URL: n.UrlHome},
+		}
+	}
+
+	if n.UrlDownload != "" {
+		if c.ExternalReferences == nil {
+			c.ExternalReferences = &[]cdx.ExternalReference{}
+		}
+		*c.ExternalReferences = append(*c.ExternalReferences, cdx.ExternalReference{
+			Type: cdx.ERTypeDistribution, URL: n.UrlDownload,
+		})
+	}
+
 	if n.ExternalReferences != nil {
 		for _, er := range n.ExternalReferences {
+			if er.Url == "" {
+				// TODO(degradation): CycloneDX requires the reference URL
+				continue
+			}
 			if c.ExternalReferences == nil {
 				c.ExternalReferences = &[]cdx.ExternalReference{}
 			}
 
-			*c.ExternalReferences = append(*c.ExternalReferences, cdx.ExternalReference{
-				Type: cdx.ExternalReferenceType(er.Type), // Fix to make it valid
-				URL:  er.Url,
-			})
+			extRef := cdx.ExternalReference{
+				Type:    er.ToCycloneDX(),
+				URL:     er.Url,
+				Comment: er.Comment,
+			}
+
+			for algoString, hash := range er.Hashes {
+				if algoVal, ok := sbom.HashAlgorithm_value[algoString]; ok {
+					cdxAlgo := sbom.HashAlgorithm(algoVal).ToCycloneDX()
+					if cdxAlgo == "" {
+						continue
+					}
+					if extRef.Hashes == nil {
+						extRef.Hashes = &[]cdx.Hash{}
+					}
+					*extRef.Hashes = append(*extRef.Hashes, cdx.Hash{
+						Algorithm: cdxAlgo,
+						Value:     hash,
+					})
+				}
+			}
+
+			*c.ExternalReferences = append(*c.ExternalReferences, extRef)
+		}
+	}
+
+	if len(n.Suppliers) > 0 {
+		// TODO(degradation): Only one supplier is supported in CDX
+		c.Supplier = personToOrganizationalEntity(n.Suppliers[0])
+	}
+
+	if len(n.Originators) > 0 {
+		// TODO(degradation): CDX authors are a free-form string, only
+		// the name and email of the originators are kept
+		authors := []string{}
+		for _, o := range n.Originators {
+			authors = append(authors, o.ToSPDX2ClientString())
 		}
+		c.Author = strings.Join(authors, ", ")
 	}
 
 	if n.Identifiers != nil {
@@ -270,35 +738,111 @@ func (s *SerializerCDX) nodeToComponent(n *sbom.Node) *cdx.Component {
 			case int32(sbom.SoftwareIdentifierType_CPE22):
 				// TODO(degradation): Only one CPE is supperted in CDX
 				if c.CPE == "" {
+					// Write CPE 2.2 URIs as the preferred CPE 2.3
 					c.CPE = n.Identifiers[idType]
+					if cpe, err := sbom.CPE22to23(c.CPE); err == nil {
+						c.CPE = cpe
+					}
 				}
 			}
 		}
 	}
 
+	c.Properties = propertiesToCDX(n.Properties)
+
 	return c
 }
 
+// propertiesToCDX converts a properties map to a list of CycloneDX
+// properties sorted by name. Returns nil if there are no properties.
+func propertiesToCDX(props map[string]string) *[]cdx.Property {
+	if len(props) == 0 {
+		return nil
+	}
+	keys := []string{}
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ret := []cdx.Property{}
+	for _, k := range keys {
+		ret = append(ret, cdx.Property{Name: k, Value: props[k]})
+	}
+	return &ret
+}
+
+// personToOrganizationalEntity converts a protobom person to a CycloneDX
+// organizational entity. If the person has an email or phone they are
+// recorded as the first contact of the entity.
+func personToOrganizationalEntity(p *sbom.Person) *cdx.OrganizationalEntity {
+	oe := &cdx.OrganizationalEntity{
+		Name: p.Name,
+	}
+
+	if p.Url != "" {
+		oe.URL = &[]string{p.Url}
+	}
+
+	contacts := []cdx.OrganizationalContact{}
+	if p.Email != "" || p.Phone != "" {
+		contact := cdx.OrganizationalContact{Email: p.Email, Phone: p.Phone}
+		if !p.IsOrg {
+			contact.Name = p.Name
+		}
+		contacts = append(contacts, contact)
+	}
+
+	for _, c := range p.Contacts {
+		contacts = append(contacts, cdx.OrganizationalContact{
+			Name:  c.Name,
+			Email: c.Email,
+			Phone: c.Phone,
+		})
+	}
+
+	if len(contacts) > 0 {
+		oe.Contact = &contacts
+	}
+	return oe
+}
+
 // renderVersion calls the official CDX serializer to render the BOM into a
-// specific version
-func (s *SerializerCDX) renderVersion(cdxVersion cdx.SpecVersion, doc interface{}, wr io.Writer) error {
+// specific version. The CycloneDX encoder only supports a fixed indentation
+// so the compact JSON it produces is reindented to honor the options.
+func (s *SerializerCDX) renderVersion(opts options.Options, cdxVersion cdx.SpecVersion, doc interface{}, wr io.Writer) error {
 	if doc == nil {
 		return errors.New("document is nil")
 	}
 
-	encoder := cdx.NewBOMEncoder(wr, cdx.BOMFileFormatJSON)
-	encoder.SetPretty(true)
+	var buf bytes.Buffer
+	encoder := cdx.NewBOMEncoder(&buf, cdx.BOMFileFormatJSON)
+	encoder.SetPretty(false)
 
 	if err := encoder.EncodeVersion(doc.(*cdx.BOM), cdxVersion); err != nil {
 		return fmt.Errorf("encoding sbom to stream: %w", err)
 	}
 
+	if opts.Indent > 0 {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf.Bytes(), "", strings.Repeat(" ", opts.Indent)); err != nil {
+			return fmt.Errorf("indenting sbom: %w", err)
+		}
+		buf = indented
+	}
+
+	if _, err := wr.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing sbom to stream: %w", err)
+	}
+
 	return nil
 }
 
 type serializerCDXState struct {
 	addedDict      map[string]struct{}
 	componentsDict map[string]*cdx.Component
+
+	// rootRef is the bom-ref of the metadata component
+	rootRef string
 }
 
 func newSerializerCDXState() *serializerCDXState {
@@ -1,6 +1,7 @@
 package writer
 
 import (
+	"context"
 	"io"
 
 	"github.com/bom-squad/protobom/pkg/writer/options"
@@ -14,7 +15,12 @@ type SerializerCDX14 struct {
 }
 
 // Render is a wrapper on top of the general CDX serializer
-func (s *SerializerCDX14) Render(_ options.Options, doc interface{}, wr io.Writer) error {
+func (s *SerializerCDX14) Render(opts options.Options, doc interface{}, wr io.Writer) error {
+	return s.RenderContext(context.Background(), opts, doc, wr)
+}
+
+// RenderContext works as Render but aborts when ctx is canceled
+func (s *SerializerCDX14) RenderContext(ctx context.Context, opts options.Options, doc interface{}, wr io.Writer) error {
 	// Call the global CycloneDX serializer method to render the doc
-	return s.renderVersion(cdx.SpecVersion1_4, doc, wr)
+	return s.renderVersion(opts, cdx.SpecVersion1_4, doc, wr)
 }
@@ -0,0 +1,369 @@
+package writer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+func twoRootDocument() *sbom.Document {
+	return &sbom.Document{
+		Metadata: &sbom.Metadata{Id: "urn:uuid:test", Name: "two roots"},
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				{Id: "root1", Name: "root1", PrimaryPurpose: "application"},
+				{Id: "root2", Name: "root2", PrimaryPurpose: "application"},
+				{Id: "dep", Name: "dep", PrimaryPurpose: "library"},
+			},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{"root1", "root2"},
+		},
+	}
+}
+
+func TestSerializeCDXRootScheme(t *testing.T) {
+	for name, tc := range map[string]struct {
+		scheme        options.CDXRootScheme
+		expectedRoot  string
+		expectedDeps  []string
+		expectedComps int
+	}{
+		"flat": {
+			scheme:        FlatRootScheme,
+			expectedRoot:  "root1",
+			expectedDeps:  nil,
+			expectedComps: 2,
+		},
+		"virtual": {
+			scheme:        VirtualRootScheme,
+			expectedRoot:  virtualRootRef,
+			expectedDeps:  []string{"root1", "root2"},
+			expectedComps: 3,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := options.Default
+			opts.CDXRootScheme = tc.scheme
+
+			s := &SerializerCDX{}
+			res, err := s.Serialize(opts, twoRootDocument())
+			require.NoError(t, err)
+
+			doc, ok := res.(*cdx.BOM)
+			require.True(t, ok)
+			require.NotNil(t, doc.Metadata.Component)
+			require.Equal(t, tc.expectedRoot, doc.Metadata.Component.BOMRef)
+			require.Len(t, *doc.Components, tc.expectedComps)
+
+			var rootDeps *cdx.Dependency
+			for i := range *doc.Dependencies {
+				if (*doc.Dependencies)[i].Ref == tc.expectedRoot {
+					rootDeps = &(*doc.Dependencies)[i]
+				}
+			}
+			if tc.expectedDeps == nil {
+				// The other roots are not made dependencies of the first
+				require.True(t, rootDeps == nil || rootDeps.Dependencies == nil || len(*rootDeps.Dependencies) == 0)
+			} else {
+				require.NotNil(t, rootDeps)
+				require.Equal(t, tc.expectedDeps, *rootDeps.Dependencies)
+			}
+
+			if name == "virtual" {
+				require.Equal(t, "two roots", doc.Metadata.Component.Name)
+			}
+
+			if name == "flat" {
+				for _, c := range *doc.Components {
+					require.NotEqual(t, virtualRootRef, c.BOMRef)
+				}
+				refs := []string{}
+				for _, d := range *doc.Dependencies {
+					require.NotEqual(t, virtualRootRef, d.Ref)
+					refs = append(refs, d.Ref)
+				}
+				// The extra root is still listed in the dependencies
+				require.Contains(t, refs, "root2")
+			}
+		})
+	}
+}
+
+func TestVirtualRootName(t *testing.T) {
+	doc := twoRootDocument()
+	doc.Metadata.Name = ""
+	root, _, err := VirtualRootScheme(doc.NodeList.RootNodes(), doc)
+	require.NoError(t, err)
+	require.Equal(t, "virtual root", root.Name)
+}
+
+func TestVirtualRootAggregateHashes(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Nodes[0].Hashes = map[string]string{
+		"SHA256": "A1B2",
+		"SHA1":   "ffff",
+	}
+	doc.NodeList.Nodes[1].Hashes = map[string]string{
+		"SHA256": "0c0d",
+	}
+
+	for _, tc := range []struct {
+		aggregate bool
+		expected  []cdx.Hash
+	}{
+		{false, nil},
+		{true, []cdx.Hash{{
+			Algorithm: cdx.HashAlgoSHA256,
+			// sha256("0c0d\na1b2"), SHA1 is missing in root2
+			Value: fmt.Sprintf("%x", sha256.Sum256([]byte("0c0d\na1b2"))),
+		}}},
+	} {
+		opts := options.Default
+		opts.CDXRootScheme = NewVirtualRootScheme(VirtualRootOptions{AggregateHashes: tc.aggregate})
+		res, err := (&SerializerCDX{}).Serialize(opts, doc)
+		require.NoError(t, err)
+
+		root := res.(*cdx.BOM).Metadata.Component
+		require.Equal(t, virtualRootRef, root.BOMRef)
+		if tc.expected == nil {
+			require.Nil(t, root.Hashes)
+			continue
+		}
+		require.NotNil(t, root.Hashes)
+		require.Equal(t, tc.expected, *root.Hashes)
+	}
+}
+
+func TestSerializeCDXCustomRootScheme(t *testing.T) {
+	// byName picks the root named root2 as the top level component
+	byName := func(roots []*sbom.Node, _ *sbom.Document) (*cdx.Component, []cdx.Dependency, error) {
+		for _, n := range roots {
+			if n.Name == "root2" {
+				return &cdx.Component{BOMRef: n.Id, Name: "custom " + n.Name}, nil, nil
+			}
+		}
+		return nil, nil, errors.New("root2 not found")
+	}
+
+	opts := options.Default
+	opts.CDXRootScheme = byName
+	s := &SerializerCDX{}
+	res, err := s.Serialize(opts, twoRootDocument())
+	require.NoError(t, err)
+
+	doc, ok := res.(*cdx.BOM)
+	require.True(t, ok)
+	require.Equal(t, "root2", doc.Metadata.Component.BOMRef)
+	require.Equal(t, "custom root2", doc.Metadata.Component.Name)
+	require.Len(t, *doc.Components, 2)
+	for _, c := range *doc.Components {
+		require.NotEqual(t, "root2", c.BOMRef)
+	}
+
+	// Errors in the scheme are returned by the serializer
+	opts.CDXRootScheme = func([]*sbom.Node, *sbom.Document) (*cdx.Component, []cdx.Dependency, error) {
+		return nil, nil, errors.New("scheme failed")
+	}
+	_, err = s.Serialize(opts, twoRootDocument())
+	require.Error(t, err)
+}
+
+func TestNodeToComponentSupplier(t *testing.T) {
+	s := &SerializerCDX{}
+	c := s.nodeToComponent(&sbom.Node{
+		Id:   "node1",
+		Name: "node1",
+		Suppliers: []*sbom.Person{
+			{Name: "ACME", IsOrg: true, Url: "https://example.com", Email: "info@example.com"},
+		},
+		Originators: []*sbom.Person{
+			{Name: "John Doe", Email: "john@example.com"},
+			{Name: "Jane Doe"},
+		},
+	})
+	require.NotNil(t, c.Supplier)
+	require.Equal(t, "ACME", c.Supplier.Name)
+	require.Equal(t, []string{"https://example.com"}, *c.Supplier.URL)
+	require.Equal(t, []cdx.OrganizationalContact{{Email: "info@example.com"}}, *c.Supplier.Contact)
+	require.Equal(t, "John Doe (john@example.com), Jane Doe", c.Author)
+}
+
+func TestSerializeCDXVulnerabilities(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Nodes[0].Vulnerabilities = []*sbom.Vulnerability{
+		{Id: "CVE-2023-0001", Status: sbom.Vulnerability_NOT_AFFECTED, Justification: "code_not_reachable"},
+		{Id: "CVE-2023-0002", Status: sbom.Vulnerability_AFFECTED},
+	}
+	doc.NodeList.Nodes[2].Vulnerabilities = []*sbom.Vulnerability{
+		{Id: "CVE-2023-0001", Status: sbom.Vulnerability_NOT_AFFECTED, Justification: "code_not_reachable"},
+	}
+
+	s := &SerializerCDX{}
+	res, err := s.Serialize(options.Default, doc)
+	require.NoError(t, err)
+
+	bom, ok := res.(*cdx.BOM)
+	require.True(t, ok)
+	require.NotNil(t, bom.Vulnerabilities)
+	require.Len(t, *bom.Vulnerabilities, 2)
+
+	notAffected := (*bom.Vulnerabilities)[0]
+	require.Equal(t, "CVE-2023-0001", notAffected.ID)
+	require.NotNil(t, notAffected.Analysis)
+	require.Equal(t, cdx.IASNotAffected, notAffected.Analysis.State)
+	require.Equal(t, cdx.IAJCodeNotReachable, notAffected.Analysis.Justification)
+	require.Equal(t, []cdx.Affects{{Ref: "root1"}, {Ref: "dep"}}, *notAffected.Affects)
+
+	affected := (*bom.Vulnerabilities)[1]
+	require.Equal(t, "CVE-2023-0002", affected.ID)
+	require.Equal(t, cdx.IASExploitable, affected.Analysis.State)
+	require.Equal(t, []cdx.Affects{{Ref: "root1"}}, *affected.Affects)
+	// The vulnerabilities are read back into the nodes
+	var buf bytes.Buffer
+	require.NoError(t, New().WriteStream(doc, nopWriteCloser{&buf}))
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	n := doc2.NodeList.GetNodeByID("dep")
+	require.NotNil(t, n)
+	require.Len(t, n.Vulnerabilities, 1)
+	require.Equal(t, sbom.Vulnerability_NOT_AFFECTED, n.Vulnerabilities[0].Status)
+	require.Equal(t, "code_not_reachable", n.Vulnerabilities[0].Justification)
+}
+
+func TestSerializeProperties(t *testing.T) {
+	props := map[string]string{"acme:build-id": "1234", "acme:pipeline": "release=nightly"}
+	for _, f := range []formats.Format{formats.CDX14JSON, formats.SPDX23JSON} {
+		doc := twoRootDocument()
+		doc.NodeList.Nodes[2].Properties = props
+
+		var buf bytes.Buffer
+		w := New()
+		w.Options.Format = f
+		require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}), string(f))
+
+		doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err, string(f))
+		n := doc2.NodeList.GetNodeByID("dep")
+		require.NotNil(t, n, string(f))
+		require.Equal(t, props, n.Properties, string(f))
+	}
+}
+
+func TestSerializeDocumentProperties(t *testing.T) {
+	props := map[string]string{"acme:build-id": "1234", "acme:commit": "5b3bd8fa01ea7c4e0ac6a1fd23c2d4b7b0ff2c1e"}
+	for _, f := range []formats.Format{formats.CDX14JSON, formats.SPDX23JSON} {
+		doc := twoRootDocument()
+		doc.Metadata.Properties = props
+
+		var buf bytes.Buffer
+		w := New()
+		w.Options.Format = f
+		require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}), string(f))
+
+		doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err, string(f))
+		require.Equal(t, props, doc2.Metadata.Properties, string(f))
+	}
+}
+
+func TestSerializeCDXBOMRefs(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "my lib", Name: "lib1", PrimaryPurpose: "library"})
+	doc.NodeList.AddNode(&sbom.Node{Id: "my%20lib", Name: "lib2", PrimaryPurpose: "library"})
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "dep", To: []string{"my lib", "my%20lib"}},
+	}
+
+	res, err := (&SerializerCDX{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+	bom := res.(*cdx.BOM)
+
+	// The valid ID is kept, the one with a space is escaped and made unique
+	deps := map[string][]string{}
+	for _, d := range *bom.Dependencies {
+		deps[d.Ref] = *d.Dependencies
+	}
+	require.Equal(t, []string{"my%20lib-2", "my%20lib"}, deps["dep"])
+
+	// The original document is not modified
+	require.Equal(t, "my lib", doc.NodeList.Nodes[3].Id)
+}
+
+func TestSerializeCDXDependencyGraph(t *testing.T) {
+	doc := &sbom.Document{
+		Metadata: &sbom.Metadata{Id: "urn:uuid:deps"},
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				{Id: "app", Name: "app", PrimaryPurpose: "application"},
+				{Id: "lib", Name: "lib", PrimaryPurpose: "library"},
+				{Id: "sub", Name: "sub", PrimaryPurpose: "library"},
+				{Id: "dep1", Name: "dep1", PrimaryPurpose: "library"},
+				{Id: "dep2", Name: "dep2", PrimaryPurpose: "library"},
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_contains, From: "app", To: []string{"lib", "dep1", "dep2"}},
+				{Type: sbom.Edge_contains, From: "lib", To: []string{"sub"}},
+				{Type: sbom.Edge_dependsOn, From: "app", To: []string{"lib"}},
+				{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"dep1"}},
+				// Edges of a nested component and of a dependency
+				{Type: sbom.Edge_dependsOn, From: "sub", To: []string{"dep2"}},
+				{Type: sbom.Edge_dependsOn, From: "dep1", To: []string{"dep2"}},
+			},
+			RootElements: []string{"app"},
+		},
+	}
+
+	res, err := (&SerializerCDX{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+	bom := res.(*cdx.BOM)
+
+	deps := map[string][]string{}
+	for _, d := range *bom.Dependencies {
+		require.NotContains(t, deps, d.Ref, "duplicate dependency ref")
+		deps[d.Ref] = *d.Dependencies
+	}
+	require.Equal(t, map[string][]string{
+		"app":  {"lib"},
+		"lib":  {"dep1"},
+		"sub":  {"dep2"},
+		"dep1": {"dep2"},
+		"dep2": {},
+	}, deps)
+
+	// Dependencies are still listed as components, sub is nested in lib
+	refs := []string{}
+	for _, c := range *bom.Components {
+		refs = append(refs, c.BOMRef)
+		if c.BOMRef == "lib" {
+			require.NotNil(t, c.Components)
+			require.Equal(t, "sub", (*c.Components)[0].BOMRef)
+		}
+	}
+	require.ElementsMatch(t, []string{"lib", "dep1", "dep2"}, refs)
+
+	// The graph survives a round trip
+	var buf bytes.Buffer
+	w := New()
+	w.Options.Format = formats.CDX14JSON
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	for _, e := range doc.NodeList.Edges {
+		for _, to := range e.To {
+			edge := doc2.NodeList.GetEdgeByType(e.From, e.Type)
+			require.NotNil(t, edge, "%s %s %s", e.From, e.Type, to)
+			require.Contains(t, edge.To, to, "%s %s %s", e.From, e.Type, to)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+// SerializerNDJSON writes the protobom as newline delimited JSON for
+// streaming consumers. Each node is written as a protobuf JSON object in its
+// own line. The last line is a document record carrying the metadata and the
+// graph (edges and root elements) of the document, without its nodes.
+type SerializerNDJSON struct{}
+
+// Serialize returns the protobom document unchanged
+func (s *SerializerNDJSON) Serialize(opts options.Options, bom *sbom.Document) (interface{}, error) {
+	return s.SerializeContext(context.Background(), opts, bom)
+}
+
+// SerializeContext works as Serialize but aborts when ctx is canceled
+func (s *SerializerNDJSON) SerializeContext(ctx context.Context, _ options.Options, bom *sbom.Document) (interface{}, error) {
+	if bom == nil {
+		return nil, errors.New("document is nil")
+	}
+	return bom, nil
+}
+
+// Render writes the document nodes and graph to wr, one record per line
+func (s *SerializerNDJSON) Render(opts options.Options, doc interface{}, wr io.Writer) error {
+	return s.RenderContext(context.Background(), opts, doc, wr)
+}
+
+// RenderContext works as Render but aborts when ctx is canceled
+func (s *SerializerNDJSON) RenderContext(ctx context.Context, opts options.Options, doc interface{}, wr io.Writer) error {
+	bom, ok := doc.(*sbom.Document)
+	if !ok {
+		return errors.New("document is not a protobom document")
+	}
+
+	nodeList := bom.GetNodeList()
+	progress := newProgressCounter(ctx, opts, len(nodeList.GetNodes()))
+	for _, n := range nodeList.GetNodes() {
+		if err := writeNDJSONRecord(wr, n); err != nil {
+			return fmt.Errorf("writing node %s: %w", n.Id, err)
+		}
+		if err := progress.step(); err != nil {
+			return err
+		}
+	}
+
+	graph := &sbom.Document{
+		Metadata: bom.Metadata,
+		NodeList: &sbom.NodeList{
+			Edges:        nodeList.GetEdges(),
+			RootElements: nodeList.GetRootElements(),
+		},
+	}
+	if err := writeNDJSONRecord(wr, graph); err != nil {
+		return fmt.Errorf("writing document graph: %w", err)
+	}
+	return nil
+}
+
+// writeNDJSONRecord writes a message as a single line of protobuf JSON
+func writeNDJSONRecord(wr io.Writer, m proto.Message) error {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshaling record: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return fmt.Errorf("compacting record: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	if _, err := wr.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+// SerializerNDJSONInventory writes the nodes of the document as newline
+// delimited JSON. Each line is an independent record with the node and a
+// summary of its outgoing edges, so consumers can ingest the inventory one
+// line at a time. Unlike SerializerNDJSON, the document metadata is not
+// written and the output cannot be read back into a protobom.
+type SerializerNDJSONInventory struct{}
+
+// ndjsonInventoryRecord is the record written for each node
+type ndjsonInventoryRecord struct {
+	Node  json.RawMessage     `json:"node"`
+	Root  bool                `json:"root,omitempty"`
+	Edges map[string][]string `json:"edges,omitempty"`
+}
+
+// Serialize returns the protobom document unchanged
+func (s *SerializerNDJSONInventory) Serialize(opts options.Options, bom *sbom.Document) (interface{}, error) {
+	return s.SerializeContext(context.Background(), opts, bom)
+}
+
+// SerializeContext works as Serialize but aborts when ctx is canceled
+func (s *SerializerNDJSONInventory) SerializeContext(ctx context.Context, _ options.Options, bom *sbom.Document) (interface{}, error) {
+	if bom == nil {
+		return nil, errors.New("document is nil")
+	}
+	return bom, nil
+}
+
+// Render writes one record per node to wr
+func (s *SerializerNDJSONInventory) Render(opts options.Options, doc interface{}, wr io.Writer) error {
+	return s.RenderContext(context.Background(), opts, doc, wr)
+}
+
+// RenderContext works as Render but aborts when ctx is canceled
+func (s *SerializerNDJSONInventory) RenderContext(ctx context.Context, opts options.Options, doc interface{}, wr io.Writer) error {
+	bom, ok := doc.(*sbom.Document)
+	if !ok {
+		return errors.New("document is not a protobom document")
+	}
+
+	nodeList := bom.GetNodeList()
+	roots := map[string]struct{}{}
+	for _, id := range nodeList.GetRootElements() {
+		roots[id] = struct{}{}
+	}
+
+	edges := map[string]map[string][]string{}
+	for _, e := range nodeList.GetEdges() {
+		if _, ok := edges[e.From]; !ok {
+			edges[e.From] = map[string][]string{}
+		}
+		edges[e.From][e.Type.String()] = append(edges[e.From][e.Type.String()], e.To...)
+	}
+
+	// json.Encoder terminates each record with a newline
+	encoder := json.NewEncoder(wr)
+	progress := newProgressCounter(ctx, opts, len(nodeList.GetNodes()))
+	for _, n := range nodeList.GetNodes() {
+		data, err := protojson.Marshal(n)
+		if err != nil {
+			return fmt.Errorf("marshaling node %s: %w", n.Id, err)
+		}
+
+		_, isRoot := roots[n.Id]
+		if err := encoder.Encode(ndjsonInventoryRecord{
+			Node:  data,
+			Root:  isRoot,
+			Edges: edges[n.Id],
+		}); err != nil {
+			return fmt.Errorf("writing node %s: %w", n.Id, err)
+		}
+		if err := progress.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
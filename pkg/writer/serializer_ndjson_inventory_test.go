@@ -0,0 +1,41 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+func TestSerializerNDJSONInventory(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "root1", To: []string{"dep"}},
+	}
+
+	w := New()
+	w.Options.Format = formats.PROTOBOMINVENTORYNDJSON
+
+	var buf bytes.Buffer
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+
+	records := []map[string]interface{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	for scanner.Scan() {
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, len(doc.NodeList.Nodes))
+
+	require.Equal(t, "root1", records[0]["node"].(map[string]interface{})["id"])
+	require.Equal(t, true, records[0]["root"])
+	require.Equal(t, map[string]interface{}{"dependsOn": []interface{}{"dep"}}, records[0]["edges"])
+	require.NotContains(t, records[2], "root")
+	require.NotContains(t, records[2], "edges")
+}
@@ -0,0 +1,41 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+	"github.com/bom-squad/protobom/pkg/sbom"
+)
+
+func TestSerializerNDJSON(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Nodes[0].Hashes = map[string]string{"SHA256": "abc"}
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "root1", To: []string{"dep"}},
+	}
+
+	w := New()
+	w.Options.Format = formats.PROTOBOMNDJSON
+
+	var buf bytes.Buffer
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+
+	// One line per node plus the graph record
+	lines := 0
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	for scanner.Scan() {
+		lines++
+	}
+	require.Equal(t, len(doc.NodeList.Nodes)+1, lines)
+
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.True(t, doc.NodeList.Equal(doc2.NodeList))
+	require.True(t, proto.Equal(doc.Metadata, doc2.Metadata))
+}
@@ -0,0 +1,54 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+// SerializerProtobomJSON writes the protobom document as protobuf JSON. As
+// the document is written as is, no data is lost in the serialization.
+type SerializerProtobomJSON struct{}
+
+// Serialize returns the protobom document unchanged
+func (s *SerializerProtobomJSON) Serialize(opts options.Options, bom *sbom.Document) (interface{}, error) {
+	return s.SerializeContext(context.Background(), opts, bom)
+}
+
+// SerializeContext works as Serialize but aborts when ctx is canceled
+func (s *SerializerProtobomJSON) SerializeContext(ctx context.Context, _ options.Options, bom *sbom.Document) (interface{}, error) {
+	if bom == nil {
+		return nil, errors.New("document is nil")
+	}
+	return bom, nil
+}
+
+// Render writes the document to wr as protobuf JSON
+func (s *SerializerProtobomJSON) Render(opts options.Options, doc interface{}, wr io.Writer) error {
+	return s.RenderContext(context.Background(), opts, doc, wr)
+}
+
+// RenderContext works as Render but aborts when ctx is canceled
+func (s *SerializerProtobomJSON) RenderContext(ctx context.Context, opts options.Options, doc interface{}, wr io.Writer) error {
+	bom, ok := doc.(*sbom.Document)
+	if !ok {
+		return errors.New("document is not a protobom document")
+	}
+
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", strings.Repeat(" ", opts.Indent))
+	if err := encoder.Encode(bom); err != nil {
+		return fmt.Errorf("encoding sbom to stream: %w", err)
+	}
+
+	// The document is encoded in one go, only its completion is reported
+	newProgressCounter(ctx, opts, len(bom.GetNodeList().GetNodes())).finish()
+
+	return nil
+}
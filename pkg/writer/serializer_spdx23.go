@@ -1,15 +1,20 @@
 package writer
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	protospdx "github.com/bom-squad/protobom/pkg/formats/spdx"
 	"github.com/bom-squad/protobom/pkg/sbom"
 	"github.com/bom-squad/protobom/pkg/writer/options"
+	"github.com/google/uuid"
 	"github.com/spdx/tools-golang/spdx"
 	"github.com/spdx/tools-golang/spdx/v2/common"
 	"github.com/spdx/tools-golang/spdx/v2/v2_3"
@@ -18,7 +23,65 @@ import (
 
 type SerializerSPDX23 struct{}
 
+// defaultSPDXNamespace is used when no namespace is set in the options
+const defaultSPDXNamespace = "https://spdx.org/spdxdocs/" // TODO(puerco): Think how to handle namespacing
+
+// defaultLicenseListVersion is the SPDX license list version written when
+// the document does not record one. Ref: https://spdx.org/licenses/
+const defaultLicenseListVersion = "3.20"
+
+// validateSPDXNamespace checks that the namespace is an absolute URI without
+// a fragment as required by the SPDX spec.
+// Ref: https://spdx.github.io/spdx-spec/v2.3/document-creation-information/#65-spdx-document-namespace-field
+func validateSPDXNamespace(namespace string) error {
+	u, err := url.Parse(namespace)
+	if err != nil {
+		return fmt.Errorf("parsing namespace URI: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("namespace %q is not an absolute URI", namespace)
+	}
+	if strings.Contains(namespace, "#") {
+		return fmt.Errorf("namespace %q must not contain a fragment", namespace)
+	}
+	return nil
+}
+
+// spdxNamespace returns the document namespace configured in the options.
+// A full namespace takes precedence, otherwise if a base URI is set the
+// namespace is generated as base/name-suffix where the suffix defaults to
+// a random UUID.
+func spdxNamespace(opts options.Options, name string) (string, error) {
+	namespace := defaultSPDXNamespace
+	switch {
+	case opts.SPDXNamespace != "":
+		namespace = opts.SPDXNamespace
+	case opts.SPDXNamespaceBase != "":
+		suffix := opts.SPDXNamespaceSuffix
+		if suffix == "" {
+			suffix = uuid.NewString()
+		}
+		if name != "" {
+			suffix = url.PathEscape(name) + "-" + suffix
+		}
+		namespace = strings.TrimSuffix(opts.SPDXNamespaceBase, "/") + "/" + suffix
+	default:
+		return namespace, nil
+	}
+
+	if err := validateSPDXNamespace(namespace); err != nil {
+		return "", err
+	}
+	return namespace, nil
+}
+
+// Render writes the native document to wr
 func (s *SerializerSPDX23) Render(opts options.Options, doc interface{}, wr io.Writer) error {
+	return s.RenderContext(context.Background(), opts, doc, wr)
+}
+
+// RenderContext works as Render but aborts when ctx is canceled
+func (s *SerializerSPDX23) RenderContext(ctx context.Context, opts options.Options, doc interface{}, wr io.Writer) error {
 	encoder := json.NewEncoder(wr)
 	encoder.SetIndent("", strings.Repeat(" ", opts.Indent))
 	if err := encoder.Encode(doc.(*spdx.Document)); err != nil {
@@ -30,31 +93,53 @@ func (s *SerializerSPDX23) Render(opts options.Options, doc interface{}, wr io.W
 
 // Serialize takes a protobom and returns an SPDX 2.3 struct
 func (s *SerializerSPDX23) Serialize(opts options.Options, bom *sbom.Document) (interface{}, error) {
+	return s.SerializeContext(context.Background(), opts, bom)
+}
+
+// SerializeContext works as Serialize but aborts when ctx is canceled
+func (s *SerializerSPDX23) SerializeContext(ctx context.Context, opts options.Options, bom *sbom.Document) (interface{}, error) {
+	name := bom.Metadata.Name
+	if opts.DocumentName != "" {
+		name = opts.DocumentName
+	}
+
+	namespace, err := spdxNamespace(opts, name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPDX namespace: %w", err)
+	}
+
+	protobomCreator := fmt.Sprintf("protobom-%s", version.GetVersionInfo().GitVersion)
 	doc := &spdx.Document{
 		SPDXVersion:       spdx.Version,
 		DataLicense:       spdx.DataLicense,
 		SPDXIdentifier:    protospdx.DOCUMENT,
-		DocumentName:      bom.Metadata.Name,
-		DocumentNamespace: "https://spdx.org/spdxdocs/", // TODO(puerco): Think how to handle namespacing
+		DocumentName:      name,
+		DocumentNamespace: namespace,
 		DocumentComment:   bom.Metadata.Comment,
 
 		CreationInfo: &spdx.CreationInfo{
-			LicenseListVersion: "3.20", // https://spdx.org/licenses/
+			LicenseListVersion: defaultLicenseListVersion,
 			Creators: []spdx.Creator{
 				// Register protobom as one of the document creation tools
 				{
-					Creator:     fmt.Sprintf("protobom-%s", version.GetVersionInfo().GitVersion),
+					Creator:     protobomCreator,
 					CreatorType: "Tool",
 				},
 			},
 
-			// Interesting, should we keep the original date?
-			Created: time.Now().UTC().Format(time.RFC3339),
-			// CreatorComment: bom.Metadata.Authors(),
+			Created: protospdx.FormatDate(time.Now()),
 			// CreatorComment: bom.Metadata.... /// TODO(puerco): Missing in the proto
 		},
 	}
 
+	// Keep the creation info of documents read from other formats
+	if bom.Metadata.Date != nil {
+		doc.CreationInfo.Created = protospdx.FormatDate(bom.Metadata.Date.AsTime())
+	}
+	if bom.Metadata.LicenseListVersion != "" {
+		doc.CreationInfo.LicenseListVersion = bom.Metadata.LicenseListVersion
+	}
+
 	for _, t := range bom.Metadata.Tools {
 		// TODO(degradation): SPDX is prescriptive on how this field is structured
 		// it is a tool identifier word separated from the version with a dash.
@@ -65,6 +150,11 @@ func (s *SerializerSPDX23) Serialize(opts options.Options, bom *sbom.Document) (
 			name = fmt.Sprintf("%s-%s", t.Name, t.Version)
 		}
 
+		// Documents read from protobom output already list it
+		if name == protobomCreator {
+			continue
+		}
+
 		// TODO(degradation): Tool vendor gets lost here
 
 		doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, spdx.Creator{
@@ -73,25 +163,48 @@ func (s *SerializerSPDX23) Serialize(opts options.Options, bom *sbom.Document) (
 		})
 	}
 
-	packages, err := buildPackages(bom)
+	for _, a := range bom.Metadata.Authors {
+		if a.Name == "" {
+			continue
+		}
+		// TODO(degradation): Author URL, phone and contacts get lost here
+		doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, spdx.Creator{
+			Creator:     a.ToSPDX2ClientString(),
+			CreatorType: a.ToSPDX2ClientOrg(),
+		})
+	}
+
+	for _, a := range propertiesToAnnotations(bom.Metadata.Properties, doc.CreationInfo.Created) {
+		a := a
+		a.AnnotationSPDXIdentifier = common.MakeDocElementID("", protospdx.DOCUMENT)
+		doc.Annotations = append(doc.Annotations, &a)
+	}
+
+	ids := newSPDXIDs(bom.NodeList)
+
+	progress := newProgressCounter(ctx, opts, len(bom.NodeList.Nodes))
+	packages, err := buildPackages(bom, ids, doc.CreationInfo.Created, progress)
 	if err != nil {
-		return nil, fmt.Errorf("building SPDX packages: %s", err)
+		return nil, fmt.Errorf("building SPDX packages: %w", err)
 	}
 
-	files, err := buildFiles(bom)
+	files, err := buildFiles(bom, ids, doc.CreationInfo.Created, progress)
 	if err != nil {
-		return nil, fmt.Errorf("building SPDX file list: %s", err)
+		return nil, fmt.Errorf("building SPDX file list: %w", err)
 	}
 
-	rels, err := buildRelationships(bom)
+	snippets := buildSnippets(bom, ids)
+
+	rels, err := buildRelationships(bom, ids)
 	if err != nil {
 		return nil, fmt.Errorf("building relationships: %w", err)
 	}
 
-	for _, id := range bom.NodeList.RootElements {
+	// SPDX requires the document to describe at least one element
+	for _, id := range bom.NodeList.DescribedElements() {
 		rels = append(rels, &spdx.Relationship{
 			RefA:                common.MakeDocElementID("", protospdx.DOCUMENT),
-			RefB:                common.MakeDocElementID("", id),
+			RefB:                common.MakeDocElementID("", string(ids.get(id))),
 			Relationship:        common.TypeRelationshipDescribe,
 			RelationshipComment: "",
 		})
@@ -100,22 +213,148 @@ func (s *SerializerSPDX23) Serialize(opts options.Options, bom *sbom.Document) (
 	// TODO(puerco): Files in packages
 	// TODO(puerco): Package verification data
 
+	if opts.SortComponents {
+		sortSPDXElements(packages, files, rels)
+	}
+
 	doc.Packages = packages
 	doc.Files = files
+	doc.Snippets = snippets
 	doc.Relationships = rels
 
 	return doc, nil
 }
 
-func buildRelationships(bom *sbom.Document) ([]*spdx.Relationship, error) { //nolint:unparam
+// sortSPDXElements sorts the packages by purl (or name@version when they
+// have no purl), the files by name and the relationships by their elements
+// and type.
+func sortSPDXElements(packages []*spdx.Package, files []*spdx.File, rels []*spdx.Relationship) {
+	packageKey := func(p *spdx.Package) string {
+		for _, r := range p.PackageExternalReferences {
+			if r.RefType == common.TypePackageManagerPURL {
+				return r.Locator
+			}
+		}
+		return p.PackageName + "@" + p.PackageVersion
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		ki, kj := packageKey(packages[i]), packageKey(packages[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return packages[i].PackageSPDXIdentifier < packages[j].PackageSPDXIdentifier
+	})
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].FileName != files[j].FileName {
+			return files[i].FileName < files[j].FileName
+		}
+		return files[i].FileSPDXIdentifier < files[j].FileSPDXIdentifier
+	})
+
+	sort.SliceStable(rels, func(i, j int) bool {
+		if rels[i].RefA.ElementRefID != rels[j].RefA.ElementRefID {
+			return rels[i].RefA.ElementRefID < rels[j].RefA.ElementRefID
+		}
+		if rels[i].Relationship != rels[j].Relationship {
+			return rels[i].Relationship < rels[j].Relationship
+		}
+		return rels[i].RefB.ElementRefID < rels[j].RefB.ElementRefID
+	})
+}
+
+// spdxIDPrefix is the prefix the SPDX libraries add to element IDs
+const spdxIDPrefix = "SPDXRef-"
+
+// spdxInvalidIDChars matches the characters not allowed in SPDX element IDs
+var spdxInvalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+// spdxIDs maps the node identifiers to valid and unique SPDX element IDs
+type spdxIDs struct {
+	ids  map[string]common.ElementID
+	used map[common.ElementID]struct{}
+}
+
+// newSPDXIDs assigns an SPDX element ID to every node in the nodelist. Node
+// IDs which are already valid are kept as they are, the rest are sanitized
+// and suffixed with a counter if the sanitized ID is already taken.
+func newSPDXIDs(nl *sbom.NodeList) *spdxIDs {
+	s := &spdxIDs{
+		ids:  map[string]common.ElementID{},
+		used: map[common.ElementID]struct{}{protospdx.DOCUMENT: {}},
+	}
+	if nl == nil {
+		return s
+	}
+
+	invalid := []string{}
+	for _, n := range nl.Nodes {
+		if sanitizeSPDXID(n.Id) != n.Id {
+			invalid = append(invalid, n.Id)
+			continue
+		}
+		if _, ok := s.used[common.ElementID(n.Id)]; ok {
+			invalid = append(invalid, n.Id)
+			continue
+		}
+		s.ids[n.Id] = common.ElementID(n.Id)
+		s.used[common.ElementID(n.Id)] = struct{}{}
+	}
+
+	for _, id := range invalid {
+		if _, ok := s.ids[id]; !ok {
+			s.ids[id] = s.reserve(sanitizeSPDXID(id))
+		}
+	}
+	return s
+}
+
+// sanitizeSPDXID replaces the characters not valid in SPDX IDs with dashes
+func sanitizeSPDXID(id string) string {
+	id = spdxInvalidIDChars.ReplaceAllString(strings.TrimPrefix(id, spdxIDPrefix), "-")
+	if id == "" {
+		return "node"
+	}
+	return id
+}
+
+// reserve returns an unused element ID based on id
+func (s *spdxIDs) reserve(id string) common.ElementID {
+	candidate := common.ElementID(id)
+	for i := 2; ; i++ {
+		if _, ok := s.used[candidate]; !ok {
+			break
+		}
+		candidate = common.ElementID(fmt.Sprintf("%s-%d", id, i))
+	}
+	s.used[candidate] = struct{}{}
+	return candidate
+}
+
+// get returns the SPDX element ID of a node. IDs not in the nodelist are
+// only sanitized.
+func (s *spdxIDs) get(id string) common.ElementID {
+	if eid, ok := s.ids[id]; ok {
+		return eid
+	}
+	return common.ElementID(sanitizeSPDXID(id))
+}
+
+func buildRelationships(bom *sbom.Document, ids *spdxIDs) ([]*spdx.Relationship, error) { //nolint:unparam
 	relationships := []*spdx.Relationship{}
 	for _, e := range bom.NodeList.Edges {
 		for _, dest := range e.To {
 			rel := spdx.Relationship{
-				RefA:         common.MakeDocElementID("", e.From),
-				RefB:         common.MakeDocElementID("", dest),
+				RefA:         common.MakeDocElementID("", string(ids.get(e.From))),
+				RefB:         common.MakeDocElementID("", string(ids.get(dest))),
 				Relationship: e.Type.ToSPDX2(),
-				// RelationshipComment: "",
+			}
+
+			// Labeled relationships are written as OTHER with the
+			// original relationship name in the comment
+			if (e.Type == sbom.Edge_UNKNOWN || e.Type == sbom.Edge_other) && e.Label != "" {
+				rel.Relationship = sbom.Edge_other.ToSPDX2()
+				rel.RelationshipComment = e.Label
 			}
 			relationships = append(relationships, &rel)
 		}
@@ -123,16 +362,57 @@ func buildRelationships(bom *sbom.Document) ([]*spdx.Relationship, error) { //no
 	return relationships, nil
 }
 
-func buildFiles(bom *sbom.Document) ([]*spdx.File, error) { //nolint:unparam
+// buildSnippets returns a snippet for each file node with a source location
+func buildSnippets(bom *sbom.Document, ids *spdxIDs) []spdx.Snippet {
+	snippets := []spdx.Snippet{}
+	for _, node := range bom.NodeList.Nodes {
+		if node.Type != sbom.Node_FILE || node.Location == nil {
+			continue
+		}
+
+		fileID := ids.get(node.Id)
+		snippet := spdx.Snippet{
+			SnippetFromFileSPDXIdentifier: fileID,
+			Ranges:                        []common.SnippetRange{},
+			SnippetLicenseConcluded:       protospdx.NOASSERTION,
+			SnippetCopyrightText:          protospdx.NOASSERTION,
+		}
+
+		// SPDX ranges are either byte or line ranges, so we emit one of each
+		if node.Location.HasBytes() {
+			snippet.Ranges = append(snippet.Ranges, common.SnippetRange{
+				StartPointer: common.SnippetRangePointer{Offset: int(node.Location.StartByte), FileSPDXIdentifier: fileID},
+				EndPointer:   common.SnippetRangePointer{Offset: int(node.Location.EndByte), FileSPDXIdentifier: fileID},
+			})
+		}
+		if node.Location.HasLines() {
+			snippet.Ranges = append(snippet.Ranges, common.SnippetRange{
+				StartPointer: common.SnippetRangePointer{LineNumber: int(node.Location.StartLine), FileSPDXIdentifier: fileID},
+				EndPointer:   common.SnippetRangePointer{LineNumber: int(node.Location.EndLine), FileSPDXIdentifier: fileID},
+			})
+		}
+		if len(snippet.Ranges) == 0 {
+			continue
+		}
+		snippet.SnippetSPDXIdentifier = ids.reserve(string(fileID) + "-snippet")
+		snippets = append(snippets, snippet)
+	}
+	return snippets
+}
+
+func buildFiles(bom *sbom.Document, ids *spdxIDs, created string, progress *progressCounter) ([]*spdx.File, error) {
 	files := []*spdx.File{}
 	for _, node := range bom.NodeList.Nodes {
-		if node.Type == sbom.Node_PACKAGE {
+		if node.Type != sbom.Node_FILE {
 			continue
 		}
+		if err := progress.step(); err != nil {
+			return nil, err
+		}
 
 		f := spdx.File{
 			FileName:           node.Name,
-			FileSPDXIdentifier: common.ElementID(node.Id),
+			FileSPDXIdentifier: ids.get(node.Id),
 			FileTypes:          node.FileTypes,
 			Checksums:          []common.Checksum{},
 			LicenseConcluded:   node.LicenseConcluded,
@@ -149,6 +429,8 @@ func buildFiles(bom *sbom.Document) ([]*spdx.File, error) { //nolint:unparam
 			f.FileCopyrightText = protospdx.NONE
 		}
 
+		f.Annotations = propertiesToAnnotations(node.Properties, created)
+
 		for algo, hash := range node.Hashes {
 			if algoVal, ok := sbom.HashAlgorithm_value[algo]; ok {
 				spdxAlgo := sbom.HashAlgorithm(algoVal).ToSPDX()
@@ -167,17 +449,45 @@ func buildFiles(bom *sbom.Document) ([]*spdx.File, error) { //nolint:unparam
 	return files, nil
 }
 
-func buildPackages(bom *sbom.Document) ([]*spdx.Package, error) { //nolint:unparam
+// propertiesToAnnotations records the node properties as SPDX annotations
+// made by the protobom tool, sorted by key. They are dated with the
+// document creation time so that the output is reproducible.
+func propertiesToAnnotations(props map[string]string, date string) []v2_3.Annotation {
+	annotations := []v2_3.Annotation{}
+	keys := []string{}
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		annotations = append(annotations, v2_3.Annotation{
+			Annotator: common.Annotator{
+				Annotator:     protospdx.PropertyAnnotator,
+				AnnotatorType: protospdx.Tool,
+			},
+			AnnotationDate:    date,
+			AnnotationType:    protospdx.AnnotationTypeOther,
+			AnnotationComment: fmt.Sprintf("%s=%s", k, props[k]),
+		})
+	}
+	return annotations
+}
+
+func buildPackages(bom *sbom.Document, ids *spdxIDs, created string, progress *progressCounter) ([]*spdx.Package, error) {
 	packages := []*spdx.Package{}
 	for _, node := range bom.NodeList.Nodes {
 		if node.Type == sbom.Node_FILE {
 			continue
 		}
+		if err := progress.step(); err != nil {
+			return nil, err
+		}
 
 		p := spdx.Package{
 			IsUnpackaged:          false,
 			PackageName:           node.Name,
-			PackageSPDXIdentifier: common.ElementID(node.Id),
+			PackageSPDXIdentifier: ids.get(node.Id),
 			PackageVersion:        node.Version,
 			PackageFileName:       node.FileName,
 			// PackageSupplier:             &common.Supplier{},
@@ -191,16 +501,15 @@ func buildPackages(bom *sbom.Document) ([]*spdx.Package, error) { //nolint:unpar
 			PackageSourceInfo:           node.SourceInfo,
 			PackageLicenseConcluded:     node.LicenseConcluded,
 			PackageLicenseInfoFromFiles: []string{},
-			// PackageLicenseDeclared:      node.Licenses[0],
-			PackageLicenseComments:    node.LicenseComments,
-			PackageCopyrightText:      strings.TrimSpace(node.Copyright),
-			PackageSummary:            node.Summary,
-			PackageDescription:        node.Description,
-			PackageComment:            node.Comment,
-			PackageExternalReferences: []*v2_3.PackageExternalReference{},
-			PackageAttributionTexts:   node.Attribution,
-			PrimaryPackagePurpose:     node.PrimaryPurpose,
-			Annotations:               []v2_3.Annotation{},
+			PackageLicenseComments:      node.LicenseComments,
+			PackageCopyrightText:        strings.TrimSpace(node.Copyright),
+			PackageSummary:              node.Summary,
+			PackageDescription:          node.Description,
+			PackageComment:              node.Comment,
+			PackageExternalReferences:   []*v2_3.PackageExternalReference{},
+			PackageAttributionTexts:     node.Attribution,
+			PrimaryPackagePurpose:       sbom.PurposeToSPDX(node.PrimaryPurpose),
+			Annotations:                 []v2_3.Annotation{},
 
 			// The files field may never be used... Or should it?
 			// We are mirroring the protbom graph in the SPDX relationship
@@ -213,22 +522,38 @@ func buildPackages(bom *sbom.Document) ([]*spdx.Package, error) { //nolint:unpar
 			// Files:                       []*v2_3.File{},
 		}
 
+		// SPDX has a single declared license field, multiple licenses
+		// are joined in a conjunctive expression
+		switch len(node.Licenses) {
+		case 0:
+		case 1:
+			p.PackageLicenseDeclared = node.Licenses[0]
+		default:
+			licenses := []string{}
+			for _, l := range node.Licenses {
+				licenses = append(licenses, fmt.Sprintf("(%s)", l))
+			}
+			p.PackageLicenseDeclared = strings.Join(licenses, " AND ")
+		}
+
 		if node.ReleaseDate != nil {
-			p.ReleaseDate = node.ReleaseDate.String()
+			p.ReleaseDate = protospdx.FormatDate(node.ReleaseDate.AsTime())
 		}
 
 		if node.BuildDate != nil {
-			p.BuiltDate = node.BuildDate.String()
+			p.BuiltDate = protospdx.FormatDate(node.BuildDate.AsTime())
 		}
 
 		if node.ValidUntilDate != nil {
-			p.ValidUntilDate = node.ValidUntilDate.String()
+			p.ValidUntilDate = protospdx.FormatDate(node.ValidUntilDate.AsTime())
 		}
 
 		if p.PackageDownloadLocation == "" {
 			p.PackageDownloadLocation = protospdx.NOASSERTION
 		}
 
+		p.Annotations = propertiesToAnnotations(node.Properties, created)
+
 		for algo, hash := range node.Hashes {
 			if algoVal, ok := sbom.HashAlgorithm_value[algo]; ok {
 				spdxAlgo := sbom.HashAlgorithm(algoVal).ToSPDX()
@@ -277,9 +602,9 @@ func buildPackages(bom *sbom.Document) ([]*spdx.Package, error) { //nolint:unpar
 		if len(node.Originators) > 0 {
 			// TODO(degradation): URL, Phone are lost if set
 			// TODO(degradation): If is more than one originator, it will be lost
-			p.PackageSupplier = &spdx.Supplier{
-				Supplier:     node.Originators[0].ToSPDX2ClientString(),
-				SupplierType: node.Originators[0].ToSPDX2ClientOrg(),
+			p.PackageOriginator = &spdx.Originator{
+				Originator:     node.Originators[0].ToSPDX2ClientString(),
+				OriginatorType: node.Originators[0].ToSPDX2ClientOrg(),
 			}
 		}
 
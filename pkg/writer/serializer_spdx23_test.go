@@ -0,0 +1,237 @@
+package writer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spdx/tools-golang/spdx"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+func TestSerializeSPDXNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		opts              []Option
+		expectedNamespace string
+		expectedName      string
+		shouldErr         bool
+	}{
+		{"defaults", nil, defaultSPDXNamespace, "two roots", false},
+		{
+			"custom",
+			[]Option{WithSPDXNamespace("https://example.com/sboms/abc"), WithDocumentName("my sbom")},
+			"https://example.com/sboms/abc", "my sbom", false,
+		},
+		{
+			"base with fixed suffix",
+			[]Option{WithSPDXNamespaceBase("https://example.com/sboms/"), WithSPDXNamespaceSuffix("v1.0.0")},
+			"https://example.com/sboms/two%20roots-v1.0.0", "two roots", false,
+		},
+		{
+			"full namespace wins over base",
+			[]Option{WithSPDXNamespace("https://example.com/sboms/abc"), WithSPDXNamespaceBase("https://example.org")},
+			"https://example.com/sboms/abc", "two roots", false,
+		},
+		{"relative base", []Option{WithSPDXNamespaceBase("sboms")}, "", "", true},
+		{"relative", []Option{WithSPDXNamespace("sboms/abc")}, "", "", true},
+		{"fragment", []Option{WithSPDXNamespace("https://example.com/sboms#abc")}, "", "", true},
+	} {
+		w := New(tc.opts...)
+		w.Options.Format = formats.SPDX23JSON
+		s := &SerializerSPDX23{}
+		res, err := s.Serialize(w.Options, twoRootDocument())
+		if tc.shouldErr {
+			require.Error(t, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		doc, ok := res.(*spdx.Document)
+		require.True(t, ok)
+		require.Equal(t, tc.expectedNamespace, doc.DocumentNamespace, tc.name)
+		require.Equal(t, tc.expectedName, doc.DocumentName, tc.name)
+	}
+}
+
+func TestSerializeSPDXNamespaceBase(t *testing.T) {
+	w := New(WithSPDXNamespaceBase("https://example.com/sboms"), WithDocumentName("app"))
+	namespaces := []string{}
+	for i := 0; i < 2; i++ {
+		res, err := (&SerializerSPDX23{}).Serialize(w.Options, twoRootDocument())
+		require.NoError(t, err)
+		namespaces = append(namespaces, res.(*spdx.Document).DocumentNamespace)
+		require.True(t, strings.HasPrefix(namespaces[i], "https://example.com/sboms/app-"))
+	}
+
+	// Without a fixed suffix every document gets a unique namespace
+	require.NotEqual(t, namespaces[0], namespaces[1])
+}
+
+func TestSerializeSPDXIDs(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "pkg/lib", Name: "lib1"})
+	doc.NodeList.AddNode(&sbom.Node{Id: "pkg_lib", Name: "lib2"})
+	doc.NodeList.AddNode(&sbom.Node{Id: "pkg-lib", Name: "lib3"})
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_contains, From: "root1", To: []string{"pkg/lib", "pkg_lib"}},
+		{Type: sbom.Edge_dependsOn, From: "pkg_lib", To: []string{"pkg-lib"}},
+	}
+
+	res, err := (&SerializerSPDX23{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+	spdxDoc := res.(*spdx.Document)
+
+	names := map[string]string{}
+	for _, p := range spdxDoc.Packages {
+		require.Regexp(t, `^[a-zA-Z0-9.-]+$`, string(p.PackageSPDXIdentifier))
+		require.NotContains(t, names, string(p.PackageSPDXIdentifier))
+		names[string(p.PackageSPDXIdentifier)] = p.PackageName
+	}
+	require.Len(t, names, 6)
+
+	// The already valid ID is kept and the others get distinct IDs
+	require.Equal(t, "lib3", names["pkg-lib"])
+
+	rels := []string{}
+	for _, r := range spdxDoc.Relationships {
+		if r.RefA.ElementRefID == "DOCUMENT" {
+			continue
+		}
+		rels = append(rels, names[string(r.RefA.ElementRefID)]+" "+r.Relationship+" "+names[string(r.RefB.ElementRefID)])
+	}
+	require.ElementsMatch(t, []string{
+		"root1 CONTAINS lib1",
+		"root1 CONTAINS lib2",
+		"lib2 DEPENDS_ON lib3",
+	}, rels)
+}
+
+func TestSerializeSPDXDescribes(t *testing.T) {
+	doc := sbom.NewDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "app", Name: "app"})
+	doc.NodeList.AddNode(&sbom.Node{Id: "lib", Name: "lib"})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "app", To: []string{"lib"}})
+	doc.NodeList.RootElements = []string{"missing"}
+
+	res, err := (&SerializerSPDX23{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+
+	described := []string{}
+	for _, r := range res.(*spdx.Document).Relationships {
+		if r.Relationship == "DESCRIBES" {
+			require.Equal(t, "DOCUMENT", string(r.RefA.ElementRefID))
+			described = append(described, string(r.RefB.ElementRefID))
+		}
+	}
+	require.Equal(t, []string{"app"}, described)
+
+	// The document being written is not modified
+	require.Equal(t, []string{"missing"}, doc.NodeList.RootElements)
+}
+
+func TestSerializeSPDXSnippets(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.AddNode(&sbom.Node{
+		Id:       "File-main",
+		Type:     sbom.Node_FILE,
+		Name:     "main.go",
+		Location: &sbom.SourceLocation{StartByte: 230, EndByte: 310},
+	})
+
+	var buf bytes.Buffer
+	w := New()
+	w.Options.Format = formats.SPDX23JSON
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	n := doc2.NodeList.GetNodeByID("File-main")
+	require.NotNil(t, n)
+	require.NotNil(t, n.Location)
+	require.Equal(t, int64(230), n.Location.StartByte)
+	require.Equal(t, int64(310), n.Location.EndByte)
+	require.False(t, n.Location.HasLines())
+}
+
+func TestSerializeSPDXEdgeLabels(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_other, From: "root1", To: []string{"dep"}, Label: "bundles"},
+		{Type: sbom.Edge_UNKNOWN, From: "root2", To: []string{"dep"}, Label: "VENDORED_FROM"},
+	}
+
+	var buf bytes.Buffer
+	w := New()
+	w.Options.Format = formats.SPDX23JSON
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	labels := map[string]string{}
+	for _, e := range doc2.NodeList.Edges {
+		require.Equal(t, sbom.Edge_other, e.Type)
+		labels[e.From] = e.Label
+	}
+	require.Equal(t, map[string]string{"root1": "bundles", "root2": "VENDORED_FROM"}, labels)
+}
+
+func TestSerializeSPDXCreationInfo(t *testing.T) {
+	doc := twoRootDocument()
+	created := time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)
+	doc.Metadata.Date = timestamppb.New(created)
+	doc.Metadata.LicenseListVersion = "3.21"
+	doc.Metadata.Authors = []*sbom.Person{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "Example Inc", IsOrg: true},
+	}
+
+	var buf bytes.Buffer
+	w := New()
+	w.Options.Format = formats.SPDX23JSON
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+	require.Contains(t, buf.String(), `"Person: Jane Doe (jane@example.com)"`)
+	require.Contains(t, buf.String(), `"Organization: Example Inc"`)
+
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, created, doc2.Metadata.Date.AsTime())
+	require.Equal(t, "3.21", doc2.Metadata.LicenseListVersion)
+	require.Len(t, doc2.Metadata.Authors, 2)
+	for i, a := range doc.Metadata.Authors {
+		require.Equal(t, a.Name, doc2.Metadata.Authors[i].Name)
+		require.Equal(t, a.Email, doc2.Metadata.Authors[i].Email)
+		require.Equal(t, a.IsOrg, doc2.Metadata.Authors[i].IsOrg)
+	}
+}
+
+func TestSerializeSPDXAnnotationDates(t *testing.T) {
+	doc := twoRootDocument()
+	doc.Metadata.Date = timestamppb.New(time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC))
+	doc.Metadata.Properties = map[string]string{"acme:build-id": "1234"}
+	doc.NodeList.Nodes[2].Properties = map[string]string{"acme:pipeline": "nightly"}
+
+	res, err := (&SerializerSPDX23{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+	spdxDoc, ok := res.(*spdx.Document)
+	require.True(t, ok)
+
+	// Annotations are dated with the document creation time
+	require.Len(t, spdxDoc.Annotations, 1)
+	require.Equal(t, "2023-06-01T12:30:00Z", spdxDoc.Annotations[0].AnnotationDate)
+	annotated := 0
+	for _, p := range spdxDoc.Packages {
+		for _, a := range p.Annotations {
+			require.Equal(t, "2023-06-01T12:30:00Z", a.AnnotationDate)
+			annotated++
+		}
+	}
+	require.Equal(t, 1, annotated)
+}
@@ -1,21 +1,136 @@
 package writer
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
 	"github.com/bom-squad/protobom/pkg/sbom"
 	"github.com/bom-squad/protobom/pkg/writer/options"
 )
 
 type Option func(*Writer)
 
-func New() *Writer {
-	return &Writer{
+func New(opts ...Option) *Writer {
+	w := &Writer{
 		impl:    &defaultWriterImplementation{},
 		Options: options.Default,
 	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// WithIndent sets the number of spaces used to indent the written documents
+func WithIndent(indent int) Option {
+	return func(w *Writer) {
+		w.Options.Indent = indent
+	}
+}
+
+// WithCompact makes the writer emit minified documents without indentation
+func WithCompact() Option {
+	return func(w *Writer) {
+		w.Options.Indent = 0
+	}
+}
+
+// WithCompression sets the compression algorithm used to write documents
+func WithCompression(c options.Compression) Option {
+	return func(w *Writer) {
+		w.Options.Compression = c
+	}
+}
+
+// WithCDXRootScheme sets the scheme used to express multiple root
+// elements when writing CycloneDX documents
+func WithCDXRootScheme(scheme options.CDXRootScheme) Option {
+	return func(w *Writer) {
+		w.Options.CDXRootScheme = scheme
+	}
+}
+
+// WithSPDXNamespace sets the document namespace of SPDX documents. The
+// namespace must be an absolute URI, it is validated when writing.
+func WithSPDXNamespace(namespace string) Option {
+	return func(w *Writer) {
+		w.Options.SPDXNamespace = namespace
+	}
+}
+
+// WithSPDXNamespaceBase makes the SPDX serializer generate the document
+// namespace from a base URI as base/name-uuid. It has no effect when a full
+// namespace is set with WithSPDXNamespace.
+func WithSPDXNamespaceBase(base string) Option {
+	return func(w *Writer) {
+		w.Options.SPDXNamespaceBase = base
+	}
+}
+
+// WithSPDXNamespaceSuffix replaces the random UUID of namespaces generated
+// from a base URI with a fixed suffix, for reproducible documents
+func WithSPDXNamespaceSuffix(suffix string) Option {
+	return func(w *Writer) {
+		w.Options.SPDXNamespaceSuffix = suffix
+	}
+}
+
+// WithDocumentName sets the name of the written documents, overriding the
+// name in the document metadata
+func WithDocumentName(name string) Option {
+	return func(w *Writer) {
+		w.Options.DocumentName = name
+	}
+}
+
+// WithSortComponents makes the serializers sort the components and
+// relationships in the output for stable, diffable documents. The
+// document being written is not modified.
+func WithSortComponents() Option {
+	return func(w *Writer) {
+		w.Options.SortComponents = true
+	}
+}
+
+// WithProgress registers a function the serializers call every interval
+// nodes with the number of nodes written and the total. An interval of
+// zero or less uses options.DefaultProgressInterval.
+func WithProgress(interval int, fn options.ProgressFunc) Option {
+	return func(w *Writer) {
+		w.Options.Progress = fn
+		w.Options.ProgressInterval = interval
+	}
+}
+
+// WithScopeFilter drops the edges of the given types, and the nodes that
+// are only linked to the graph through them, from the written documents.
+// With no types, the dev, test and optional dependencies in
+// options.DefaultScopeFilter are dropped to write only the runtime graph.
+func WithScopeFilter(types ...sbom.Edge_Type) Option {
+	return func(w *Writer) {
+		if len(types) == 0 {
+			types = options.DefaultScopeFilter
+		}
+		w.Options.ExcludeEdgeTypes = append([]sbom.Edge_Type{}, types...)
+	}
+}
+
+// WithPruneUnreachable controls whether the nodes that cannot be reached
+// from the root elements are left out of the written documents, see
+// sbom.NodeList.PruneUnreachable
+func WithPruneUnreachable(prune bool) Option {
+	return func(w *Writer) {
+		w.Options.PruneUnreachable = prune
+	}
 }
 
 type Writer struct {
@@ -23,7 +138,17 @@ type Writer struct {
 	Options options.Options
 }
 
+// WriteStream serializes the document and writes it to wr. The stream is not
+// closed but when writing compressed documents, the compression footer is
+// flushed before returning.
 func (w *Writer) WriteStream(bom *sbom.Document, wr io.WriteCloser) error {
+	return w.WriteStreamContext(context.Background(), bom, wr)
+}
+
+// WriteStreamContext works as WriteStream but aborts the serialization and
+// returns the context error when ctx is canceled. Anything written to wr
+// before the cancellation is left as is.
+func (w *Writer) WriteStreamContext(ctx context.Context, bom *sbom.Document, wr io.WriteCloser) error {
 	if bom == nil {
 		return errors.New("unable to write sbom to stream, SBOM is nil")
 	}
@@ -35,18 +160,173 @@ func (w *Writer) WriteStream(bom *sbom.Document, wr io.WriteCloser) error {
 		return fmt.Errorf("getting serializer: %w", err)
 	}
 
-	if err := w.impl.SerializeSBOM(w.Options, serializer, bom, wr); err != nil {
-		return fmt.Errorf("serializing sbom: %w", err)
+	bom = w.filter(bom)
+	return w.compress(wr, func(cw io.Writer) error {
+		if err := w.impl.SerializeSBOM(ctx, w.Options, serializer, bom, cw); err != nil {
+			return fmt.Errorf("serializing sbom: %w", err)
+		}
+		return nil
+	})
+}
+
+// ToNative converts the document to the native object of the format in the
+// options (a *cyclonedx.BOM for CycloneDX, an *spdx.Document for SPDX) so
+// it can be modified before writing it with RenderNative.
+func (w *Writer) ToNative(bom *sbom.Document) (interface{}, error) {
+	if bom == nil {
+		return nil, errors.New("unable to serialize sbom, SBOM is nil")
 	}
 
-	return nil
+	serializer, err := w.impl.GetFormatSerializer(w.Options.Format)
+	if err != nil {
+		return nil, fmt.Errorf("getting serializer: %w", err)
+	}
+
+	doc, err := serializer.Serialize(w.Options, w.filter(bom))
+	if err != nil {
+		return nil, fmt.Errorf("serializing SBOM to native format: %w", err)
+	}
+	return doc, nil
 }
 
+// RenderNative writes a native document, as returned by ToNative, to wr in
+// the format and compression set in the options
+func (w *Writer) RenderNative(doc interface{}, wr io.Writer) error {
+	if doc == nil {
+		return errors.New("unable to render document, document is nil")
+	}
+
+	serializer, err := w.impl.GetFormatSerializer(w.Options.Format)
+	if err != nil {
+		return fmt.Errorf("getting serializer: %w", err)
+	}
+
+	return w.compress(wr, func(cw io.Writer) error {
+		if err := serializer.Render(w.Options, doc, cw); err != nil {
+			return fmt.Errorf("rendering native document: %w", err)
+		}
+		return nil
+	})
+}
+
+// filter returns the document to serialize after applying the filters in
+// the options. When a filter applies, a copy is returned so the caller's
+// document is not modified.
+func (w *Writer) filter(bom *sbom.Document) *sbom.Document {
+	if bom.NodeList == nil || (len(w.Options.ExcludeEdgeTypes) == 0 && !w.Options.PruneUnreachable) {
+		return bom
+	}
+
+	var nl *sbom.NodeList
+	if len(w.Options.ExcludeEdgeTypes) > 0 {
+		nl = bom.NodeList.ExcludeEdgeTypes(w.Options.ExcludeEdgeTypes...)
+	} else {
+		nl = bom.NodeList.Copy()
+	}
+	if w.Options.PruneUnreachable {
+		nl.PruneUnreachable()
+	}
+	return &sbom.Document{
+		Metadata: bom.Metadata,
+		NodeList: nl,
+	}
+}
+
+// compress calls fn with a writer that applies the compression in the
+// options to wr, flushing the compressed stream when fn returns
+func (w *Writer) compress(wr io.Writer, fn func(io.Writer) error) error {
+	switch w.Options.Compression {
+	case options.CompressionNone:
+		return fn(wr)
+	case options.CompressionGzip:
+		gz := gzip.NewWriter(wr)
+		if err := fn(gz); err != nil {
+			gz.Close()
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("flushing compressed stream: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression %q", w.Options.Compression)
+	}
+}
+
+// WriteFile writes the document to the file at path, creating or truncating it
 func (w *Writer) WriteFile(bom *sbom.Document, path string) error {
 	f, err := w.impl.OpenFile(path)
 	if err != nil {
 		return err
 	}
 
-	return w.WriteStream(bom, f)
+	if err := w.WriteStream(bom, f); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+	return nil
+}
+
+// AppendToFile adds the nodes and edges of nl to the SBOM in the file at
+// path. The existing document is read, nl is merged into its NodeList with
+// NodeList.Add and the file is rewritten keeping the original metadata,
+// format and compression, the format in the writer options is ignored.
+// The document is serialized before the file is reopened for writing, so
+// the file is left untouched if the merged document cannot be rendered.
+func (w *Writer) AppendToFile(nl *sbom.NodeList, path string) error {
+	if nl == nil {
+		return errors.New("no nodelist to append")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading SBOM file: %w", err)
+	}
+
+	format, err := (&formats.Sniffer{}).SniffReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("detecting SBOM format: %w", err)
+	}
+	isGzip, err := formats.IsGzip(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("checking file compression: %w", err)
+	}
+
+	doc, err := reader.New().ParseStream(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("parsing SBOM file: %w", err)
+	}
+	if doc.NodeList == nil {
+		doc.NodeList = &sbom.NodeList{}
+	}
+	doc.NodeList.Add(nl)
+
+	fw := &Writer{impl: w.impl, Options: w.Options}
+	fw.Options.Format = format
+	fw.Options.Compression = options.CompressionNone
+	if isGzip {
+		fw.Options.Compression = options.CompressionGzip
+	}
+
+	var buf bytes.Buffer
+	if err := fw.WriteStream(doc, nopWriteCloser{&buf}); err != nil {
+		return fmt.Errorf("rendering merged document: %w", err)
+	}
+
+	f, err := w.impl.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("writing SBOM file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,513 @@
+package writer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/spdx/tools-golang/spdx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bom-squad/protobom/pkg/formats"
+	"github.com/bom-squad/protobom/pkg/reader"
+	"github.com/bom-squad/protobom/pkg/sbom"
+	"github.com/bom-squad/protobom/pkg/writer/options"
+)
+
+func TestWriteStreamGzip(t *testing.T) {
+	doc := twoRootDocument()
+	w := New(WithCompression(options.CompressionGzip))
+	w.Options.Format = formats.SPDX23JSON
+
+	var buf bytes.Buffer
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+
+	// The written stream must be a complete gzip stream
+	gz, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	_, err = io.ReadAll(gz)
+	require.NoError(t, err)
+
+	// And the reader must decompress it transparently
+	doc2, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, doc2.NodeList.Nodes, len(doc.NodeList.Nodes))
+
+	w.Options.Compression = "bogus"
+	require.Error(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sbom.spdx.json.gz")
+	w := New(WithCompression(options.CompressionGzip))
+	w.Options.Format = formats.SPDX23JSON
+	require.NoError(t, w.WriteFile(twoRootDocument(), path))
+
+	// Overwriting an existing file truncates it
+	require.NoError(t, w.WriteFile(twoRootDocument(), path))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	doc, err := reader.New().ParseStream(f)
+	require.NoError(t, err)
+	require.Len(t, doc.NodeList.Nodes, 3)
+}
+
+func TestWriteTools(t *testing.T) {
+	doc := twoRootDocument()
+	doc.AddTool("scanner", "1.2.3", "Example Corp")
+
+	cdxDoc, err := (&SerializerCDX{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+	tools := cdxDoc.(*cdx.BOM).Metadata.Tools
+	require.NotNil(t, tools)
+	require.Equal(t, []cdx.Tool{{Vendor: "Example Corp", Name: "scanner", Version: "1.2.3"}}, *tools)
+
+	spdxDoc, err := (&SerializerSPDX23{}).Serialize(options.Default, doc)
+	require.NoError(t, err)
+	creators := []string{}
+	for _, c := range spdxDoc.(*spdx.Document).CreationInfo.Creators {
+		creators = append(creators, c.CreatorType+": "+c.Creator)
+	}
+	require.Contains(t, creators, "Tool: scanner-1.2.3")
+
+	// Tools survive a CycloneDX round trip
+	w := New()
+	var buf bytes.Buffer
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+	doc2, err := reader.New().ParseStream(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Len(t, doc2.Metadata.Tools, 1)
+	require.Equal(t, "Example Corp", doc2.Metadata.Tools[0].Vendor)
+
+	// SPDX tool strings are split back into name and version and
+	// protobom is not listed twice when an ingested document is written
+	w.Options.Format = formats.SPDX23JSON
+	buf.Reset()
+	require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}))
+	doc2, err = reader.New().ParseStream(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Len(t, doc2.Metadata.Tools, 2)
+	require.Equal(t, "scanner", doc2.Metadata.Tools[1].Name)
+	require.Equal(t, "1.2.3", doc2.Metadata.Tools[1].Version)
+
+	spdxDoc, err = (&SerializerSPDX23{}).Serialize(options.Default, doc2)
+	require.NoError(t, err)
+	require.Len(t, spdxDoc.(*spdx.Document).CreationInfo.Creators, 2)
+}
+
+func TestWriteStreamIndent(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_contains, From: "root1", To: []string{"dep"}},
+	}
+
+	var compact, indented bytes.Buffer
+	require.NoError(t, New(WithCompact()).WriteStream(doc, nopWriteCloser{&compact}))
+	require.NoError(t, New(WithIndent(3)).WriteStream(doc, nopWriteCloser{&indented}))
+
+	require.Equal(t, 1, strings.Count(compact.String(), "\n"))
+	require.Contains(t, indented.String(), "\n   \"bomFormat\"")
+
+	doc1, err := reader.New().ParseStream(bytes.NewReader(compact.Bytes()))
+	require.NoError(t, err)
+	doc2, err := reader.New().ParseStream(bytes.NewReader(indented.Bytes()))
+	require.NoError(t, err)
+	require.True(t, doc1.NodeList.Equal(doc2.NodeList))
+}
+
+func TestWriteSortComponents(t *testing.T) {
+	newDoc := func(reverse bool) *sbom.Document {
+		nodes := []*sbom.Node{
+			{Id: "app", Name: "app", Version: "1.0"},
+			{Id: "zlib", Name: "zlib", Identifiers: map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:apk/wolfi/zlib@1.3"}},
+			{Id: "curl", Name: "curl", Identifiers: map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:apk/wolfi/curl@8.1"}},
+			{Id: "bash", Name: "bash", Version: "5.2"},
+		}
+		if reverse {
+			for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+				nodes[i], nodes[j] = nodes[j], nodes[i]
+			}
+		}
+		return &sbom.Document{
+			Metadata: &sbom.Metadata{Id: "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"},
+			NodeList: &sbom.NodeList{
+				Nodes: nodes,
+				Edges: []*sbom.Edge{
+					{Type: sbom.Edge_dependsOn, From: "bash", To: []string{"app"}},
+				},
+				RootElements: []string{"app"},
+			},
+		}
+	}
+
+	w := New(WithSortComponents())
+	doc := newDoc(false)
+	native, err := (&SerializerCDX{}).Serialize(w.Options, doc)
+	require.NoError(t, err)
+	names := []string{}
+	for _, c := range *native.(*cdx.BOM).Components {
+		names = append(names, c.Name)
+	}
+	require.Equal(t, []string{"bash", "curl", "zlib"}, names)
+
+	// The document is not modified
+	require.Equal(t, "app", doc.NodeList.Nodes[0].Id)
+	to := []string{"curl", "bash"}
+	deps := []cdx.Dependency{{Ref: "zlib", Dependencies: &to}, {Ref: "bash"}}
+	sortDependencies(&deps)
+	require.Equal(t, "bash", deps[0].Ref)
+	require.Equal(t, []string{"bash", "curl"}, *deps[1].Dependencies)
+	require.Equal(t, []string{"curl", "bash"}, to)
+
+	// Node order does not change the output
+	var buf1, buf2 bytes.Buffer
+	require.NoError(t, w.WriteStream(newDoc(false), nopWriteCloser{&buf1}))
+	require.NoError(t, w.WriteStream(newDoc(true), nopWriteCloser{&buf2}))
+	require.Equal(t, buf1.String(), buf2.String())
+}
+
+func TestWriteStreamProgress(t *testing.T) {
+	doc := sbom.NewDocument()
+	for i := 0; i < 2500; i++ {
+		doc.NodeList.AddNode(&sbom.Node{Id: fmt.Sprintf("node-%d", i), Name: fmt.Sprintf("package-%d", i)})
+	}
+	doc.NodeList.RootElements = []string{"node-0"}
+
+	for _, tc := range []struct {
+		format formats.Format
+		expect []int
+	}{
+		{formats.CDX14JSON, []int{1000, 2000, 2500}},
+		{formats.SPDX23JSON, []int{1000, 2000, 2500}},
+		{formats.PROTOBOMNDJSON, []int{1000, 2000, 2500}},
+		{formats.PROTOBOMINVENTORYNDJSON, []int{1000, 2000, 2500}},
+		{formats.PROTOBOMJSON, []int{2500}},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			done := []int{}
+			w := New(WithProgress(0, func(d, total int) {
+				require.Equal(t, 2500, total)
+				done = append(done, d)
+			}))
+			w.Options.Format = tc.format
+			require.NoError(t, w.WriteStream(doc, nopWriteCloser{io.Discard}))
+			require.Equal(t, tc.expect, done)
+		})
+	}
+}
+
+func TestWriteStreamContext(t *testing.T) {
+	doc := sbom.NewDocument()
+	for i := 0; i < 5000; i++ {
+		doc.NodeList.AddNode(&sbom.Node{Id: fmt.Sprintf("node-%d", i), Name: fmt.Sprintf("package-%d", i)})
+	}
+	doc.NodeList.RootElements = []string{"node-0"}
+
+	for _, f := range []formats.Format{
+		formats.CDX14JSON, formats.SPDX23JSON, formats.PROTOBOMJSON, formats.PROTOBOMNDJSON, formats.PROTOBOMINVENTORYNDJSON,
+	} {
+		t.Run(string(f), func(t *testing.T) {
+			// An already canceled context fails right away
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			w := New()
+			w.Options.Format = f
+			require.ErrorIs(t, w.WriteStreamContext(ctx, doc, nopWriteCloser{io.Discard}), context.Canceled)
+
+			// The protobom JSON serializer encodes the document in one go
+			if f == formats.PROTOBOMJSON {
+				return
+			}
+
+			// Canceling while serializing stops the node loop
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+			last := 0
+			w = New(WithProgress(100, func(done, _ int) {
+				last = done
+				if done == 1000 {
+					cancel()
+				}
+			}))
+			w.Options.Format = f
+			require.ErrorIs(t, w.WriteStreamContext(ctx, doc, nopWriteCloser{io.Discard}), context.Canceled)
+			require.Less(t, last, 1100)
+		})
+	}
+}
+
+// plainSerializer is a serializer that does not implement ContextSerializer
+type plainSerializer struct{}
+
+func (plainSerializer) Serialize(_ options.Options, bom *sbom.Document) (interface{}, error) {
+	return bom.Metadata.Id, nil
+}
+
+func (plainSerializer) Render(_ options.Options, doc interface{}, wr io.Writer) error {
+	_, err := fmt.Fprint(wr, doc)
+	return err
+}
+
+func TestSerializeSBOMContext(t *testing.T) {
+	doc := sbom.NewDocument()
+	doc.Metadata.Id = "test"
+	for i := 0; i < 100; i++ {
+		doc.NodeList.AddNode(&sbom.Node{Id: fmt.Sprintf("node-%d", i), Name: fmt.Sprintf("package-%d", i)})
+	}
+	di := &defaultWriterImplementation{}
+
+	// Serializers without context support are still usable
+	var buf bytes.Buffer
+	require.NoError(t, di.SerializeSBOM(context.Background(), options.Default, plainSerializer{}, doc, &buf))
+	require.Equal(t, "test", buf.String())
+
+	// The redactor passes the context to the serializer it wraps
+	var _ ContextSerializer = &Redactor{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewRedactor(&SerializerSPDX23{}, RedactRules{}).SerializeContext(ctx, options.Default, doc)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = NewRedactor(plainSerializer{}, RedactRules{}).SerializeContext(ctx, options.Default, doc)
+	require.NoError(t, err)
+}
+
+func TestToNative(t *testing.T) {
+	doc := sbom.NewDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "app", Name: "app", Version: "1.0"})
+	doc.NodeList.RootElements = []string{"app"}
+
+	w := New()
+	w.Options.Format = formats.CDX14JSON
+	native, err := w.ToNative(doc)
+	require.NoError(t, err)
+
+	bom, ok := native.(*cdx.BOM)
+	require.True(t, ok)
+	bom.Metadata.Properties = &[]cdx.Property{{Name: "signature", Value: "abc"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, w.RenderNative(bom, &buf))
+	require.Contains(t, buf.String(), `"signature"`)
+
+	parsed, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, "abc", parsed.Metadata.Properties["signature"])
+
+	w.Options.Format = formats.SPDX23JSON
+	native, err = w.ToNative(doc)
+	require.NoError(t, err)
+	_, ok = native.(*spdx.Document)
+	require.True(t, ok)
+
+	_, err = w.ToNative(nil)
+	require.Error(t, err)
+	require.Error(t, w.RenderNative(nil, &buf))
+}
+
+func TestAppendToFile(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		format      formats.Format
+		compression options.Compression
+	}{
+		{"cyclonedx", formats.CDX14JSON, options.CompressionNone},
+		{"spdx gzip", formats.SPDX23JSON, options.CompressionGzip},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sbom.json")
+			doc := twoRootDocument()
+			doc.Metadata.Authors = []*sbom.Person{{Name: "John Doe", Email: "john@example.com"}}
+			doc.NodeList.Edges = []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "root1", To: []string{"dep"}},
+			}
+
+			w := New(WithCompression(tc.compression))
+			w.Options.Format = tc.format
+			require.NoError(t, w.WriteFile(doc, path))
+			orig, err := reader.New().ParseFile(path)
+			require.NoError(t, err)
+
+			// The writer format is ignored, the file keeps its own
+			w = New()
+			w.Options.Format = formats.PROTOBOMJSON
+			require.NoError(t, w.AppendToFile(&sbom.NodeList{
+				Nodes: []*sbom.Node{{Id: "extra", Name: "extra", PrimaryPurpose: "library"}},
+				Edges: []*sbom.Edge{{Type: sbom.Edge_dependsOn, From: "dep", To: []string{"extra"}}},
+			}, path))
+
+			f, err := os.Open(path)
+			require.NoError(t, err)
+			defer f.Close()
+			isGzip, err := formats.IsGzip(f)
+			require.NoError(t, err)
+			require.Equal(t, tc.compression == options.CompressionGzip, isGzip)
+			format, err := (&formats.Sniffer{}).SniffFile(path)
+			require.NoError(t, err)
+			require.Equal(t, tc.format, format)
+
+			appended, err := reader.New().ParseFile(path)
+			require.NoError(t, err)
+			require.Equal(t, orig.Metadata.Name, appended.Metadata.Name)
+			require.Equal(t, orig.Metadata.Authors, appended.Metadata.Authors)
+			require.NotNil(t, appended.NodeList.GetNodeByID("extra"))
+			require.Len(t, appended.NodeList.Nodes, len(orig.NodeList.Nodes)+1)
+
+			for _, e := range []struct{ from, to string }{{"root1", "dep"}, {"dep", "extra"}} {
+				edge := appended.NodeList.GetEdgeByType(e.from, sbom.Edge_dependsOn)
+				require.NotNil(t, edge, e.from)
+				require.Contains(t, edge.To, e.to)
+			}
+		})
+	}
+
+	require.Error(t, New().AppendToFile(&sbom.NodeList{}, filepath.Join(t.TempDir(), "missing.json")))
+}
+
+func TestWriteScopeFilter(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.RootElements = []string{"root1"}
+	doc.NodeList.AddNode(&sbom.Node{Id: "linter", Name: "linter", PrimaryPurpose: "library"})
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "root1", To: []string{"dep"}},
+		{Type: sbom.Edge_dependsOn, From: "linter", To: []string{"dep"}},
+		{Type: sbom.Edge_devDependency, From: "linter", To: []string{"root1"}},
+		{Type: sbom.Edge_testDependency, From: "root2", To: []string{"root1"}},
+	}
+
+	for _, f := range []formats.Format{formats.SPDX23JSON, formats.CDX14JSON} {
+		var buf bytes.Buffer
+		w := New(WithScopeFilter())
+		w.Options.Format = f
+		require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}), string(f))
+
+		written, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err, string(f))
+		require.NotNil(t, written.NodeList.GetNodeByID("dep"), string(f))
+		require.Nil(t, written.NodeList.GetNodeByID("linter"), string(f))
+		require.Nil(t, written.NodeList.GetNodeByID("root2"), string(f))
+		for _, e := range written.NodeList.Edges {
+			require.NotEqual(t, sbom.Edge_devDependency, e.Type, string(f))
+			require.NotEqual(t, sbom.Edge_testDependency, e.Type, string(f))
+		}
+	}
+
+	// The document being written is not modified
+	require.Len(t, doc.NodeList.Nodes, 4)
+	require.Len(t, doc.NodeList.Edges, 4)
+
+	// Only the types passed are excluded
+	w := New(WithScopeFilter(sbom.Edge_testDependency))
+	w.Options.Format = formats.SPDX23JSON
+	native, err := w.ToNative(doc)
+	require.NoError(t, err)
+	names := []string{}
+	for _, p := range native.(*spdx.Document).Packages {
+		names = append(names, p.PackageName)
+	}
+	require.ElementsMatch(t, []string{"root1", "dep", "linter"}, names)
+}
+
+func TestWritePruneUnreachable(t *testing.T) {
+	doc := twoRootDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "orphan", Name: "orphan", PrimaryPurpose: "library"})
+	doc.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "root1", To: []string{"dep"}},
+	}
+
+	for name, scheme := range map[string]options.CDXRootScheme{
+		"flat":    FlatRootScheme,
+		"virtual": VirtualRootScheme,
+	} {
+		for _, prune := range []bool{false, true} {
+			var buf bytes.Buffer
+			w := New(WithCDXRootScheme(scheme), WithPruneUnreachable(prune))
+			require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}), name)
+
+			written, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err, name)
+			for _, id := range []string{"root1", "root2", "dep"} {
+				require.NotNil(t, written.NodeList.GetNodeByID(id), "%s: %s", name, id)
+			}
+			require.Equal(t, !prune, written.NodeList.GetNodeByID("orphan") != nil, name)
+		}
+	}
+
+	// The document being written is not modified
+	require.NotNil(t, doc.NodeList.GetNodeByID("orphan"))
+}
+
+func TestWriteReadStrict(t *testing.T) {
+	doc := sbom.NewDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "app", Name: "app", Version: "1.0", PrimaryPurpose: "application"})
+	doc.NodeList.AddNode(&sbom.Node{
+		Id: "lib", Name: "lib", Version: "2.0", PrimaryPurpose: "library",
+		Identifiers: map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:generic/lib@2.0"},
+	})
+	doc.NodeList.AddNode(&sbom.Node{Id: "libdep", Name: "libdep", Version: "3.0", PrimaryPurpose: "library"})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "app", To: []string{"lib"}})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"libdep"}})
+	doc.NodeList.RootElements = []string{"app"}
+
+	var buf bytes.Buffer
+	require.NoError(t, New().WriteStream(doc, nopWriteCloser{&buf}))
+	require.Contains(t, buf.String(), `"dependencies"`)
+
+	// Our own output has no fields the strict reader does not capture
+	parsed, err := reader.New(reader.WithStrict()).ParseStream(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, parsed.NodeList.Nodes, 3)
+	require.Equal(t, []string{"libdep"}, parsed.NodeList.GetEdgeByType("lib", sbom.Edge_dependsOn).To)
+}
+
+func TestWriteExternalReferences(t *testing.T) {
+	doc := sbom.NewDocument()
+	doc.NodeList.AddNode(&sbom.Node{
+		Id: "app", Name: "app", Version: "1.0", PrimaryPurpose: "application",
+		ExternalReferences: []*sbom.ExternalReference{
+			{ReferenceType: sbom.ExternalReference_VCS, Url: "https://github.com/example/app"},
+			{ReferenceType: sbom.ExternalReference_ADVISORIES, Url: "https://example.com/advisories"},
+			{ReferenceType: sbom.ExternalReference_DOCUMENTATION, Url: "https://example.com/docs"},
+			{ReferenceType: sbom.ExternalReference_NPM, Url: "https://www.npmjs.com/package/app"},
+		},
+	})
+	doc.NodeList.AddNode(&sbom.Node{Id: "lib", Name: "lib", Version: "2.0", PrimaryPurpose: "library"})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "app", To: []string{"lib"}})
+	doc.NodeList.RootElements = []string{"app"}
+
+	for f, expected := range map[formats.Format][]sbom.ExternalReference_ExternalReferenceType{
+		formats.SPDX23JSON: {
+			sbom.ExternalReference_VCS, sbom.ExternalReference_ADVISORIES,
+			sbom.ExternalReference_DOCUMENTATION, sbom.ExternalReference_NPM,
+		},
+		// SPDX only types are degraded to other in CycloneDX
+		formats.CDX14JSON: {
+			sbom.ExternalReference_VCS, sbom.ExternalReference_ADVISORIES,
+			sbom.ExternalReference_DOCUMENTATION, sbom.ExternalReference_OTHER,
+		},
+	} {
+		var buf bytes.Buffer
+		w := New()
+		w.Options.Format = f
+		require.NoError(t, w.WriteStream(doc, nopWriteCloser{&buf}), string(f))
+
+		parsed, err := reader.New().ParseStream(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err, string(f))
+		app := parsed.NodeList.GetNodeByID("app")
+		require.NotNil(t, app, string(f))
+
+		types := []sbom.ExternalReference_ExternalReferenceType{}
+		for _, er := range app.ExternalReferences {
+			types = append(types, er.ReferenceType)
+		}
+		require.ElementsMatch(t, expected, types, string(f))
+	}
+}